@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/board"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/buildinfo"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/history"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+)
+
+// about 固件和设备信息
+type about struct {
+	profile *board.Profile
+	// 挥杆记录，未启用时为 nil
+	swings *history.Log
+}
+
+// total 返回累计挥杆次数，清除挥杆记录后不归零，未启用挥杆记录时返回 "-"
+func (a *about) total() string {
+	if a.swings == nil {
+		return "-"
+	}
+	return fmt.Sprint(a.swings.Total())
+}
+
+// freeHeap 返回空闲堆内存字节数
+func freeHeap() uint64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.HeapSys - ms.HeapInuse
+}
+
+// lines 返回适合在屏幕上显示的信息行
+func (a *about) lines() []string {
+	info := buildinfo.Get()
+	return []string{
+		"Ver " + info.Version,
+		"Git " + info.ShortCommit(),
+		"Built " + info.Built(),
+		"Board " + a.profile.Name,
+		"Up " + time.Since(bootTime).Round(time.Second).String(),
+		"Swings " + a.total(),
+		fmt.Sprintf("Heap %dKB", freeHeap()/1024),
+	}
+}
+
+// newNode 创建显示固件和设备信息的菜单节点
+func (a *about) newNode() menu.Node {
+	return menu.NewLinesNode("About", a.lines)
+}
+
+// command 创建输出固件和设备信息的串口命令，以便主机端记录设备运行的固件
+func (a *about) command() *menu.Command {
+	return &menu.Command{
+		Name: "about",
+		Run: func(args []string, w io.Writer) error {
+			if len(args) != 0 {
+				return menu.ErrUsage
+			}
+			info := buildinfo.Get()
+			built := "unknown"
+			if !info.BuildTime.IsZero() {
+				built = info.BuildTime.Format(time.RFC3339)
+			}
+			commit := info.Commit
+			if commit == "" {
+				commit = "unknown"
+			}
+			_, _ = fmt.Fprintf(w, "version=%s commit=%s built=%s board=%s uptime_s=%d swings=%s free_heap=%d\r\n",
+				info.Version, commit, built, a.profile.Name,
+				int64(time.Since(bootTime).Seconds()), a.total(), freeHeap())
+			return nil
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/calibration"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+)
+
+// calLog 距离校准日志
+var calLog = logging.New("cal")
+
+const (
+	// calibrationRegionSize 校准表存储区域大小
+	calibrationRegionSize = 4096
+	// calibrationSweepStep 校准时每次增加的速度
+	calibrationSweepStep = 10
+	// maxDistance 校准和目标距离的最大值（单位：码）
+	maxDistance = 400
+)
+
+// calibrationStore 保存在 Flash 中的距离校准表
+type calibrationStore struct {
+	region *storage.Region
+	table  calibration.Table
+}
+
+// newCalibrationStore 创建 *calibrationStore 并加载已保存的校准表
+func newCalibrationStore(region *storage.Region) *calibrationStore {
+	s := &calibrationStore{region: region}
+	data, err := region.Load()
+	switch {
+	case errors.Is(err, storage.ErrEmpty):
+	case err != nil:
+		calLog.Warnf("load calibration error: %v", err)
+	default:
+		if err := json.Unmarshal(data, &s.table); err != nil {
+			calLog.Warnf("decode calibration error: %v", err)
+			s.table = calibration.Table{}
+		}
+	}
+	return s
+}
+
+// save 保存校准表
+func (s *calibrationStore) save() error {
+	data, err := json.Marshal(&s.table)
+	if err != nil {
+		return err
+	}
+	return s.region.Save(data)
+}
+
+// newCalibrationNode 创建校准各球杆距离的菜单节点
+//
+// 对每个球杆，选择速度并挥杆，然后输入游戏中的距离，记录后速度自动增加一档，以便依次校准。
+func newCalibrationNode(clubs *golfclubs.GolfClubs, profiles *profileStore, store *calibrationStore) menu.Node {
+	node := &menu.BaseNode{NodeName: "Calibrate"}
+	node.AddChildren(menu.NewBackNode("Back"))
+	for i := range profiles.saved {
+		club := profiles.saved[i].Name
+
+		speed := menu.NewRangeValueNode("Speed", calibrationSweepStep, 1, 100, calibrationSweepStep, nil, nil)
+		distance := menu.NewRangeValueNode("Dist", 0, 0, maxDistance, 1, nil, func(node *menu.ValueNode) {
+			curve := store.table.Curve(club)
+			curve.Add(calibration.Point{Setting: uint8(speed.Value()), Distance: float32(node.Value())})
+			if err := store.save(); err != nil {
+				calLog.Errorf("save calibration error: %v", err)
+				return
+			}
+			calLog.Infof("%s: speed %d -> %d yd", club, speed.Value(), node.Value())
+			speed.NextN(1)
+		})
+
+		clubNode := &menu.BaseNode{NodeName: club}
+		clubNode.AddChildren(
+			menu.NewBackNode("Back"),
+			speed,
+			swingTrigger.Wrap(&menu.ActionNode{
+				BaseNode: menu.BaseNode{NodeName: "Swing"},
+				OnEnter: func(_ *menu.ActionNode) {
+					_ = swingProfile(clubs, profiles.saved[i].WithSpeed(uint8(speed.Value())))
+				},
+			}),
+			distance,
+			menu.NewLinesNode("Points", func() []string {
+				curve := store.table.Lookup(club)
+				if curve == nil {
+					return nil
+				}
+				lines := make([]string, len(curve.Points))
+				for j, p := range curve.Points {
+					lines[j] = fmt.Sprintf("%d%% %.0f", p.Setting, p.Distance)
+				}
+				return lines
+			}),
+			&menu.ActionNode{
+				BaseNode: menu.BaseNode{NodeName: "Clear"},
+				OnEnter: func(_ *menu.ActionNode) {
+					store.table.Curve(club).Points = nil
+					if err := store.save(); err != nil {
+						calLog.Errorf("save calibration error: %v", err)
+						return
+					}
+					speed.SetValue(calibrationSweepStep)
+					calLog.Infof("%s calibration cleared", club)
+				},
+			},
+		)
+		node.AddChildren(clubNode)
+	}
+	return node
+}
+
+// newTargetNode 创建按目标距离挥杆的菜单节点
+func newTargetNode(clubs *golfclubs.GolfClubs, profiles *profileStore, store *calibrationStore) menu.Node {
+	club := menu.NewRangeValueNode("Club", 0, 0, int32(len(profiles.saved)-1), 1, func(value int32) string {
+		return profiles.saved[value].Name
+	}, nil)
+	node := &menu.BaseNode{NodeName: "Target"}
+	node.AddChildren(
+		menu.NewBackNode("Back"),
+		club,
+		swingTrigger.Wrap(menu.NewRangeValueNode("Yards", 100, 0, maxDistance, 5, nil, func(node *menu.ValueNode) {
+			_ = swingDistance(clubs, profiles.saved[club.Value()], store, float32(node.Value()))
+		})),
+	)
+	return node
+}
+
+// swingDistance 根据校准曲线以 profile 挥杆参数挥出 distance 距离并记录日志
+func swingDistance(clubs *golfclubs.GolfClubs, profile golfclubs.SwingProfile, store *calibrationStore, distance float32) error {
+	curve := store.table.Lookup(profile.Name)
+	if curve == nil {
+		err := fmt.Errorf("%s not calibrated", profile.Name)
+		calLog.Errorf("target swing error: %v", err)
+		return err
+	}
+	speed, err := curve.Setting(distance)
+	if err != nil {
+		calLog.Errorf("target swing error: %v", err)
+		return err
+	}
+	calLog.Infof("%s %.0f yd: speed %d%%", profile.Name, distance, speed)
+	return swingProfile(clubs, profile.WithSpeed(speed))
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/course"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+)
+
+// courseLog 球场日志
+var courseLog = logging.New("course")
+
+// coursesRegionSize 球场数据库存储区域大小
+const coursesRegionSize = 16 * 1024
+
+// courseStore 保存在 Flash 中的球场数据库
+type courseStore struct {
+	region *storage.Region
+	db     *course.Database
+	// 数据库变化时调用
+	onChange func()
+}
+
+// newCourseStore 创建 *courseStore 并加载已保存的球场
+func newCourseStore(region *storage.Region) *courseStore {
+	s := &courseStore{region: region, db: &course.Database{}}
+	data, err := region.Load()
+	switch {
+	case errors.Is(err, storage.ErrEmpty):
+	case err != nil:
+		courseLog.Warnf("load courses error: %v", err)
+	default:
+		db, err := course.Unmarshal(data)
+		if err != nil {
+			courseLog.Warnf("decode courses error: %v", err)
+			break
+		}
+		s.db = db
+	}
+	return s
+}
+
+// save 保存球场数据库
+func (s *courseStore) save() error {
+	data, err := json.Marshal(s.db)
+	if err != nil {
+		return err
+	}
+	if err := s.region.Save(data); err != nil {
+		return err
+	}
+	if s.onChange != nil {
+		s.onChange()
+	}
+	return nil
+}
+
+// courseCommand 返回通过串口导入导出球场的命令
+//
+// 导入： course import <球场 JSON> ，导出： course export [<name>] ，不指定球场名时导出整个数据库。
+func courseCommand(store *courseStore) *menu.Command {
+	return &menu.Command{
+		Name:  "course",
+		Usage: "list | import <json> | export [<name>] | delete <name>",
+		// 保留 import 的 JSON 原文，其中字符串的空白不能被拆分
+		Raw: true,
+		Run: func(raw []string, w io.Writer) error {
+			if len(raw) == 0 {
+				return menu.ErrUsage
+			}
+			args := strings.Fields(raw[0])
+			switch args[0] {
+			case "list":
+				for _, c := range store.db.Courses {
+					_, _ = fmt.Fprintf(w, "%s %d\r\n", c.Name, len(c.Holes))
+				}
+				return nil
+			case "import":
+				if len(args) < 2 {
+					return menu.ErrUsage
+				}
+				var c course.Course
+				data := strings.TrimSpace(strings.TrimPrefix(raw[0], args[0]))
+				if err := json.Unmarshal([]byte(data), &c); err != nil {
+					return fmt.Errorf("decode course error: %w", err)
+				}
+				if err := store.db.Put(c); err != nil {
+					return err
+				}
+				return store.save()
+			case "export":
+				var v any = store.db
+				if len(args) == 2 {
+					c := store.db.Find(args[1])
+					if c == nil {
+						return fmt.Errorf("course %q not found", args[1])
+					}
+					v = c
+				} else if len(args) > 2 {
+					return menu.ErrUsage
+				}
+				data, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(w, "%s\r\n", data)
+				return nil
+			case "delete":
+				if len(args) != 2 {
+					return menu.ErrUsage
+				}
+				if !store.db.Delete(args[1]) {
+					return fmt.Errorf("course %q not found", args[1])
+				}
+				return store.save()
+			}
+			return menu.ErrUsage
+		},
+	}
+}
+
+// newPlayNode 创建逐杆打球场的菜单节点
+func newPlayNode(
+	clubs *golfclubs.GolfClubs,
+	store *courseStore,
+	profiles *profileStore,
+	putts *puttStore,
+	calibrations *calibrationStore,
+) menu.Node {
+	player := &course.Player{}
+
+	courseNode := menu.NewRangeValueNode("Course", 0, 0, 0, 1, func(value int32) string {
+		if int(value) >= len(store.db.Courses) {
+			return "-"
+		}
+		return store.db.Courses[value].Name
+	}, nil)
+	holeNode := menu.NewRangeValueNode("Hole", 0, 0, 0, 1, func(value int32) string {
+		if player.Course == nil || int(value) >= len(player.Course.Holes) {
+			return "-"
+		}
+		return strconv.Itoa(player.Course.Holes[value].Number)
+	}, func(node *menu.ValueNode) {
+		player.SetHole(int(node.Value()))
+	})
+
+	// selectCourse 选择第 i 个球场，从第一洞开始
+	selectCourse := func(i int) {
+		player.Course = nil
+		if i < len(store.db.Courses) {
+			player.Course = &store.db.Courses[i]
+		}
+		player.SetHole(0)
+		holeNode.Max = 0
+		if player.Course != nil {
+			holeNode.Max = int32(len(player.Course.Holes) - 1)
+		}
+		holeNode.SetValue(0)
+	}
+	// reload 球场数据库变化后重新选择球场
+	reload := func() {
+		courseNode.Max = max(int32(len(store.db.Courses)-1), 0)
+		courseNode.SetValue(courseNode.Value())
+		selectCourse(int(courseNode.Value()))
+	}
+	courseNode.OnEnter = func(node *menu.ValueNode) {
+		selectCourse(int(node.Value()))
+	}
+	store.onChange = reload
+	reload()
+
+	node := &menu.BaseNode{NodeName: "Play"}
+	node.AddChildren(
+		menu.NewBackNode("Back"),
+		swingTrigger.Wrap(&menu.ActionNode{
+			NodeName: func(_ *menu.ActionNode) string {
+				h := player.Hole()
+				n, shot := player.Shot()
+				if h == nil || shot == nil {
+					return "No shot"
+				}
+				return fmt.Sprintf("H%d S%d %s", h.Number, n+1, describeShot(shot))
+			},
+			OnEnter: func(_ *menu.ActionNode) {
+				_, shot := player.Shot()
+				if shot == nil {
+					return
+				}
+				if err := playShot(clubs, shot, profiles, putts, calibrations); err != nil {
+					return
+				}
+				player.Next()
+				holeNode.SetValue(int32(player.HoleIndex()))
+			},
+		}),
+		menu.NewLinesNode("Note", func() []string {
+			if _, shot := player.Shot(); shot != nil && shot.Note != "" {
+				return []string{shot.Note}
+			}
+			return nil
+		}),
+		&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Skip"},
+			OnEnter: func(_ *menu.ActionNode) {
+				player.Next()
+				holeNode.SetValue(int32(player.HoleIndex()))
+			},
+		},
+		holeNode,
+		courseNode,
+	)
+	return node
+}
+
+// describeShot 返回击球计划的简短描述
+func describeShot(shot *course.Shot) string {
+	switch {
+	case shot.Distance > 0 && shot.Club == puttCurveName:
+		return fmt.Sprintf("%s %.1fm", shot.Club, shot.Distance)
+	case shot.Distance > 0:
+		return fmt.Sprintf("%s %.0fyd", shot.Club, shot.Distance)
+	case shot.Club == puttCurveName:
+		return fmt.Sprintf("%s %ddeg", shot.Club, shot.Speed)
+	}
+	return fmt.Sprintf("%s %d%%", shot.Club, shot.Speed)
+}
+
+// playShot 按击球计划挥杆或推杆并记录日志
+func playShot(
+	clubs *golfclubs.GolfClubs,
+	shot *course.Shot,
+	profiles *profileStore,
+	putts *puttStore,
+	calibrations *calibrationStore,
+) error {
+	if shot.Club == puttCurveName {
+		if shot.Distance > 0 {
+			return puttDistance(clubs, putts.saved, calibrations, shot.Distance)
+		}
+		return putt(clubs, putts.saved.WithBackswing(uint16(shot.Speed)))
+	}
+
+	i := profiles.index(shot.Club)
+	if i < 0 {
+		err := fmt.Errorf("unknown club %q", shot.Club)
+		courseLog.Errorf("play shot error: %v", err)
+		return err
+	}
+	if shot.Distance > 0 {
+		return swingDistance(clubs, profiles.saved[i], calibrations, shot.Distance)
+	}
+	return swingProfile(clubs, profiles.saved[i].WithSpeed(shot.Speed))
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/board"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/display"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/power"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/selftest"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+)
+
+// selftestLog 自检日志
+var selftestLog = logging.New("selftest")
+
+const (
+	// jogAngle 电机点动检查转动的角度
+	jogAngle uint16 = 5
+	// buttonSamples 检查按键卡住的采样次数，间隔 buttonSampleInterval
+	buttonSamples = 20
+	// buttonSampleInterval 检查按键卡住的采样间隔
+	buttonSampleInterval = 10 * time.Millisecond
+)
+
+// namedRegion 有名字的设置存储区域
+type namedRegion struct {
+	name   string
+	region *storage.Region
+}
+
+// diagnostics 自检项和最近一次自检结果
+type diagnostics struct {
+	checks  []selftest.Check
+	results []selftest.Result
+}
+
+// fail 记录启动时初始化失败的部件，自检时作为失败项报告
+func (d *diagnostics) fail(name string, err error) {
+	d.checks = append(d.checks, selftest.Check{Name: name, Run: func() selftest.Result {
+		return selftest.Fail(err)
+	}})
+}
+
+// addChecks 添加自检项， bus 为 nil 时表示 I2C 总线未能配置
+func (d *diagnostics) addChecks(
+	clubs *golfclubs.GolfClubs,
+	p *board.Profile,
+	bus drivers.I2C,
+	displayErr error,
+	buttonPin machine.Pin,
+	regions []namedRegion,
+) {
+	d.checks = append(d.checks,
+		selftest.Check{Name: "disp", Run: func() selftest.Result {
+			switch {
+			case p.Display.Type == display.TypeNone:
+				return selftest.Skip("none")
+			case displayErr != nil:
+				return selftest.Fail(displayErr)
+			case !p.Display.Type.UsesI2C():
+				return selftest.Skip(p.Display.Type.String())
+			}
+			// 只发送控制字节，检查显示器是否应答
+			if err := bus.Tx(display.DefaultI2CAddress, []byte{0x00}, nil); err != nil {
+				return selftest.Fail(fmt.Errorf("no ack at 0x%02x: %w", display.DefaultI2CAddress, err))
+			}
+			return selftest.Pass("0x%02x", display.DefaultI2CAddress)
+		}},
+		selftest.Check{Name: "enc", Run: func() selftest.Result {
+			// 编码器针脚上拉，停在定位点时两相均为高电平
+			a, b := p.Encoder.APin.Machine().Get(), p.Encoder.BPin.Machine().Get()
+			if a && b {
+				return selftest.Pass("")
+			}
+			return selftest.Warn("A%d B%d", bit(a), bit(b))
+		}},
+		selftest.Check{Name: "btn", Run: func() selftest.Result {
+			// 按键低电平有效
+			return selftest.Stuck(buttonPin.Get, false, buttonSamples, buttonSampleInterval)
+		}},
+	)
+	for _, r := range regions {
+		d.checks = append(d.checks, selftest.Check{Name: r.name, Run: func() selftest.Result {
+			return selftest.Region(r.region)
+		}})
+	}
+	d.checks = append(d.checks,
+		selftest.Check{Name: "supply", Run: func() selftest.Result {
+			if supply == nil {
+				return selftest.Skip("none")
+			}
+			err := supply.Check()
+			v := supply.Voltage()
+			switch {
+			case errors.Is(err, power.ErrLowVoltage):
+				return selftest.Fail(err)
+			case supply.Level() == power.LevelLow:
+				return selftest.Warn("%.1fV", v)
+			}
+			return selftest.Pass("%.1fV %d%%", v, supply.Percent())
+		}},
+		selftest.Check{Name: "motor", Confirm: true, Run: func() selftest.Result {
+			if err := clubs.Jog(jogAngle); err != nil {
+				return selftest.Fail(err)
+			}
+			return selftest.Pass("jog %ddeg", jogAngle)
+		}},
+	)
+}
+
+// bit 将电平转换为 0 或 1
+func bit(level bool) int {
+	if level {
+		return 1
+	}
+	return 0
+}
+
+// run 执行自检并输出结果到日志， confirmed 为 true 时包括电机点动，返回失败项数
+func (d *diagnostics) run(confirmed bool) int {
+	d.results = selftest.Run(d.checks, confirmed)
+	for _, r := range d.results {
+		if r.Status == selftest.StatusFail {
+			selftestLog.Errorf("%s", r)
+		} else {
+			selftestLog.Infof("%s", r)
+		}
+	}
+	failed := selftest.Count(d.results, selftest.StatusFail)
+	selftestLog.Infof("done, %d failed", failed)
+	return failed
+}
+
+// lines 返回最近一次自检结果行
+func (d *diagnostics) lines() []string {
+	if d.results == nil {
+		return []string{"Not run"}
+	}
+	return selftest.Lines(d.results)
+}
+
+// newNode 创建诊断菜单节点
+func (d *diagnostics) newNode() menu.Node {
+	jog := &menu.BaseNode{NodeName: "Run+jog"}
+	jog.AddChildren(
+		menu.NewBackNode("Cancel"),
+		&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Confirm"},
+			OnEnter: func(_ *menu.ActionNode) {
+				d.run(true)
+			},
+		},
+	)
+	node := &menu.BaseNode{NodeName: "Diagnostics"}
+	node.AddChildren(
+		menu.NewBackNode("Back"),
+		&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Run"},
+			OnEnter: func(_ *menu.ActionNode) {
+				d.run(false)
+			},
+		},
+		jog,
+		menu.NewLinesNode("Results", d.lines),
+	)
+	return node
+}
+
+// newNotice 创建自检失败时显示结果的提示节点，确认后进入 next
+func (d *diagnostics) newNotice(next menu.Node) menu.Node {
+	return menu.NewNoticeNode("Self-test", d.lines, "Continue", func() menu.Node {
+		return next
+	})
+}
+
+// command 创建执行自检的串口命令
+func (d *diagnostics) command() *menu.Command {
+	return &menu.Command{
+		Name:  "selftest",
+		Usage: "[jog]",
+		Run: func(args []string, w io.Writer) error {
+			confirmed := false
+			switch {
+			case len(args) == 1 && args[0] == "jog":
+				confirmed = true
+			case len(args) != 0:
+				return menu.ErrUsage
+			}
+			if failed := d.run(confirmed); failed > 0 {
+				return fmt.Errorf("%d checks failed", failed)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/board"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/feedback"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+)
+
+// feedbackLog 蜂鸣器和状态灯日志
+var feedbackLog = logging.New("feedback")
+
+const (
+	// beepShort 倒计时每秒蜂鸣时长
+	beepShort = 50 * time.Millisecond
+	// beepLong 倒计时结束蜂鸣时长
+	beepLong = 300 * time.Millisecond
+)
+
+// indicator 蜂鸣器和状态灯的声光提示，没有蜂鸣器和状态灯时不输出
+var indicator = &feedback.Player{}
+
+// configureFeedback 配置蜂鸣器和状态灯，并根据挥杆触发和球杆状态播放提示
+func configureFeedback(clubs *golfclubs.GolfClubs, p *board.Profile) {
+	if p.BuzzerPin.Used() {
+		if p.BuzzerPWM {
+			tone, err := feedback.NewPWMTone(p.BuzzerPin.Machine())
+			if err != nil {
+				feedbackLog.Errorf("configure buzzer error: %v", err)
+			} else {
+				indicator.Tone = tone
+			}
+		} else {
+			indicator.Tone = feedback.NewPinTone(p.BuzzerPin.Machine())
+		}
+	}
+	switch {
+	case p.PixelPin.Used():
+		indicator.Light = feedback.NewPixelLight(p.PixelPin.Machine())
+	case p.LEDPin.Used():
+		indicator.Light = feedback.NewPinLight(p.LEDPin.Machine())
+	}
+
+	swingTrigger.OnTick = func(remaining int) {
+		if remaining == 0 {
+			indicator.Play(feedback.Beep(feedback.NoteC6, beepLong, feedback.ColorBlue))
+			return
+		}
+		indicator.Play(feedback.Beep(feedback.NoteA5, beepShort, feedback.ColorAmber))
+	}
+	swingTrigger.OnArm = func(armed bool) {
+		if armed {
+			indicator.SetBackground(feedback.Armed)
+			return
+		}
+		refreshStatus(clubs)
+	}
+	clubs.AddObserver(&statusObserver{clubs: clubs})
+	indicator.Start(0)
+}
+
+// refreshStatus 按球杆是否有锁定的故障更新背景提示
+func refreshStatus(clubs *golfclubs.GolfClubs) {
+	if clubs.Fault() != nil {
+		indicator.SetBackground(feedback.Fault)
+		return
+	}
+	indicator.SetBackground(nil)
+}
+
+// statusObserver 运动时更新背景提示， golfclubs.MotionObserver 的实现
+type statusObserver struct {
+	clubs *golfclubs.GolfClubs
+}
+
+var _ golfclubs.MotionObserver = (*statusObserver)(nil)
+
+// MotionStarted 提示正在挥杆
+func (o *statusObserver) MotionStarted(_ []golfclubs.Segment, _ uint32) {
+	indicator.SetBackground(feedback.Swinging)
+}
+
+// MotionFinished 按运动后的故障状态更新提示
+func (o *statusObserver) MotionFinished(_ error) {
+	refreshStatus(o.clubs)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"tinygo.org/x/drivers"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/board"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/history"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/imu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+)
+
+// imuLog IMU 日志
+var imuLog = logging.New("imu")
+
+// swingIMU 记录每次运动实测角速度的 IMU ，为 nil 时没有 IMU
+var swingIMU *imu.Monitor
+
+// configureIMU 配置球杆臂上的 IMU ，并观察球杆的每次运动
+func configureIMU(clubs *golfclubs.GolfClubs, cfg board.IMU, bus drivers.I2C) error {
+	if cfg.Type != board.IMUNone && bus == nil {
+		return errors.New("i2c bus not configured")
+	}
+	var sensor imu.Sensor
+	switch cfg.Type {
+	case board.IMUNone:
+		return nil
+	case board.IMUMPU6050:
+		s := &imu.MPU6050{Bus: bus, Address: cfg.Address}
+		if err := s.Configure(); err != nil {
+			return err
+		}
+		sensor = s
+	case board.IMUICM42688:
+		s := &imu.ICM42688{Bus: bus, Address: cfg.Address}
+		if err := s.Configure(); err != nil {
+			return err
+		}
+		sensor = s
+	}
+	swingIMU = &imu.Monitor{
+		Recorder: imu.Recorder{
+			Sensor: sensor,
+			Axis:   cfg.Axis,
+			Invert: cfg.Invert,
+		},
+	}
+	clubs.AddObserver(swingIMU)
+	imuLog.Infof("%s axis %s", cfg.Type, cfg.Axis)
+	return nil
+}
+
+// applyIMU 将最近一次运动的 IMU 比较结果记入挥杆记录 r
+func applyIMU(r *history.Record) {
+	if swingIMU == nil {
+		return
+	}
+	a := swingIMU.Last()
+	if a == nil {
+		return
+	}
+	r.PeakVelocity = uint16(max(a.PeakMeasured, 0))
+	if r.Fault != history.FaultNone {
+		return
+	}
+	switch {
+	case a.Slip:
+		r.Fault = history.FaultSlip
+	case a.Flex:
+		r.Fault = history.FaultFlex
+	}
+}
+
+// newIMUNode 创建显示最近一次运动 IMU 比较结果的菜单节点
+func newIMUNode(clubLength float32) menu.Node {
+	return menu.NewLinesNode("IMU", func() []string {
+		a := swingIMU.Last()
+		if a == nil {
+			return []string{"No data"}
+		}
+		lines := []string{
+			fmt.Sprintf("Peak %.0f/%.0f", a.PeakMeasured, a.PeakCommanded),
+			fmt.Sprintf("Lag %dms", a.Lag.Milliseconds()),
+			fmt.Sprintf("RMS %.0f", a.RMSError),
+			fmt.Sprintf("Travel %+.0f%%", a.TravelError*100),
+			fmt.Sprintf("Over %+.0f%%", a.Overshoot*100),
+		}
+		if clubLength > 0 {
+			lines = append(lines, fmt.Sprintf("Head %.1fm/s", a.ClubHeadSpeed(clubLength)))
+		}
+		if a.Slip {
+			lines = append(lines, "SLIP")
+		}
+		if a.Flex {
+			lines = append(lines, "FLEX")
+		}
+		return lines
+	})
+}
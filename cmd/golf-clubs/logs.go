@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"log"
+	"machine"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/tinyfont/proggy"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logbuf"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/textscreen"
+)
+
+// logSinks 日志输出
+type logSinks struct {
+	// 内存中的日志，在菜单中查看
+	buffer *logbuf.Buffer
+	// 串口，菜单启动后默认关闭，避免与串口菜单输出混在一起
+	serial *logging.WriterSink
+	// 屏幕，仅在启动期间显示，菜单启动后关闭
+	screen *logging.WriterSink
+}
+
+// configureLogging 配置日志同时输出到串口和内存，标准库 log 的输出也转发到日志
+func configureLogging() *logSinks {
+	l := &logSinks{
+		buffer: logbuf.New(logbuf.DefaultSize, nil),
+		serial: logging.NewWriterSink(machine.Serial, logging.LevelDebug),
+	}
+	logging.AddSink(logging.BufferSink{Buffer: l.buffer}, l.serial)
+	log.SetFlags(0)
+	log.SetOutput(logging.Writer("log"))
+	return l
+}
+
+// showOnScreen 启动期间日志同时显示在屏幕上
+func (l *logSinks) showOnScreen(disp drivers.Displayer) {
+	w, h := disp.Size()
+	l.screen = logging.NewWriterSink(textscreen.NewTextScreen(
+		disp, 0, 0, w, h,
+		&proggy.TinySZ8pt7b,
+		color.RGBA{R: 255, G: 255, B: 255, A: 255}, color.RGBA{A: 255},
+		0,
+	), logging.LevelInfo)
+	l.screen.Newline = "\n"
+	logging.AddSink(l.screen)
+}
+
+// menuStarted 菜单接管串口和屏幕，停止输出日志到串口和屏幕
+func (l *logSinks) menuStarted() {
+	l.serial.SetEnabled(false)
+	if l.screen != nil {
+		l.screen.SetEnabled(false)
+	}
+}
+
+// newNode 创建查看日志的菜单节点
+func (l *logSinks) newNode() menu.Node {
+	return menu.NewLinesNode("Logs", l.buffer.Lines)
+}
+
+// command 创建查看日志和开关串口日志输出的串口命令
+func (l *logSinks) command() *menu.Command {
+	return &menu.Command{
+		Name:  "log",
+		Usage: "[on | off]",
+		Run: func(args []string, w io.Writer) error {
+			if len(args) == 0 {
+				for _, line := range l.buffer.Lines() {
+					_, _ = fmt.Fprint(w, line+"\r\n")
+				}
+				return nil
+			}
+			if len(args) != 1 {
+				return menu.ErrUsage
+			}
+			switch args[0] {
+			case "on":
+				l.serial.SetEnabled(true)
+				return nil
+			case "off":
+				l.serial.SetEnabled(false)
+				return nil
+			}
+			return menu.ErrUsage
+		},
+	}
+}
@@ -3,55 +3,217 @@ package main
 import (
 	"context"
 	"fmt"
-	"image/color"
-	"log"
 	"machine"
 	"strconv"
 	"time"
 
-	"tinygo.org/x/drivers/sh1106"
+	"tinygo.org/x/drivers"
 	"tinygo.org/x/tinyfont/proggy"
 
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/board"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/buildinfo"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/display"
 	"github.com/yhlooo/ns-sports-golf-clubs/pkg/encoder"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/feedback"
 	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/history"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
 	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
 )
 
+// mainLog 启动和主菜单日志
+var mainLog = logging.New("main")
+
+// profile 开发板配置
+var profile = &board.Pico
+
 func main() {
+	disableMotor(profile)
 	time.Sleep(2 * time.Second)
 
+	// 日志同时输出到串口和内存，以便在菜单中查看
+	logs := configureLogging()
+	mainLog.Infof("firmware %s", buildinfo.Get())
+
+	// 检查开发板配置
+	if err := profile.Validate(); err != nil {
+		mainLog.Fatalf("invalid board profile: %v", err)
+	}
+	mainLog.Infof("board profile: %s", profile.Name)
+
+	// 以下部件初始化失败时记录为自检失败项，继续启动以便在诊断中查看和通过串口操作
+	diag := &diagnostics{}
+
 	// 初始化高尔夫球杆
 	clubs := golfclubs.New(
-		machine.GPIO2,
-		machine.GPIO3,
-		machine.GPIO4,
+		profile.Motor.StepPin.Machine(),
+		profile.Motor.DirPin.Machine(),
+		profile.Motor.EnPin.Machine(),
 	)
-	if err := clubs.Configure(golfclubs.Config{}); err != nil {
-		log.Fatalf("configure golf clubs error: %v", err)
+	if err := clubs.Configure(golfclubs.Config{
+		Driver: profile.Motor.Driver,
+		Power:  profile.Motor.Power,
+	}); err != nil {
+		// 未配置完成的球杆拒绝运动
+		mainLog.Errorf("configure golf clubs error: %v", err)
+		diag.fail("clubs", err)
+	}
+	watchdogReset := checkWatchdogReset(clubs)
+
+	// 初始化串口配置的驱动器
+	var tmc *golfclubs.TMC2209
+	if profile.Motor.UART.Used() {
+		var err error
+		if tmc, err = configureTMC(profile); err != nil {
+			mainLog.Errorf("configure tmc2209 error: %v", err)
+			diag.fail("tmc", err)
+		} else {
+			clubs.SetStallDetector(tmc)
+		}
+	}
+
+	// 初始化总线
+	// 总线配置失败时不使用依赖总线的部件
+	buses, err := profile.ConfigureBuses()
+	if err != nil {
+		mainLog.Errorf("configure buses error: %v", err)
+		diag.fail("bus", err)
+		buses = &board.Buses{}
+	}
+	var i2c drivers.I2C
+	if buses.I2C != nil {
+		i2c = buses.I2C
 	}
 
 	// 初始化显示器
-	i2c := machine.I2C1
-	if err := i2c.Configure(machine.I2CConfig{
-		Frequency: 400 * machine.KHz,
-		SDA:       machine.GPIO10,
-		SCL:       machine.GPIO11,
-	}); err != nil {
-		log.Fatalf("configure i2c error: %v", err)
+	// 显示器初始化失败时仍可通过串口操作，由自检报告
+	disp, displayErr := display.New(profile.DisplayConfig(buses))
+	if displayErr != nil {
+		mainLog.Errorf("configure display error: %v", displayErr)
+		disp = nil
+	} else {
+		logs.showOnScreen(disp)
+	}
+
+	// 初始化电机轴位置传感器
+	var shaftSensor golfclubs.PositionSensor
+	switch profile.ShaftSensor.Type {
+	case board.ShaftSensorQuadrature:
+		shaftEnc := &encoder.Encoder{
+			APin: profile.ShaftSensor.APin.Machine(),
+			BPin: profile.ShaftSensor.BPin.Machine(),
+		}
+		if err := shaftEnc.Configure(); err != nil {
+			mainLog.Errorf("configure shaft encoder error: %v, running open loop", err)
+			diag.fail("shaft", err)
+			break
+		}
+		shaftSensor = &golfclubs.CounterSensor{
+			Counter:         shaftEnc,
+			CountsPerCircle: profile.ShaftSensor.CountsPerCircle,
+		}
+	case board.ShaftSensorAS5600:
+		if i2c == nil {
+			mainLog.Errorf("as5600 requires i2c bus, running open loop")
+			break
+		}
+		as5600 := &golfclubs.AS5600{Bus: i2c}
+		if err := as5600.Configure(); err != nil {
+			mainLog.Errorf("configure as5600 error: %v, running open loop", err)
+		} else {
+			shaftSensor = as5600
+		}
+	}
+	if shaftSensor != nil {
+		if err := clubs.SetFeedback(&golfclubs.Feedback{
+			Sensor:    shaftSensor,
+			Tolerance: profile.ShaftSensor.Tolerance,
+			Invert:    profile.ShaftSensor.Invert,
+		}, profile.ShaftSensor.AutoRehome); err != nil {
+			mainLog.Errorf("configure position feedback error: %v, running open loop", err)
+			diag.fail("fb", err)
+		}
+	}
+
+	// 初始化 IMU
+	if err := configureIMU(clubs, profile.IMU, i2c); err != nil {
+		mainLog.Errorf("configure imu error: %v, swings will not be measured", err)
+	}
+
+	// 初始化电源电压检测
+	if err := configureSupply(clubs, profile.Supply); err != nil {
+		mainLog.Errorf("configure supply monitor error: %v, supply voltage will not be checked", err)
+	}
+
+	// 加载挥杆参数
+	// 存储区域按分配顺序排列，新的区域只能追加在最后
+	// 分配失败的区域为 nil ，对应设置使用默认值且不能保存，由自检报告
+	flash := &storage.Allocator{Device: machine.Flash}
+	allocate := func(size int64) *storage.Region {
+		region, err := flash.Allocate(size)
+		if err != nil {
+			mainLog.Errorf("allocate storage error: %v", err)
+			diag.fail("flash", err)
+		}
+		return region
+	}
+	profilesRegion := allocate(profilesRegionSize)
+	profiles := newProfileStore(profilesRegion)
+	trajectories, err := newTrajectoryStore(flash)
+	if err != nil {
+		mainLog.Errorf("allocate trajectories storage error: %v", err)
+		diag.fail("flash", err)
+	}
+	calibrationRegion := allocate(calibrationRegionSize)
+	calibrations := newCalibrationStore(calibrationRegion)
+	puttRegion := allocate(puttRegionSize)
+	putts := newPuttStore(puttRegion)
+	coefficientsRegion := allocate(coefficientsRegionSize)
+	coefficients := newCoefficientsStore(coefficientsRegion)
+	coursesRegion := allocate(coursesRegionSize)
+	courses := newCourseStore(coursesRegion)
+	historyRegion := allocate(historyRegionSize)
+	if swings, err = history.Open(historyRegion); err != nil {
+		mainLog.Errorf("open swing history error: %v", err)
 	}
-	display := sh1106.NewI2C(i2c)
-	display.Configure(sh1106.Config{Width: 128, Height: 32})
-	display.ClearDisplay()
+
+	// 初始化蜂鸣器和状态灯
+	configureFeedback(clubs, profile)
 
 	// 初始化编码器
-	machine.GPIO8.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	buttonPin := profile.Encoder.ButtonPin.Machine()
+	buttonPin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
 	enc := &encoder.Encoder{
-		APin: machine.GPIO6,
-		BPin: machine.GPIO7,
+		APin: profile.Encoder.APin.Machine(),
+		BPin: profile.Encoder.BPin.Machine(),
+	}
+	encErr := enc.Configure()
+	if encErr != nil {
+		// 仍可通过串口操作菜单
+		mainLog.Errorf("configure encoder error: %v", encErr)
+		diag.fail("enc", encErr)
+	}
+
+	// 开机自检，结果输出到串口，有失败项时在屏幕上显示
+	settingRegions := []namedRegion{
+		{name: "prof", region: profilesRegion},
+		{name: "cal", region: calibrationRegion},
+		{name: "putt", region: puttRegion},
+		{name: "coef", region: coefficientsRegion},
+		{name: "course", region: coursesRegion},
 	}
-	if err := enc.Configure(); err != nil {
-		log.Fatalf("configure encoder error: %v", err)
+	for i, r := range trajectories.regions {
+		settingRegions = append(settingRegions, namedRegion{name: fmt.Sprintf("traj%d", i+1), region: r})
 	}
+	diag.addChecks(clubs, profile, i2c, displayErr, buttonPin, settingRegions)
+	selfTestFailed := diag.run(false) > 0
+
+	// 初始化 USB HID 键盘
+	configureUSBHID(clubs)
+
+	// 初始化外部触发输入
+	triggerInputs, triggerClubNode := newExternalTriggers(clubs, profiles, profile.Triggers)
 
 	// 初始化菜单
 	settingsNode := &menu.BaseNode{NodeName: "Settings"}
@@ -61,17 +223,37 @@ func main() {
 			clubs.SetReverse(reverse)
 		}),
 	)
+	settingsNode.AddChildren(newTriggerSettingNodes()...)
+	settingsNode.AddChildren(newHoldTimeNode(clubs))
+	if len(profile.Triggers) > 0 {
+		settingsNode.AddChildren(triggerClubNode)
+	}
+	if clubs.CanHome() {
+		settingsNode.AddChildren(
+			&menu.ActionNode{
+				BaseNode: menu.BaseNode{NodeName: "Home"},
+				OnEnter: func(_ *menu.ActionNode) {
+					if err := clubs.Home(); err != nil {
+						mainLog.Errorf("home error: %v", err)
+						return
+					}
+					mainLog.Infof("home done")
+				},
+			},
+		)
+	}
+	if tmc != nil {
+		settingsNode.AddChildren(menu.NewLinesNode("Driver", tmc.StatusLines))
+	}
 	root := &menu.BaseNode{NodeName: "Root"}
 	root.AddChildren(
-		&menu.BaseNode{NodeName: "Driver"},
-		&menu.BaseNode{NodeName: "Spoon"},
-		&menu.BaseNode{NodeName: "3-Iron"},
-		&menu.BaseNode{NodeName: "5-Iron"},
-		&menu.BaseNode{NodeName: "7-Iron"},
-		&menu.BaseNode{NodeName: "9-Iron"},
-		&menu.BaseNode{NodeName: "Wedge"},
-		&menu.BaseNode{NodeName: "Putter"},
-		&menu.ValueNode{
+		newPlayNode(clubs, courses, profiles, putts, calibrations),
+		swingTrigger.NewRepeatNode("Repeat"),
+	)
+	root.AddChildren(newProfileNodes(clubs, profiles)...)
+	root.AddChildren(
+		newPutterNode(clubs, putts, calibrations),
+		swingTrigger.Wrap(&menu.ValueNode{
 			BaseNode: menu.BaseNode{NodeName: "Custom"},
 			FormatValue: func(value int32) string {
 				v := value % 100
@@ -87,38 +269,115 @@ func main() {
 					speed += 100
 				}
 				speed++
-				log.Printf("swing at %d speed", speed)
-				clubs.Swing(uint8(speed))
-				log.Printf("swing done")
+				p := golfclubs.DefaultSwingProfile.WithSpeed(uint8(speed))
+				p.Name = "Custom"
+				_ = swingProfile(clubs, p)
 			},
-		},
+		}),
+		newStatusNode(clubs),
+		newTargetNode(clubs, profiles, calibrations),
+		newPlanNode(clubs, profiles, calibrations, coefficients),
+		newTrajectoriesNode(clubs, trajectories),
+		newCalibrationNode(clubs, profiles, calibrations),
 		settingsNode,
+		diag.newNode(),
+		logs.newNode(),
+	)
+	if swings != nil {
+		root.AddChildren(newStatsNode(swings))
+	}
+	if swingIMU != nil {
+		root.AddChildren(newIMUNode(profile.IMU.ClubLength))
+	}
+	info := &about{profile: profile, swings: swings}
+	root.AddChildren(info.newNode())
+	// 倒计时需要较短的刷新间隔
+	m := &menu.Menu{RefreshInterval: 100 * time.Millisecond}
+	start := menu.Node(root)
+	if selfTestFailed {
+		start = diag.newNotice(start)
+	}
+	if watchdogReset {
+		// 确认后才能运动
+		start = newFaultNotice(clubs, start)
+	}
+	m.SetRoot(start)
+
+	// 串口命令
+	commands := &menu.Commands{}
+	commands.Register(
+		trajectoryCommand(clubs, trajectories),
+		coefficientsCommand(coefficients),
+		courseCommand(courses),
+		diag.command(),
+		logs.command(),
+		info.command(),
 	)
-	m := &menu.Menu{}
-	m.SetRoot(root)
+	if swings != nil {
+		commands.Register(statsCommand(swings))
+	}
 
-	serialUI := &menu.Serial{Serial: machine.Serial}
+	serialUI := &menu.Serial{Serial: machine.Serial, Commands: commands}
 	encoderUI := &menu.Encoder{
 		Encoder:   enc,
-		ButtonPin: machine.GPIO8,
-	}
-	displayUI := &menu.GraphicsDisplay{
-		Display:         &display,
-		Font:            &proggy.TinySZ8pt7b,
-		ForegroundColor: color.RGBA{R: 255, G: 255, B: 255, A: 255},
-		BackgroundColor: color.RGBA{A: 255},
-		PaddingLeft:     1,
-		PaddingTop:      -1,
-		PaddingBottom:   1,
-		Width:           40,
-		Height:          32,
-	}
-	m.AddOutputs(serialUI, displayUI)
-	m.AddInputs(serialUI, encoderUI)
+		ButtonPin: buttonPin,
+	}
+	m.AddOutputs(serialUI)
+	if disp != nil {
+		displayUI := &menu.GraphicsDisplay{
+			Display:       disp,
+			Font:          &proggy.TinySZ8pt7b,
+			PaddingLeft:   1,
+			PaddingTop:    -1,
+			PaddingBottom: 1,
+		}
+		if _, h := disp.Size(); h <= 32 {
+			// 矮屏只使用左侧区域
+			displayUI.Width = 40
+		}
+		if profile.Display.Type.IsColor() {
+			displayUI.SetTheme(menu.ThemeBlue)
+		} else {
+			displayUI.SetTheme(menu.ThemeMonochrome)
+		}
+		m.AddOutputs(displayUI)
+	}
+	m.AddInputs(serialUI)
+	if encErr == nil {
+		m.AddInputs(encoderUI)
+	}
+	m.AddInputs(triggerInputs...)
 
+	if watchdogReset {
+		refreshStatus(clubs)
+	} else {
+		indicator.Play(feedback.BootOK)
+	}
+	logs.menuStarted()
+	startWatchdog(clubs, m)
 	m.HandleInputs(context.Background())
 }
 
+// configureTMC 配置通过串口连接的 TMC2209 驱动器
+func configureTMC(p *board.Profile) (*golfclubs.TMC2209, error) {
+	uart, err := p.Motor.UART.Configure()
+	if err != nil {
+		return nil, fmt.Errorf("configure motor uart error: %w", err)
+	}
+	tmc := &golfclubs.TMC2209{
+		UART:    uart,
+		Address: p.Motor.TMCAddress,
+		Echo:    true,
+	}
+	cfg := p.Motor.TMC
+	cfg.Microsteps = p.Motor.Driver.EffectiveMicrosteps()
+	cfg.StallMinStepRate = uint32(golfclubs.StallMinRPM * float32(p.Motor.Driver.PulsesPerCircle()) / 60)
+	if err := tmc.Configure(cfg); err != nil {
+		return nil, err
+	}
+	return tmc, nil
+}
+
 func readLine(s machine.Serialer) string {
 	var line []byte
 	for {
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/board"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/feedback"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/history"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/power"
+)
+
+// supplyLog 电源电压检测日志
+var supplyLog = logging.New("supply")
+
+const (
+	// supplyInterval 电源电压采样间隔
+	supplyInterval = 500 * time.Millisecond
+	// holdTimeStep 设置保持转矩时长的步长
+	holdTimeStep = 500 * time.Millisecond
+	// maxHoldTime 可设置的最长保持转矩时长
+	maxHoldTime = 10 * time.Second
+)
+
+// supply 电机电源电压监测，为 nil 时不检测
+var supply *power.Monitor
+
+// configureSupply 配置电机电源电压检测，每次运动前检查电压，并在后台定期采样
+func configureSupply(clubs *golfclubs.GolfClubs, cfg board.Supply) error {
+	if !cfg.Pin.Used() {
+		return nil
+	}
+	adc, err := cfg.Configure()
+	if err != nil {
+		return err
+	}
+	supply = &power.Monitor{
+		Sampler: adc,
+		Config:  cfg.Config,
+		OnLevel: func(level power.Level, voltage float32) {
+			switch level {
+			case power.LevelOK:
+				supplyLog.Infof("ok: %.1fV", voltage)
+			case power.LevelLow:
+				supplyLog.Warnf("low: %.1fV, swings derated", voltage)
+				indicator.Play(feedback.LowBattery)
+			case power.LevelCritical:
+				supplyLog.Errorf("critical: %.1fV, swings refused", voltage)
+				indicator.Play(feedback.LowBattery)
+			}
+		},
+	}
+	supply.Update()
+	clubs.SetSupply(supply)
+	go func() {
+		for {
+			time.Sleep(supplyInterval)
+			supply.Update()
+		}
+	}()
+	supplyLog.Infof("%s %.1fV %d%%", cfg.Pin, supply.Voltage(), supply.Percent())
+	return nil
+}
+
+// applySupply 将电源电压记入挥杆记录 r
+func applySupply(r *history.Record) {
+	if supply == nil {
+		return
+	}
+	r.Voltage = uint16(supply.Voltage() * 1000)
+}
+
+// statusName 返回状态菜单项的名字，包括故障和电源状态
+func statusName(clubs *golfclubs.GolfClubs) string {
+	switch state, _ := clubs.State(); state {
+	case golfclubs.StateEStop:
+		return "E-STOP"
+	case golfclubs.StateFault:
+		return "FAULT"
+	}
+	if cooldown := clubs.Cooldown(); cooldown > 0 {
+		return fmt.Sprintf("COOL %ds", int(cooldown.Round(time.Second)/time.Second))
+	}
+	if supply == nil {
+		return "Status: OK"
+	}
+	v := supply.Voltage()
+	switch supply.Level() {
+	case power.LevelLow:
+		return fmt.Sprintf("LOW %.1fV", v)
+	case power.LevelCritical:
+		return fmt.Sprintf("LOW! %.1fV", v)
+	}
+	return fmt.Sprintf("OK %.1fV %d%%", v, supply.Percent())
+}
+
+// newHoldTimeNode 创建设置运动结束后保持转矩时长的菜单节点
+func newHoldTimeNode(clubs *golfclubs.GolfClubs) menu.Node {
+	cfg := clubs.PowerConfig()
+	val := int32(min(cfg.HoldTime, maxHoldTime) / holdTimeStep)
+	return menu.NewRangeValueNode("Hold", val, 0, int32(maxHoldTime/holdTimeStep), 1, func(value int32) string {
+		return strconv.FormatFloat((time.Duration(value)*holdTimeStep).Seconds(), 'f', 1, 64) + "s"
+	}, func(node *menu.ValueNode) {
+		cfg := clubs.PowerConfig()
+		cfg.HoldTime = time.Duration(node.Value()) * holdTimeStep
+		clubs.SetPowerConfig(cfg)
+	})
+}
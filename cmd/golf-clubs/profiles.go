@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+)
+
+// swingLog 挥杆参数日志
+var swingLog = logging.New("swing")
+
+// profilesRegionSize 挥杆参数存储区域大小
+const profilesRegionSize = 4096
+
+// profileStore 各球杆挥杆参数，持久化保存在 Flash 中
+type profileStore struct {
+	region *storage.Region
+	// 已保存的参数
+	saved []golfclubs.SwingProfile
+	// 正在编辑的参数
+	editing []golfclubs.SwingProfile
+}
+
+// newProfileStore 创建 *profileStore ，加载已保存的参数，没有保存的球杆使用默认参数
+func newProfileStore(region *storage.Region) *profileStore {
+	s := &profileStore{region: region, saved: golfclubs.DefaultClubProfiles()}
+
+	data, err := region.Load()
+	switch {
+	case errors.Is(err, storage.ErrEmpty):
+	case err != nil:
+		swingLog.Warnf("load swing profiles error: %v, using defaults", err)
+	default:
+		var profiles []golfclubs.SwingProfile
+		if err := json.Unmarshal(data, &profiles); err != nil {
+			swingLog.Warnf("decode swing profiles error: %v, using defaults", err)
+			break
+		}
+		for _, p := range profiles {
+			if err := p.Validate(); err != nil {
+				swingLog.Warnf("ignore swing profile %q: %v", p.Name, err)
+				continue
+			}
+			if i := s.index(p.Name); i >= 0 {
+				s.saved[i] = p
+			}
+		}
+	}
+
+	s.editing = append([]golfclubs.SwingProfile(nil), s.saved...)
+	return s
+}
+
+// index 返回名为 name 的参数序号，不存在时返回 -1
+func (s *profileStore) index(name string) int {
+	for i := range s.saved {
+		if s.saved[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// save 保存第 i 个球杆正在编辑的参数
+func (s *profileStore) save(i int) error {
+	profiles := append([]golfclubs.SwingProfile(nil), s.saved...)
+	profiles[i] = s.editing[i]
+	data, err := json.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	if err := s.region.Save(data); err != nil {
+		return err
+	}
+	s.saved = profiles
+	return nil
+}
+
+// revert 放弃第 i 个球杆正在编辑的参数
+func (s *profileStore) revert(i int) {
+	s.editing[i] = s.saved[i]
+}
+
+// newProfileNodes 为每个球杆创建编辑和试挥挥杆参数的菜单节点
+func newProfileNodes(clubs *golfclubs.GolfClubs, store *profileStore) []menu.Node {
+	nodes := make([]menu.Node, len(store.editing))
+	for i := range store.editing {
+		nodes[i] = newProfileNode(clubs, store, i)
+	}
+	return nodes
+}
+
+// newProfileNode 创建第 i 个球杆的菜单节点
+func newProfileNode(clubs *golfclubs.GolfClubs, store *profileStore, i int) menu.Node {
+	p := &store.editing[i]
+
+	backswing := menu.NewRangeValueNode(
+		"Backswing", int32(p.BackswingAngle),
+		int32(golfclubs.MinBackswingAngle), int32(golfclubs.MaxBackswingAngle), 5,
+		nil,
+		func(node *menu.ValueNode) { p.BackswingAngle = uint16(node.Value()) },
+	)
+	pause := menu.NewRangeValueNode(
+		"Pause", int32(p.Pause/time.Millisecond),
+		0, int32(golfclubs.MaxPause/time.Millisecond), 100,
+		formatMillis,
+		func(node *menu.ValueNode) { p.Pause = time.Duration(node.Value()) * time.Millisecond },
+	)
+	speed := menu.NewRangeValueNode(
+		"Speed", int32(p.PeakSpeedPercent),
+		int32(golfclubs.MinPeakSpeedPercent), int32(golfclubs.MaxPeakSpeedPercent), 1,
+		nil,
+		func(node *menu.ValueNode) { p.PeakSpeedPercent = uint8(node.Value()) },
+	)
+	accel := menu.NewRangeValueNode(
+		"Accel", int32(p.Acceleration),
+		int32(golfclubs.MinAcceleration), int32(golfclubs.MaxAcceleration), 500,
+		nil,
+		func(node *menu.ValueNode) { p.Acceleration = uint32(node.Value()) },
+	)
+	follow := menu.NewRangeValueNode(
+		"Follow", int32(p.FollowThroughAngle),
+		int32(golfclubs.MinFollowThroughAngle), int32(golfclubs.MaxFollowThroughAngle), 5,
+		nil,
+		func(node *menu.ValueNode) { p.FollowThroughAngle = uint16(node.Value()) },
+	)
+
+	node := &menu.BaseNode{NodeName: p.Name}
+	node.AddChildren(
+		menu.NewBackNode("Back"),
+		swingTrigger.Wrap(&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Swing"},
+			OnEnter: func(_ *menu.ActionNode) {
+				_ = swingProfile(clubs, store.saved[i])
+			},
+		}),
+		backswing, pause, speed, accel, follow,
+		swingTrigger.Wrap(&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Test swing"},
+			OnEnter: func(_ *menu.ActionNode) {
+				_ = swingProfile(clubs, *p)
+			},
+		}),
+		&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Save"},
+			OnEnter: func(_ *menu.ActionNode) {
+				if err := store.save(i); err != nil {
+					swingLog.Errorf("save swing profile %q error: %v", p.Name, err)
+					return
+				}
+				swingLog.Infof("swing profile %q saved", p.Name)
+			},
+		},
+		&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Revert"},
+			OnEnter: func(_ *menu.ActionNode) {
+				store.revert(i)
+				backswing.SetValue(int32(p.BackswingAngle))
+				pause.SetValue(int32(p.Pause / time.Millisecond))
+				speed.SetValue(int32(p.PeakSpeedPercent))
+				accel.SetValue(int32(p.Acceleration))
+				follow.SetValue(int32(p.FollowThroughAngle))
+				swingLog.Infof("swing profile %q reverted", p.Name)
+			},
+		},
+	)
+	return node
+}
+
+// swingProfile 按挥杆参数挥杆并记录日志
+func swingProfile(clubs *golfclubs.GolfClubs, p golfclubs.SwingProfile) error {
+	swingLog.Infof("swing %s: %s", p.Name, describeProfile(p))
+	err := clubs.SwingProfile(p)
+	recordSwing(clubs, p.Name, p.PeakSpeedPercent, err)
+	if err != nil {
+		swingLog.Errorf("swing error: %v", err)
+		return err
+	}
+	swingLog.Infof("swing done")
+	return nil
+}
+
+// describeProfile 返回挥杆参数的简短描述
+func describeProfile(p golfclubs.SwingProfile) string {
+	return fmt.Sprintf("back %d pause %s speed %d%% accel %d follow %d",
+		p.BackswingAngle, p.Pause, p.PeakSpeedPercent, p.Acceleration, p.FollowThroughAngle)
+}
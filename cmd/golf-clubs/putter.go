@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/calibration"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+)
+
+// puttLog 推杆日志
+var puttLog = logging.New("putt")
+
+const (
+	// puttRegionSize 推杆参数存储区域大小
+	puttRegionSize = 4096
+	// puttCurveName 推杆距离校准曲线名，距离单位为米
+	puttCurveName = "Putter"
+	// puttCalibrationStep 推杆校准时每次增加的后摆角度
+	puttCalibrationStep = 4
+	// maxPuttDecimetres 推杆校准和目标距离的最大值（单位：分米）
+	maxPuttDecimetres = 300
+)
+
+// puttStore 保存在 Flash 中的推杆参数
+type puttStore struct {
+	region  *storage.Region
+	saved   golfclubs.PuttProfile
+	editing golfclubs.PuttProfile
+}
+
+// newPuttStore 创建 *puttStore 并加载已保存的推杆参数
+func newPuttStore(region *storage.Region) *puttStore {
+	s := &puttStore{region: region, saved: golfclubs.DefaultPuttProfile}
+	data, err := region.Load()
+	switch {
+	case errors.Is(err, storage.ErrEmpty):
+	case err != nil:
+		puttLog.Warnf("load putt profile error: %v, using defaults", err)
+	default:
+		var p golfclubs.PuttProfile
+		if err := json.Unmarshal(data, &p); err != nil {
+			puttLog.Warnf("decode putt profile error: %v, using defaults", err)
+		} else if err := p.Validate(); err != nil {
+			puttLog.Warnf("ignore putt profile: %v", err)
+		} else {
+			s.saved = p
+		}
+	}
+	s.editing = s.saved
+	return s
+}
+
+// save 保存正在编辑的推杆参数
+func (s *puttStore) save() error {
+	data, err := json.Marshal(&s.editing)
+	if err != nil {
+		return err
+	}
+	if err := s.region.Save(data); err != nil {
+		return err
+	}
+	s.saved = s.editing
+	return nil
+}
+
+// newPutterNode 创建推杆菜单节点
+func newPutterNode(clubs *golfclubs.GolfClubs, store *puttStore, calibrations *calibrationStore) menu.Node {
+	p := &store.editing
+
+	backswing := menu.NewRangeValueNode(
+		"Backswing", int32(p.BackswingAngle),
+		int32(golfclubs.MinPuttBackswingAngle), int32(golfclubs.MaxPuttBackswingAngle), 1,
+		nil,
+		func(node *menu.ValueNode) { p.BackswingAngle = uint16(node.Value()) },
+	)
+	tempo := menu.NewRangeValueNode(
+		"Tempo", int32(p.Tempo/time.Millisecond),
+		int32(golfclubs.MinPuttTempo/time.Millisecond), int32(golfclubs.MaxPuttTempo/time.Millisecond), 50,
+		formatMillis,
+		func(node *menu.ValueNode) { p.Tempo = time.Duration(node.Value()) * time.Millisecond },
+	)
+	pause := menu.NewRangeValueNode(
+		"Pause", int32(p.Pause/time.Millisecond),
+		0, int32(golfclubs.MaxPause/time.Millisecond), 100,
+		formatMillis,
+		func(node *menu.ValueNode) { p.Pause = time.Duration(node.Value()) * time.Millisecond },
+	)
+
+	node := &menu.BaseNode{NodeName: "Putter"}
+	node.AddChildren(
+		menu.NewBackNode("Back"),
+		swingTrigger.Wrap(&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Putt"},
+			OnEnter: func(_ *menu.ActionNode) {
+				_ = putt(clubs, store.saved)
+			},
+		}),
+		swingTrigger.Wrap(menu.NewRangeValueNode("Metres", 30, 1, maxPuttDecimetres, 1, formatDecimetres, func(node *menu.ValueNode) {
+			_ = puttDistance(clubs, store.saved, calibrations, float32(node.Value())/10)
+		})),
+		backswing, tempo, pause,
+		swingTrigger.Wrap(&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Test putt"},
+			OnEnter: func(_ *menu.ActionNode) {
+				_ = putt(clubs, *p)
+			},
+		}),
+		&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Save"},
+			OnEnter: func(_ *menu.ActionNode) {
+				if err := store.save(); err != nil {
+					puttLog.Errorf("save putt profile error: %v", err)
+					return
+				}
+				puttLog.Infof("putt profile saved")
+			},
+		},
+		&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Revert"},
+			OnEnter: func(_ *menu.ActionNode) {
+				store.editing = store.saved
+				backswing.SetValue(int32(p.BackswingAngle))
+				tempo.SetValue(int32(p.Tempo / time.Millisecond))
+				pause.SetValue(int32(p.Pause / time.Millisecond))
+				puttLog.Infof("putt profile reverted")
+			},
+		},
+		newPuttCalibrationNode(clubs, store, calibrations),
+	)
+	return node
+}
+
+// newPuttCalibrationNode 创建校准推杆距离的菜单节点
+//
+// 选择后摆角度并推杆，然后输入游戏中的距离（米），记录后后摆角度自动增加一档。
+func newPuttCalibrationNode(clubs *golfclubs.GolfClubs, store *puttStore, calibrations *calibrationStore) menu.Node {
+	backswing := menu.NewRangeValueNode(
+		"Backswing", puttCalibrationStep,
+		int32(golfclubs.MinPuttBackswingAngle), int32(golfclubs.MaxPuttBackswingAngle), puttCalibrationStep,
+		nil, nil,
+	)
+	node := &menu.BaseNode{NodeName: "Calibrate"}
+	node.AddChildren(
+		menu.NewBackNode("Back"),
+		backswing,
+		swingTrigger.Wrap(&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Putt"},
+			OnEnter: func(_ *menu.ActionNode) {
+				_ = putt(clubs, store.saved.WithBackswing(uint16(backswing.Value())))
+			},
+		}),
+		menu.NewRangeValueNode("Dist", 0, 0, maxPuttDecimetres, 1, formatDecimetres, func(node *menu.ValueNode) {
+			curve := calibrations.table.Curve(puttCurveName)
+			curve.Add(calibration.Point{Setting: uint8(backswing.Value()), Distance: float32(node.Value()) / 10})
+			if err := calibrations.save(); err != nil {
+				puttLog.Errorf("save calibration error: %v", err)
+				return
+			}
+			puttLog.Infof("backswing %d -> %s", backswing.Value(), formatDecimetres(node.Value()))
+			backswing.NextN(1)
+		}),
+		menu.NewLinesNode("Points", func() []string {
+			curve := calibrations.table.Lookup(puttCurveName)
+			if curve == nil {
+				return nil
+			}
+			lines := make([]string, len(curve.Points))
+			for i, p := range curve.Points {
+				lines[i] = fmt.Sprintf("%d %.1fm", p.Setting, p.Distance)
+			}
+			return lines
+		}),
+		&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Clear"},
+			OnEnter: func(_ *menu.ActionNode) {
+				calibrations.table.Curve(puttCurveName).Points = nil
+				if err := calibrations.save(); err != nil {
+					puttLog.Errorf("save calibration error: %v", err)
+					return
+				}
+				backswing.SetValue(puttCalibrationStep)
+				puttLog.Infof("putt calibration cleared")
+			},
+		},
+	)
+	return node
+}
+
+// putt 按推杆参数推杆并记录日志
+func putt(clubs *golfclubs.GolfClubs, p golfclubs.PuttProfile) error {
+	puttLog.Infof("back %d tempo %s pause %s", p.BackswingAngle, p.Tempo, p.Pause)
+	err := clubs.Putt(p)
+	recordSwing(clubs, puttCurveName, 0, err)
+	if err != nil {
+		puttLog.Errorf("putt error: %v", err)
+		return err
+	}
+	puttLog.Infof("putt done")
+	return nil
+}
+
+// puttDistance 根据推杆校准曲线推出 metres 米并记录日志
+func puttDistance(clubs *golfclubs.GolfClubs, p golfclubs.PuttProfile, calibrations *calibrationStore, metres float32) error {
+	curve := calibrations.table.Lookup(puttCurveName)
+	if curve == nil {
+		err := errors.New("putter not calibrated")
+		puttLog.Errorf("target putt error: %v", err)
+		return err
+	}
+	angle, err := curve.Setting(metres)
+	if err != nil {
+		puttLog.Errorf("target putt error: %v", err)
+		return err
+	}
+	puttLog.Infof("putt %.1fm: backswing %d", metres, angle)
+	return putt(clubs, p.WithBackswing(uint16(angle)))
+}
+
+// formatMillis 将毫秒格式化为秒
+func formatMillis(value int32) string {
+	return strconv.FormatFloat(float64(value)/1000, 'f', 2, 64) + "s"
+}
+
+// formatDecimetres 将分米格式化为米
+func formatDecimetres(value int32) string {
+	return strconv.FormatFloat(float64(value)/10, 'f', 1, 64) + "m"
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"machine"
+	"strings"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/board"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+)
+
+// safetyLog 看门狗和故障日志
+var safetyLog = logging.New("safety")
+
+// watchdogTimeout 看门狗超时时长，菜单每个刷新间隔、球杆运动期间每 10ms 喂狗一次
+const watchdogTimeout = 2 * time.Second
+
+// disableMotor 开机后立即禁用电机
+//
+// 复位后 GPIO 为高阻输入，部分驱动器（如共阳极接法的 TB6600 ）此时处于使能状态，需尽早脱机。
+func disableMotor(p *board.Profile) {
+	en := p.Motor.EnPin.Machine()
+	en.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	en.Set(!p.Motor.Driver.EnableActiveHigh)
+}
+
+// checkWatchdogReset 上次由看门狗复位时锁定故障，返回是否由看门狗复位
+func checkWatchdogReset(clubs *golfclubs.GolfClubs) bool {
+	if !board.WatchdogReset() {
+		return false
+	}
+	clubs.Trip(golfclubs.ErrWatchdogReset)
+	return true
+}
+
+// startWatchdog 启动看门狗，由菜单主循环和球杆运动喂狗
+func startWatchdog(clubs *golfclubs.GolfClubs, m *menu.Menu) {
+	if err := machine.Watchdog.Configure(machine.WatchdogConfig{
+		TimeoutMillis: uint32(watchdogTimeout.Milliseconds()),
+	}); err != nil {
+		safetyLog.Errorf("configure watchdog error: %v", err)
+		return
+	}
+	clubs.SetWatchdog(machine.Watchdog)
+	m.Watchdog = machine.Watchdog
+	// 记录看门狗由程序启动，下次开机时据此区分烧录等有意的看门狗复位
+	board.MarkWatchdog()
+	if err := machine.Watchdog.Start(); err != nil {
+		safetyLog.Errorf("start watchdog error: %v", err)
+	}
+}
+
+// newFaultNotice 创建显示锁定故障的提示节点，确认并清除故障后进入 root
+func newFaultNotice(clubs *golfclubs.GolfClubs, root menu.Node) menu.Node {
+	return menu.NewNoticeNode("Fault", func() []string {
+		state, cause := clubs.State()
+		lines := []string{strings.ToUpper(state.String())}
+		if cause != nil {
+			// 按单词分行，适应窄屏
+			lines = append(lines, strings.Fields(cause.Error())...)
+		}
+		return append(lines, "Motor off")
+	}, "Ack", func() menu.Node {
+		if err := clubs.ClearFault(); err != nil {
+			safetyLog.Errorf("clear fault error: %v", err)
+			return nil
+		}
+		refreshStatus(clubs)
+		safetyLog.Infof("fault acknowledged")
+		return root
+	})
+}
+
+// newStatusNode 创建显示球杆状态的节点
+func newStatusNode(clubs *golfclubs.GolfClubs) menu.Node {
+	return &statusNode{
+		ActionNode: &menu.ActionNode{
+			NodeName: func(_ *menu.ActionNode) string {
+				return statusName(clubs)
+			},
+			OnEnter: func(_ *menu.ActionNode) {
+				if clubs.Fault() == nil {
+					return
+				}
+				var err error
+				if clubs.CanHome() {
+					err = clubs.Home()
+				} else {
+					err = clubs.ClearFault()
+				}
+				refreshStatus(clubs)
+				if err != nil {
+					safetyLog.Errorf("clear fault error: %v", err)
+					return
+				}
+				safetyLog.Infof("fault cleared")
+			},
+		},
+		clubs: clubs,
+	}
+}
+
+// statusNode 显示球杆状态的节点，进入时通过归位清除故障
+//
+// 急停不能通过归位清除，进入时切换到需确认的故障提示节点。
+type statusNode struct {
+	*menu.ActionNode
+	clubs *golfclubs.GolfClubs
+}
+
+// Enter 进入当前节点，急停时返回故障提示节点
+func (n *statusNode) Enter() menu.Node {
+	if state, _ := n.clubs.State(); state == golfclubs.StateEStop {
+		return newFaultNotice(n.clubs, n.Back())
+	}
+	return n.ActionNode.Enter()
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/shotplan"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+)
+
+// planLog 击球规划日志
+var planLog = logging.New("plan")
+
+const (
+	// coefficientsRegionSize 距离修正系数存储区域大小
+	coefficientsRegionSize = 4096
+	// planMaxSpeed 规划击球时优先使用的最大速度百分比
+	planMaxSpeed = 90
+)
+
+// coefficientsStore 保存在 Flash 中的距离修正系数
+type coefficientsStore struct {
+	region       *storage.Region
+	coefficients shotplan.Coefficients
+}
+
+// newCoefficientsStore 创建 *coefficientsStore 并加载已保存的系数
+func newCoefficientsStore(region *storage.Region) *coefficientsStore {
+	s := &coefficientsStore{region: region, coefficients: shotplan.DefaultCoefficients}
+	data, err := region.Load()
+	switch {
+	case errors.Is(err, storage.ErrEmpty):
+	case err != nil:
+		planLog.Warnf("load coefficients error: %v, using defaults", err)
+	default:
+		if err := json.Unmarshal(data, &s.coefficients); err != nil {
+			planLog.Warnf("decode coefficients error: %v, using defaults", err)
+			s.coefficients = shotplan.DefaultCoefficients
+		}
+	}
+	return s
+}
+
+// save 保存系数
+func (s *coefficientsStore) save() error {
+	data, err := json.Marshal(&s.coefficients)
+	if err != nil {
+		return err
+	}
+	return s.region.Save(data)
+}
+
+// coefficientsCommand 返回通过串口查看和调整距离修正系数的命令
+func coefficientsCommand(store *coefficientsStore) *menu.Command {
+	return &menu.Command{
+		Name:  "coef",
+		Usage: "[headwind|tailwind|crosswind|elevation <value> | reset]",
+		Run: func(args []string, w io.Writer) error {
+			k := &store.coefficients
+			fields := []struct {
+				name  string
+				value *float32
+			}{
+				{name: "headwind", value: &k.Headwind},
+				{name: "tailwind", value: &k.Tailwind},
+				{name: "crosswind", value: &k.Crosswind},
+				{name: "elevation", value: &k.Elevation},
+			}
+			switch {
+			case len(args) == 0:
+				for _, f := range fields {
+					_, _ = fmt.Fprintf(w, "%s %g\r\n", f.name, *f.value)
+				}
+				return nil
+			case len(args) == 1 && args[0] == "reset":
+				*k = shotplan.DefaultCoefficients
+				return store.save()
+			case len(args) == 2:
+				v, err := strconv.ParseFloat(args[1], 32)
+				if err != nil {
+					return fmt.Errorf("invalid value %q: %w", args[1], err)
+				}
+				// 系数为负会使修正方向相反
+				if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+					return fmt.Errorf("invalid value %q: must be a non-negative number", args[1])
+				}
+				for _, f := range fields {
+					if f.name == args[0] {
+						*f.value = float32(v)
+						return store.save()
+					}
+				}
+			}
+			return menu.ErrUsage
+		},
+	}
+}
+
+// newPlanNode 创建根据风和高度差规划击球的菜单节点
+func newPlanNode(
+	clubs *golfclubs.GolfClubs,
+	profiles *profileStore,
+	calibrations *calibrationStore,
+	coefficients *coefficientsStore,
+) menu.Node {
+	distance := menu.NewRangeValueNode("Dist", 150, 0, maxDistance, 5, nil, nil)
+	wind := menu.NewRangeValueNode("Wind", 0, 0, 20, 1, func(value int32) string {
+		return strconv.Itoa(int(value)) + "m/s"
+	}, nil)
+	direction := menu.NewRangeValueNode("Dir", 0, 0, int32(len(shotplan.WindDirections)-1), 1, func(value int32) string {
+		return shotplan.WindDirections[value].String()
+	}, nil)
+	elevation := menu.NewRangeValueNode("Elev", 0, -50, 50, 1, func(value int32) string {
+		return strconv.Itoa(int(value)) + "m"
+	}, nil)
+
+	// plan 按当前输入规划击球
+	plan := func() (float32, shotplan.Choice, error) {
+		effective := coefficients.coefficients.EffectiveDistance(float32(distance.Value()), shotplan.Conditions{
+			Wind:          float32(wind.Value()),
+			WindDirection: shotplan.WindDirections[direction.Value()],
+			Elevation:     float32(elevation.Value()),
+		})
+		names := make([]string, len(profiles.saved))
+		for i := range profiles.saved {
+			names[i] = profiles.saved[i].Name
+		}
+		choice, err := shotplan.Choose(&calibrations.table, names, effective, planMaxSpeed)
+		return effective, choice, err
+	}
+
+	node := &menu.BaseNode{NodeName: "Plan"}
+	node.AddChildren(
+		menu.NewBackNode("Back"),
+		distance, wind, direction, elevation,
+		menu.NewLinesNode("Result", func() []string {
+			effective, choice, err := plan()
+			lines := []string{fmt.Sprintf("eff %.0fyd", effective)}
+			if err != nil {
+				return append(lines, "no club")
+			}
+			return append(lines, fmt.Sprintf("%s %d%%", choice.Club, choice.Speed))
+		}),
+		swingTrigger.Wrap(&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Swing"},
+			OnEnter: func(_ *menu.ActionNode) {
+				effective, choice, err := plan()
+				if err != nil {
+					planLog.Errorf("plan shot error: %v", err)
+					return
+				}
+				i := profiles.index(choice.Club)
+				planLog.Infof("plan %dyd -> %.0fyd: %s %d%%", distance.Value(), effective, choice.Club, choice.Speed)
+				_ = swingProfile(clubs, profiles.saved[i].WithSpeed(choice.Speed))
+			},
+		}),
+	)
+	return node
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/history"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/power"
+)
+
+// statsLog 挥杆记录日志
+var statsLog = logging.New("stats")
+
+const (
+	// historyRegionSize 挥杆记录存储区域大小，约 1000 条记录
+	historyRegionSize = 8 * 4096
+	// recentSwings 菜单中显示的最近挥杆记录数
+	recentSwings = 20
+)
+
+// bootTime 开机时间
+var bootTime = time.Now()
+
+// swings 挥杆记录，为 nil 时不记录
+var swings *history.Log
+
+// recordSwing 记录一次挥杆
+func recordSwing(clubs *golfclubs.GolfClubs, club string, speed uint8, err error) {
+	if swings == nil {
+		return
+	}
+	r := history.Record{
+		Uptime: time.Since(bootTime),
+		Club:   club,
+		Speed:  speed,
+		Fault:  faultOf(err),
+	}
+	// 被拒绝的运动没有开始转动，运动统计和 IMU 数据都属于上一次运动
+	if !errors.Is(err, golfclubs.ErrRefused) {
+		motion := clubs.LastMotion()
+		r.Duration = motion.Duration
+		r.Steps = motion.Steps
+		applyIMU(&r)
+	}
+	applySupply(&r)
+	if err := swings.Append(r); err != nil {
+		statsLog.Errorf("record swing error: %v", err)
+	}
+}
+
+// faultOf 返回错误对应的故障类型
+func faultOf(err error) history.Fault {
+	switch {
+	case err == nil:
+		return history.FaultNone
+	case errors.Is(err, golfclubs.ErrStalled):
+		return history.FaultStall
+	case errors.Is(err, golfclubs.ErrPositionMismatch):
+		return history.FaultPosition
+	case errors.Is(err, power.ErrLowVoltage):
+		return history.FaultLowVoltage
+	case errors.Is(err, golfclubs.ErrCooldown):
+		return history.FaultCooldown
+	}
+	return history.FaultOther
+}
+
+// newStatsNode 创建挥杆统计菜单节点
+func newStatsNode(l *history.Log) menu.Node {
+	node := &menu.BaseNode{NodeName: "Stats"}
+	node.AddChildren(
+		menu.NewBackNode("Back"),
+		menu.NewLinesNode("Totals", func() []string {
+			records := l.Records()
+			stats := history.Summarize(records)
+			lines := make([]string, 0, len(stats)+1)
+			lines = append(lines, fmt.Sprintf("All %d", len(records)))
+			for _, s := range stats {
+				line := fmt.Sprintf("%s %d", s.Club, s.Count)
+				if s.AverageSpeed > 0 {
+					line += fmt.Sprintf(" %.0f%%", s.AverageSpeed)
+				}
+				if s.Faults > 0 {
+					line += fmt.Sprintf(" F%d", s.Faults)
+				}
+				lines = append(lines, line)
+			}
+			return lines
+		}),
+		menu.NewLinesNode("Recent", func() []string {
+			records := l.Last(recentSwings)
+			lines := make([]string, len(records))
+			for i, r := range records {
+				lines[i] = fmt.Sprintf("%d %s %d%% %.1fs %s", r.Seq, r.Club, r.Speed, r.Duration.Seconds(), r.Fault)
+				if r.PeakVelocity > 0 {
+					lines[i] += fmt.Sprintf(" %ddps", r.PeakVelocity)
+				}
+				if r.Voltage > 0 {
+					lines[i] += fmt.Sprintf(" %.1fV", float32(r.Voltage)/1000)
+				}
+			}
+			return lines
+		}),
+		&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Clear"},
+			OnEnter: func(_ *menu.ActionNode) {
+				if err := l.Clear(); err != nil {
+					statsLog.Errorf("clear swing history error: %v", err)
+					return
+				}
+				statsLog.Infof("swing history cleared")
+			},
+		},
+	)
+	return node
+}
+
+// statsCommand 返回通过串口导出挥杆记录的命令
+func statsCommand(l *history.Log) *menu.Command {
+	return &menu.Command{
+		Name:  "stats",
+		Usage: "csv | summary | clear",
+		Run: func(args []string, w io.Writer) error {
+			if len(args) != 1 {
+				return menu.ErrUsage
+			}
+			switch args[0] {
+			case "csv":
+				return history.WriteCSV(w, l.Records())
+			case "summary":
+				for _, s := range history.Summarize(l.Records()) {
+					_, _ = fmt.Fprintf(w, "%s count=%d faults=%d avg_speed=%.1f steps=%d peak_dps=%d\r\n",
+						s.Club, s.Count, s.Faults, s.AverageSpeed, s.Steps, s.PeakVelocity)
+				}
+				return nil
+			case "clear":
+				return l.Clear()
+			}
+			return menu.ErrUsage
+		},
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+)
+
+// trajLog 轨迹录制和回放日志
+var trajLog = logging.New("traj")
+
+const (
+	// trajectorySlots 可保存的轨迹数
+	trajectorySlots = 4
+	// trajectoryRegionSize 每条轨迹的存储区域大小，约可保存 500 个采样点
+	trajectoryRegionSize = 4096
+	// maxRecordDuration 手动录制轨迹的最长时长
+	maxRecordDuration = 10 * time.Second
+)
+
+// trajectoryStore 保存在 Flash 中的轨迹
+type trajectoryStore struct {
+	regions []*storage.Region
+	slots   []*golfclubs.Trajectory
+
+	// 正在通过串口上传的轨迹
+	uploading *golfclubs.Trajectory
+	// 轨迹变化时调用
+	onChange func(slot int)
+}
+
+// newTrajectoryStore 创建 *trajectoryStore ，从 alloc 分配存储区域并加载已保存的轨迹
+//
+// 分配失败时仍返回可用的 *trajectoryStore 和第一个分配错误。
+func newTrajectoryStore(alloc *storage.Allocator) (*trajectoryStore, error) {
+	s := &trajectoryStore{}
+	var allocErr error
+	for i := 0; i < trajectorySlots; i++ {
+		region, err := alloc.Allocate(trajectoryRegionSize)
+		if err != nil {
+			// 分配失败的槽位不可保存，其余槽位仍可使用
+			if allocErr == nil {
+				allocErr = err
+			}
+			s.regions = append(s.regions, nil)
+			s.slots = append(s.slots, nil)
+			continue
+		}
+		s.regions = append(s.regions, region)
+
+		var t *golfclubs.Trajectory
+		data, err := region.Load()
+		switch {
+		case errors.Is(err, storage.ErrEmpty):
+		case err != nil:
+			trajLog.Warnf("load trajectory %d error: %v", i+1, err)
+		default:
+			t = &golfclubs.Trajectory{}
+			if err := t.UnmarshalBinary(data); err != nil {
+				trajLog.Warnf("decode trajectory %d error: %v", i+1, err)
+				t = nil
+			}
+		}
+		s.slots = append(s.slots, t)
+	}
+	return s, allocErr
+}
+
+// get 返回第 slot 条轨迹（从 0 开始），不存在时返回错误
+func (s *trajectoryStore) get(slot int) (*golfclubs.Trajectory, error) {
+	if slot < 0 || slot >= len(s.slots) {
+		return nil, fmt.Errorf("slot %d out of range [1, %d]", slot+1, len(s.slots))
+	}
+	if s.slots[slot] == nil {
+		return nil, fmt.Errorf("slot %d is empty", slot+1)
+	}
+	return s.slots[slot], nil
+}
+
+// save 将轨迹保存到第 slot 条
+func (s *trajectoryStore) save(slot int, t *golfclubs.Trajectory) error {
+	if slot < 0 || slot >= len(s.slots) {
+		return fmt.Errorf("slot %d out of range [1, %d]", slot+1, len(s.slots))
+	}
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := s.regions[slot].Save(data); err != nil {
+		return err
+	}
+	s.slots[slot] = t
+	s.changed(slot)
+	return nil
+}
+
+// clear 删除第 slot 条轨迹
+func (s *trajectoryStore) clear(slot int) error {
+	if slot < 0 || slot >= len(s.slots) {
+		return fmt.Errorf("slot %d out of range [1, %d]", slot+1, len(s.slots))
+	}
+	if err := s.regions[slot].Erase(); err != nil {
+		return err
+	}
+	s.slots[slot] = nil
+	s.changed(slot)
+	return nil
+}
+
+// changed 通知第 slot 条轨迹变化
+func (s *trajectoryStore) changed(slot int) {
+	if s.onChange != nil {
+		s.onChange(slot)
+	}
+}
+
+// slotName 返回第 slot 条轨迹在菜单中显示的名字
+func (s *trajectoryStore) slotName(slot int) string {
+	name := "-"
+	if t := s.slots[slot]; t != nil {
+		name = t.Name
+	}
+	return strconv.Itoa(slot+1) + ": " + name
+}
+
+// newTrajectoriesNode 创建回放和录制轨迹的菜单节点
+func newTrajectoriesNode(clubs *golfclubs.GolfClubs, store *trajectoryStore) menu.Node {
+	node := &menu.BaseNode{NodeName: "Tricks"}
+	node.AddChildren(menu.NewBackNode("Back"))
+
+	slotNodes := make([]*menu.BaseNode, len(store.slots))
+	for i := range store.slots {
+		slot := i
+		slotNode := &menu.BaseNode{NodeName: store.slotName(slot)}
+		slotNode.AddChildren(
+			menu.NewBackNode("Back"),
+			swingTrigger.Wrap(&menu.ActionNode{
+				BaseNode: menu.BaseNode{NodeName: "Play"},
+				OnEnter: func(_ *menu.ActionNode) {
+					playTrajectory(clubs, store, slot)
+				},
+			}),
+		)
+		if clubs.CanRecord() {
+			slotNode.AddChildren(&menu.ActionNode{
+				BaseNode: menu.BaseNode{NodeName: "Record"},
+				OnEnter: func(_ *menu.ActionNode) {
+					trajLog.Infof("recording, move the club by hand")
+					t, err := clubs.RecordTrajectory(maxRecordDuration)
+					if err != nil {
+						trajLog.Errorf("record trajectory error: %v", err)
+						return
+					}
+					t.Name = "rec" + strconv.Itoa(slot+1)
+					if err := store.save(slot, t); err != nil {
+						trajLog.Errorf("save trajectory error: %v", err)
+						return
+					}
+					trajLog.Infof("recorded %d samples in %s", len(t.Samples), t.Duration())
+				},
+			})
+		}
+		slotNode.AddChildren(&menu.ActionNode{
+			BaseNode: menu.BaseNode{NodeName: "Clear"},
+			OnEnter: func(_ *menu.ActionNode) {
+				if err := store.clear(slot); err != nil {
+					trajLog.Errorf("clear trajectory error: %v", err)
+				}
+			},
+		})
+		slotNodes[slot] = slotNode
+		node.AddChildren(slotNode)
+	}
+	store.onChange = func(slot int) {
+		slotNodes[slot].NodeName = store.slotName(slot)
+	}
+	return node
+}
+
+// playTrajectory 回放第 slot 条轨迹并记录日志
+func playTrajectory(clubs *golfclubs.GolfClubs, store *trajectoryStore, slot int) {
+	t, err := store.get(slot)
+	if err != nil {
+		trajLog.Errorf("play trajectory error: %v", err)
+		return
+	}
+	trajLog.Infof("play trajectory %s", t.Name)
+	err = clubs.PlayTrajectory(t, golfclubs.DefaultTrajectoryLimits)
+	recordSwing(clubs, t.Name, 0, err)
+	if err != nil {
+		trajLog.Errorf("play trajectory error: %v", err)
+		return
+	}
+	trajLog.Infof("play done")
+}
+
+// trajectoryCommand 返回通过串口管理轨迹的命令
+//
+// 上传轨迹： traj new <name> ，然后多次 traj add <ms> <deg> [<ms> <deg>...] ，最后 traj save <slot> 。
+func trajectoryCommand(clubs *golfclubs.GolfClubs, store *trajectoryStore) *menu.Command {
+	return &menu.Command{
+		Name:  "traj",
+		Usage: "list | new <name> | add <ms> <deg>... | save <slot> | play <slot> | dump <slot> | clear <slot>",
+		Run: func(args []string, w io.Writer) error {
+			if len(args) == 0 {
+				return menu.ErrUsage
+			}
+			sub, args := args[0], args[1:]
+
+			// 解析从 1 开始的轨迹序号
+			slotArg := func() (int, error) {
+				if len(args) != 1 {
+					return 0, menu.ErrUsage
+				}
+				slot, err := strconv.Atoi(args[0])
+				if err != nil {
+					return 0, menu.ErrUsage
+				}
+				return slot - 1, nil
+			}
+
+			switch sub {
+			case "list":
+				for i, t := range store.slots {
+					if t == nil {
+						_, _ = fmt.Fprintf(w, "%d -\r\n", i+1)
+						continue
+					}
+					_, _ = fmt.Fprintf(w, "%d %s %d %s\r\n", i+1, t.Name, len(t.Samples), t.Duration())
+				}
+			case "new":
+				if len(args) != 1 || len(args[0]) > golfclubs.MaxTrajectoryNameLength {
+					return menu.ErrUsage
+				}
+				store.uploading = &golfclubs.Trajectory{Name: args[0]}
+			case "add":
+				if store.uploading == nil {
+					return errors.New("no trajectory uploading, run traj new first")
+				}
+				if len(args) == 0 || len(args)%2 != 0 {
+					return menu.ErrUsage
+				}
+				for i := 0; i < len(args); i += 2 {
+					ms, err := strconv.ParseUint(args[i], 10, 32)
+					if err != nil {
+						return fmt.Errorf("invalid time %q: %w", args[i], err)
+					}
+					deg, err := strconv.ParseFloat(args[i+1], 32)
+					if err != nil {
+						return fmt.Errorf("invalid angle %q: %w", args[i+1], err)
+					}
+					store.uploading.Samples = append(store.uploading.Samples, golfclubs.TrajectorySample{
+						Time:  time.Duration(ms) * time.Millisecond,
+						Angle: float32(deg),
+					})
+				}
+			case "save":
+				slot, err := slotArg()
+				if err != nil {
+					return err
+				}
+				if store.uploading == nil {
+					return errors.New("no trajectory uploading, run traj new first")
+				}
+				if err := store.save(slot, store.uploading); err != nil {
+					return err
+				}
+				store.uploading = nil
+			case "play":
+				slot, err := slotArg()
+				if err != nil {
+					return err
+				}
+				t, err := store.get(slot)
+				if err != nil {
+					return err
+				}
+				err = clubs.PlayTrajectory(t, golfclubs.DefaultTrajectoryLimits)
+				recordSwing(clubs, t.Name, 0, err)
+				return err
+			case "dump":
+				slot, err := slotArg()
+				if err != nil {
+					return err
+				}
+				t, err := store.get(slot)
+				if err != nil {
+					return err
+				}
+				for _, s := range t.Samples {
+					_, _ = fmt.Fprintf(w, "%d %.2f\r\n", s.Time.Milliseconds(), s.Angle)
+				}
+			case "clear":
+				slot, err := slotArg()
+				if err != nil {
+					return err
+				}
+				return store.clear(slot)
+			default:
+				return menu.ErrUsage
+			}
+			return nil
+		},
+	}
+}
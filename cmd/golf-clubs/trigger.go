@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/board"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/usbhid"
+)
+
+// triggerLog 外部触发日志
+var triggerLog = logging.New("trigger")
+
+// maxCountdownSeconds 挥杆前倒计时最大秒数
+const maxCountdownSeconds = 10
+
+// swingTrigger 挥杆动作的触发方式，所有挥杆动作节点都用它包装
+var swingTrigger = &menu.Trigger{}
+
+// newTriggerSettingNodes 创建设置挥杆触发方式的菜单节点
+func newTriggerSettingNodes() []menu.Node {
+	return []menu.Node{
+		menu.NewRangeValueNode("Countdown", 0, 0, maxCountdownSeconds, 1, func(value int32) string {
+			return strconv.Itoa(int(value)) + "s"
+		}, func(node *menu.ValueNode) {
+			swingTrigger.Countdown = time.Duration(node.Value()) * time.Second
+		}),
+		menu.NewBoolValueNode("Arm", false, true, func(arm bool) {
+			swingTrigger.Arm = arm
+		}),
+	}
+}
+
+// newExternalTriggers 为开发板配置的外部触发输入创建菜单输入源，并返回选择外部触发挥杆所用球杆的菜单节点
+//
+// 主机通过 USB HID 输出报告触发的输入见 hidTriggerActions 。
+// 挥杆和重复上次动作经过菜单操作队列，按 swingTrigger 的倒计时执行（不需要两步确认），球杆运动时的触发被丢弃；
+// 急停直接在采样协程中执行。
+func newExternalTriggers(
+	clubs *golfclubs.GolfClubs,
+	profiles *profileStore,
+	triggers []board.Trigger,
+) ([]menu.UIInput, menu.Node) {
+	swings := make([]*menu.TriggerNode, len(profiles.saved))
+	for i := range swings {
+		swings[i] = swingTrigger.Wrap(&menu.ActionNode{
+			NodeName: func(_ *menu.ActionNode) string { return profiles.saved[i].Name },
+			OnEnter: func(_ *menu.ActionNode) {
+				_ = swingProfile(clubs, profiles.saved[i])
+			},
+		})
+	}
+	club := menu.NewRangeValueNode("Trig club", 0, 0, int32(len(swings)-1), 1, func(value int32) string {
+		return profiles.saved[value].Name
+	}, nil)
+
+	// bind 返回触发 action 时输入的菜单操作，急停返回在采样协程中直接调用的函数
+	bind := func(action board.TriggerAction) (menu.Operation, func()) {
+		switch action {
+		case board.TriggerSwing:
+			return menu.Operation{Jump: &menu.Jump{Func: func(current menu.Node) menu.Node {
+				return swingTrigger.Start(swings[club.Value()], current)
+			}}}, nil
+		case board.TriggerRepeat:
+			return menu.Operation{Jump: &menu.Jump{Func: swingTrigger.Repeat}}, nil
+		case board.TriggerEStop:
+			return menu.Operation{}, func() {
+				clubs.EStop()
+				refreshStatus(clubs)
+			}
+		}
+		return menu.Operation{}, nil
+	}
+
+	inputs := make([]menu.UIInput, 0, len(triggers)+len(hidTriggerActions))
+	for _, t := range triggers {
+		input := &menu.PinTrigger{
+			Pin: t.Configure(),
+			Debouncer: menu.Debouncer{
+				Edge:     t.Edge,
+				Debounce: t.Debounce,
+				Lockout:  t.Lockout,
+			},
+			Busy: clubs.Moving,
+		}
+		input.Operation, input.Immediate = bind(t.Action)
+		inputs = append(inputs, input)
+		triggerLog.Infof("%s: %s on %s %s edge", t.Name, t.Action, t.Pin, t.Edge)
+	}
+	for _, t := range hidTriggerActions {
+		input := &hidTrigger{Port: usbhid.Port, LED: t.led, Busy: clubs.Moving}
+		input.Operation, input.Immediate = bind(t.action)
+		inputs = append(inputs, input)
+		triggerLog.Infof("usb hid: %s on %s led", t.action, t.led)
+	}
+	return inputs, club
+}
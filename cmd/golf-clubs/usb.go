@@ -0,0 +1,69 @@
+package main
+
+import (
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/board"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/usbhid"
+)
+
+// usbLog USB HID 日志
+var usbLog = logging.New("usb")
+
+// hidPollInterval 检查主机是否通过 HID 输出报告触发动作的间隔
+const hidPollInterval = 10 * time.Millisecond
+
+// hidTriggerActions 主机置位键盘指示灯时触发的动作
+var hidTriggerActions = []struct {
+	led    usbhid.LED
+	action board.TriggerAction
+}{
+	{led: usbhid.LEDCompose, action: board.TriggerSwing},
+	{led: usbhid.LEDKana, action: board.TriggerRepeat},
+}
+
+// configureUSBHID 运动开始和结束时通过 USB HID 键盘敲击按键，主机端工具据此获取挥杆状态
+func configureUSBHID(clubs *golfclubs.GolfClubs) {
+	clubs.AddObserver(usbhid.Port)
+	usbLog.Infof("hid keys: start %#x, done %#x, fault %#x",
+		usbhid.SwingStartKey, usbhid.SwingDoneKey, usbhid.SwingFaultKey)
+}
+
+// hidTrigger 主机通过 USB HID 输出报告置位键盘指示灯触发的菜单输入源
+type hidTrigger struct {
+	// HID 键盘接口
+	Port *usbhid.Device
+	// 触发的指示灯
+	LED usbhid.LED
+	// 触发时输入的菜单操作
+	Operation menu.Operation
+	// 触发时直接在检查协程中调用，不为 nil 时忽略 Operation
+	Immediate func()
+	// 返回是否正在执行不应排队的动作，见 menu.Pending
+	Busy func() bool
+}
+
+var _ menu.UIInput = (*hidTrigger)(nil)
+
+// StartReceiving 开始接收操作，并将操作输入到 ch
+//
+// 与 menu.PinTrigger 一样，菜单暂时无法接收操作时保留触发， Busy 返回 true 时丢弃。
+func (t *hidTrigger) StartReceiving(ch chan<- menu.Operation) {
+	go func() {
+		pending := &menu.Pending{Busy: t.Busy}
+		for {
+			time.Sleep(hidPollInterval)
+			if t.Port.Triggered(t.LED) {
+				if t.Immediate != nil {
+					t.Immediate()
+					continue
+				}
+				pending.Trigger()
+			}
+			pending.Deliver(ch, t.Operation)
+		}
+	}()
+}
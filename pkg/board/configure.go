@@ -0,0 +1,140 @@
+//go:build tinygo
+
+package board
+
+import (
+	"fmt"
+	"machine"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/display"
+)
+
+// Machine 返回对应的 machine.Pin
+func (p Pin) Machine() machine.Pin {
+	if p == NoPin {
+		return machine.NoPin
+	}
+	return machine.Pin(p)
+}
+
+// Configure 配置 I2C 总线，不使用时返回 nil
+func (c I2C) Configure() (*machine.I2C, error) {
+	if !c.Used() {
+		return nil, nil
+	}
+	bus := machine.I2C0
+	if c.Bus == 1 {
+		bus = machine.I2C1
+	}
+	if err := bus.Configure(machine.I2CConfig{
+		Frequency: c.Frequency,
+		SDA:       c.SDA.Machine(),
+		SCL:       c.SCL.Machine(),
+	}); err != nil {
+		return nil, fmt.Errorf("configure i2c%d error: %w", c.Bus, err)
+	}
+	return bus, nil
+}
+
+// Configure 配置 SPI 总线，不使用时返回 nil
+func (c SPI) Configure() (*machine.SPI, error) {
+	if !c.Used() {
+		return nil, nil
+	}
+	bus := machine.SPI0
+	if c.Bus == 1 {
+		bus = machine.SPI1
+	}
+	if err := bus.Configure(machine.SPIConfig{
+		Frequency: c.Frequency,
+		SCK:       c.SCK.Machine(),
+		SDO:       c.SDO.Machine(),
+		SDI:       c.SDI.Machine(),
+	}); err != nil {
+		return nil, fmt.Errorf("configure spi%d error: %w", c.Bus, err)
+	}
+	return bus, nil
+}
+
+// Configure 配置串口，不使用时返回 nil
+func (c UART) Configure() (*machine.UART, error) {
+	if !c.Used() {
+		return nil, nil
+	}
+	uart := machine.UART0
+	if c.Bus == 1 {
+		uart = machine.UART1
+	}
+	if err := uart.Configure(machine.UARTConfig{
+		BaudRate: c.BaudRate,
+		TX:       c.TX.Machine(),
+		RX:       c.RX.Machine(),
+	}); err != nil {
+		return nil, fmt.Errorf("configure uart%d error: %w", c.Bus, err)
+	}
+	return uart, nil
+}
+
+// Configure 配置外部触发输入针脚，返回对应的 machine.Pin
+func (t Trigger) Configure() machine.Pin {
+	pin := t.Pin.Machine()
+	mode := machine.PinInput
+	switch t.Pull {
+	case PullUp:
+		mode = machine.PinInputPullup
+	case PullDown:
+		mode = machine.PinInputPulldown
+	}
+	pin.Configure(machine.PinConfig{Mode: mode})
+	return pin
+}
+
+// Configure 配置电源电压检测 ADC ，返回对应的 machine.ADC
+func (s Supply) Configure() (machine.ADC, error) {
+	machine.InitADC()
+	adc := machine.ADC{Pin: s.Pin.Machine()}
+	if err := adc.Configure(machine.ADCConfig{}); err != nil {
+		return adc, fmt.Errorf("configure adc %s error: %w", s.Pin, err)
+	}
+	return adc, nil
+}
+
+// Buses 已配置的总线
+type Buses struct {
+	I2C *machine.I2C
+	SPI *machine.SPI
+}
+
+// ConfigureBuses 配置所有使用的总线
+func (p *Profile) ConfigureBuses() (*Buses, error) {
+	i2c, err := p.I2C.Configure()
+	if err != nil {
+		return nil, err
+	}
+	spi, err := p.SPI.Configure()
+	if err != nil {
+		return nil, err
+	}
+	return &Buses{I2C: i2c, SPI: spi}, nil
+}
+
+// DisplayConfig 返回显示器配置
+func (p *Profile) DisplayConfig(buses *Buses) display.Config {
+	cfg := display.Config{
+		Type:         p.Display.Type,
+		Width:        p.Display.Width,
+		Height:       p.Display.Height,
+		Rotation:     p.Display.Rotation,
+		CSPin:        p.Display.CSPin.Machine(),
+		DCPin:        p.Display.DCPin.Machine(),
+		ResetPin:     p.Display.ResetPin.Machine(),
+		BacklightPin: p.Display.BacklightPin.Machine(),
+	}
+	if buses.I2C != nil {
+		cfg.I2C = buses.I2C
+	}
+	if buses.SPI != nil {
+		cfg.SPI = buses.SPI
+	}
+	return cfg
+}
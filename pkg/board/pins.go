@@ -0,0 +1,112 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Pin RP2040 GPIO 编号
+type Pin uint8
+
+// NoPin 未使用的针脚
+const NoPin Pin = 0xff
+
+// MaxPin RP2040 最大 GPIO 编号
+const MaxPin Pin = 29
+
+// String 返回针脚名
+func (p Pin) String() string {
+	if p == NoPin {
+		return "none"
+	}
+	return "GPIO" + strconv.Itoa(int(p))
+}
+
+// Used 返回针脚是否被使用
+func (p Pin) Used() bool {
+	return p != NoPin
+}
+
+// PWMSlice 返回针脚对应的 PWM 组序号和通道
+//
+// RP2040 的 GPIO n 对应 PWM 组 (n/2)%8 的 n%2 通道（ A 通道为 0 ， B 通道为 1 ）
+func PWMSlice(p Pin) (slice, channel uint8) {
+	return uint8(p) / 2 % 8, uint8(p) % 2
+}
+
+// i2cPins 各 I2C 总线可用的 SDA 和 SCL 针脚
+var i2cPins = [2]struct {
+	sda, scl []Pin
+}{
+	{sda: []Pin{0, 4, 8, 12, 16, 20, 24, 28}, scl: []Pin{1, 5, 9, 13, 17, 21, 25, 29}},
+	{sda: []Pin{2, 6, 10, 14, 18, 22, 26}, scl: []Pin{3, 7, 11, 15, 19, 23, 27}},
+}
+
+// spiPins 各 SPI 总线可用的 SCK 、 SDO(TX) 和 SDI(RX) 针脚
+var spiPins = [2]struct {
+	sck, sdo, sdi []Pin
+}{
+	{sck: []Pin{2, 6, 18, 22}, sdo: []Pin{3, 7, 19, 23}, sdi: []Pin{0, 4, 16, 20}},
+	{sck: []Pin{10, 14, 26}, sdo: []Pin{11, 15, 27}, sdi: []Pin{8, 12, 24, 28}},
+}
+
+// uartPins 各 UART 可用的 TX 和 RX 针脚
+var uartPins = [2]struct {
+	tx, rx []Pin
+}{
+	{tx: []Pin{0, 12, 16, 28}, rx: []Pin{1, 13, 17, 29}},
+	{tx: []Pin{4, 8, 20, 24}, rx: []Pin{5, 9, 21, 25}},
+}
+
+// adcPins 可用作 ADC 输入的针脚
+var adcPins = []Pin{26, 27, 28, 29}
+
+// containsPin 返回 pins 中是否包含 p
+func containsPin(pins []Pin, p Pin) bool {
+	for _, pin := range pins {
+		if pin == p {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBusPin 检查针脚是否可用作总线的某个功能
+func checkBusPin(bus string, fn string, p Pin, valid []Pin) error {
+	if !p.Used() {
+		return nil
+	}
+	if !containsPin(valid, p) {
+		return fmt.Errorf("%s %s can not use %s", bus, fn, p)
+	}
+	return nil
+}
+
+// pinAssignment 针脚分配
+type pinAssignment struct {
+	name string
+	pin  Pin
+	// 是否作为 PWM 输出
+	pwm bool
+}
+
+// checkAssignments 检查针脚分配是否越界或冲突
+func checkAssignments(assignments []pinAssignment, reserved []Pin) error {
+	used := map[Pin]string{}
+	for _, a := range assignments {
+		if !a.pin.Used() {
+			continue
+		}
+		if a.pin > MaxPin {
+			return fmt.Errorf("%s: invalid pin %d", a.name, a.pin)
+		}
+		if containsPin(reserved, a.pin) {
+			return fmt.Errorf("%s: %s is reserved by the board", a.name, a.pin)
+		}
+		if other, ok := used[a.pin]; ok {
+			return fmt.Errorf("%s: %s is already used by %s", a.name, a.pin, other)
+		}
+		used[a.pin] = a.name
+	}
+	return nil
+}
@@ -0,0 +1,474 @@
+package board
+
+import (
+	"fmt"
+	"time"
+
+	"tinygo.org/x/drivers"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/display"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/imu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/power"
+)
+
+// Profile 开发板配置，描述各外设的针脚分配
+type Profile struct {
+	// 配置名
+	Name string
+	// 被板载功能占用的针脚
+	Reserved []Pin
+	// 板载状态 LED ，没有时为 NoPin
+	LEDPin Pin
+	// 板载状态 WS2812 彩色灯，没有时为 NoPin ，有时代替 LEDPin 显示状态
+	PixelPin Pin
+	// 蜂鸣器，没有时为 NoPin
+	BuzzerPin Pin
+	// 蜂鸣器为无源压电蜂鸣器，使用 PWM 发出音调，否则为高电平发声的有源蜂鸣器
+	BuzzerPWM bool
+
+	// 步进电机驱动器
+	Motor Motor
+	// I2C 总线
+	I2C I2C
+	// SPI 总线
+	SPI SPI
+	// 显示器
+	Display Display
+	// 旋转编码器
+	Encoder Encoder
+	// 电机轴位置传感器
+	ShaftSensor ShaftSensor
+	// 球杆臂上的 IMU
+	IMU IMU
+	// 外部触发输入
+	Triggers []Trigger
+	// 电机电源电压检测
+	Supply Supply
+}
+
+// Motor 步进电机驱动器配置
+type Motor struct {
+	// 脉冲信号针脚，使用 PWM 输出
+	StepPin Pin
+	// 方向控制针脚
+	DirPin Pin
+	// 脱机（使能）控制针脚
+	EnPin Pin
+	// 驱动器配置，包括信号极性、时序和细分
+	Driver golfclubs.DriverConfig
+	// 配置驱动器所用串口，驱动器不支持串口配置时 TX 和 RX 为 NoPin
+	UART UART
+	// TMC2209 配置，仅在使用串口时有效
+	TMC golfclubs.TMCConfig
+	// TMC2209 地址
+	TMCAddress uint8
+	// 保持转矩时长和运动次数限制
+	Power golfclubs.PowerConfig
+}
+
+// UART 串口配置
+type UART struct {
+	// 串口序号， 0 或 1
+	Bus uint8
+	// 发送针脚，不使用时为 NoPin
+	TX Pin
+	// 接收针脚，不使用时为 NoPin
+	RX Pin
+	// 波特率
+	BaudRate uint32
+}
+
+// Used 返回是否使用该串口
+func (c UART) Used() bool {
+	return c.TX.Used() && c.RX.Used()
+}
+
+// I2C I2C 总线配置
+type I2C struct {
+	// 总线序号， 0 或 1
+	Bus uint8
+	// 数据针脚，不使用 I2C 时为 NoPin
+	SDA Pin
+	// 时钟针脚，不使用 I2C 时为 NoPin
+	SCL Pin
+	// 频率（单位： Hz ）
+	Frequency uint32
+}
+
+// Used 返回是否使用该总线
+func (c I2C) Used() bool {
+	return c.SDA.Used() && c.SCL.Used()
+}
+
+// SPI SPI 总线配置
+type SPI struct {
+	// 总线序号， 0 或 1
+	Bus uint8
+	// 时钟针脚，不使用 SPI 时为 NoPin
+	SCK Pin
+	// 输出针脚，不使用 SPI 时为 NoPin
+	SDO Pin
+	// 输入针脚，可以为 NoPin
+	SDI Pin
+	// 频率（单位： Hz ）
+	Frequency uint32
+}
+
+// Used 返回是否使用该总线
+func (c SPI) Used() bool {
+	return c.SCK.Used() && c.SDO.Used()
+}
+
+// Display 显示器配置
+type Display struct {
+	// 显示器类型
+	Type display.Type
+	// 显示器宽度（旋转前）
+	Width int16
+	// 显示器高度（旋转前）
+	Height int16
+	// 顺时针旋转
+	Rotation drivers.Rotation
+	// 片选针脚，仅用于 SPI 显示器
+	CSPin Pin
+	// 数据/命令选择针脚，仅用于 SPI 显示器
+	DCPin Pin
+	// 复位针脚，仅用于 SPI 显示器
+	ResetPin Pin
+	// 背光针脚，仅用于 TFT
+	BacklightPin Pin
+}
+
+// Encoder 旋转编码器配置
+type Encoder struct {
+	// A 相针脚
+	APin Pin
+	// B 相针脚
+	BPin Pin
+	// 按钮针脚
+	ButtonPin Pin
+}
+
+// ShaftSensorType 电机轴位置传感器类型
+type ShaftSensorType uint8
+
+const (
+	// ShaftSensorNone 无位置传感器，开环运行
+	ShaftSensorNone ShaftSensorType = iota
+	// ShaftSensorQuadrature 正交编码器
+	ShaftSensorQuadrature
+	// ShaftSensorAS5600 通过 I2C 连接的 AS5600 磁编码器
+	ShaftSensorAS5600
+)
+
+// ShaftSensor 电机轴位置传感器配置
+type ShaftSensor struct {
+	// 传感器类型
+	Type ShaftSensorType
+	// 正交编码器 A 相针脚
+	APin Pin
+	// 正交编码器 B 相针脚
+	BPin Pin
+	// 正交编码器每圈计数
+	CountsPerCircle int32
+	// 传感器正方向与向前挥杆方向相反
+	Invert bool
+	// 允许的指令位置与实测位置最大偏差，单位为 1/4096 圈，为 0 时使用默认值
+	Tolerance int32
+	// 检测到位置偏差后自动归位
+	AutoRehome bool
+}
+
+// IMUType IMU 类型
+type IMUType uint8
+
+const (
+	// IMUNone 没有 IMU
+	IMUNone IMUType = iota
+	// IMUMPU6050 通过 I2C 连接的 MPU6050
+	IMUMPU6050
+	// IMUICM42688 通过 I2C 连接的 ICM-42688-P
+	IMUICM42688
+)
+
+// String 返回 IMU 类型名
+func (t IMUType) String() string {
+	switch t {
+	case IMUNone:
+		return "none"
+	case IMUMPU6050:
+		return "mpu6050"
+	case IMUICM42688:
+		return "icm42688"
+	}
+	return "unknown"
+}
+
+// IMU 球杆臂上的 IMU 配置，用于测量挥杆的实际角速度
+type IMU struct {
+	// IMU 类型
+	Type IMUType
+	// I2C 地址，为 0 时使用默认地址
+	Address uint16
+	// 挥杆转轴对应的陀螺仪轴
+	Axis imu.Axis
+	// 陀螺仪正方向与向前挥杆方向相反
+	Invert bool
+	// 转轴到杆头的距离（单位：米），用于计算杆头速度
+	ClubLength float32
+}
+
+// Supply 电机电源（电池或电源适配器）电压检测配置，电源经电阻分压后接到 ADC 针脚
+type Supply struct {
+	// ADC 针脚，不检测时为 NoPin
+	Pin Pin
+	// 分压比、电压范围和阈值
+	Config power.Config
+}
+
+// Pull 输入针脚的上下拉
+type Pull uint8
+
+const (
+	// PullNone 不上下拉，适用于由其它板子驱动的逻辑信号
+	PullNone Pull = iota
+	// PullUp 上拉，适用于接地的常开开关
+	PullUp
+	// PullDown 下拉
+	PullDown
+)
+
+// TriggerAction 外部触发输入对应的动作
+type TriggerAction uint8
+
+const (
+	// TriggerSwing 以所选球杆挥杆
+	TriggerSwing TriggerAction = iota
+	// TriggerRepeat 重复上次动作
+	TriggerRepeat
+	// TriggerEStop 急停
+	TriggerEStop
+)
+
+// String 返回动作名
+func (a TriggerAction) String() string {
+	switch a {
+	case TriggerSwing:
+		return "swing"
+	case TriggerRepeat:
+		return "repeat"
+	case TriggerEStop:
+		return "e-stop"
+	}
+	return "unknown"
+}
+
+// Trigger 外部触发输入配置，如脚踏开关、有线遥控或其它板子的逻辑信号
+type Trigger struct {
+	// 名字
+	Name string
+	// 输入针脚
+	Pin Pin
+	// 上下拉
+	Pull Pull
+	// 触发边沿
+	Edge menu.Edge
+	// 去抖时长
+	Debounce time.Duration
+	// 锁定时长，触发后该时长内忽略输入
+	Lockout time.Duration
+	// 触发的动作
+	Action TriggerAction
+}
+
+// Validate 检查配置是否有效
+func (p *Profile) Validate() error {
+	if err := checkAssignments(p.assignments(), p.Reserved); err != nil {
+		return fmt.Errorf("profile %q: %w", p.Name, err)
+	}
+
+	// 检查电机针脚
+	if !p.Motor.StepPin.Used() || !p.Motor.DirPin.Used() || !p.Motor.EnPin.Used() {
+		return fmt.Errorf("profile %q: motor step, dir and en pins are required", p.Name)
+	}
+	stepSlice, _ := PWMSlice(p.Motor.StepPin)
+	for _, a := range p.assignments() {
+		if a.pin == p.Motor.StepPin || !a.pin.Used() {
+			continue
+		}
+		// 同一 PWM 组的另一通道会共用周期，电机改变速度时会受影响
+		if s, _ := PWMSlice(a.pin); s == stepSlice && a.pwm {
+			return fmt.Errorf(
+				"profile %q: %s shares pwm slice %d with motor step pin %s",
+				p.Name, a.name, stepSlice, p.Motor.StepPin,
+			)
+		}
+	}
+
+	// 检查总线
+	if p.I2C.Used() {
+		if p.I2C.Bus > 1 {
+			return fmt.Errorf("profile %q: invalid i2c bus %d", p.Name, p.I2C.Bus)
+		}
+		pins := i2cPins[p.I2C.Bus]
+		if err := checkBusPin(fmt.Sprintf("i2c%d", p.I2C.Bus), "sda", p.I2C.SDA, pins.sda); err != nil {
+			return fmt.Errorf("profile %q: %w", p.Name, err)
+		}
+		if err := checkBusPin(fmt.Sprintf("i2c%d", p.I2C.Bus), "scl", p.I2C.SCL, pins.scl); err != nil {
+			return fmt.Errorf("profile %q: %w", p.Name, err)
+		}
+	}
+	if p.SPI.Used() {
+		if p.SPI.Bus > 1 {
+			return fmt.Errorf("profile %q: invalid spi bus %d", p.Name, p.SPI.Bus)
+		}
+		pins := spiPins[p.SPI.Bus]
+		bus := fmt.Sprintf("spi%d", p.SPI.Bus)
+		if err := checkBusPin(bus, "sck", p.SPI.SCK, pins.sck); err != nil {
+			return fmt.Errorf("profile %q: %w", p.Name, err)
+		}
+		if err := checkBusPin(bus, "sdo", p.SPI.SDO, pins.sdo); err != nil {
+			return fmt.Errorf("profile %q: %w", p.Name, err)
+		}
+		if err := checkBusPin(bus, "sdi", p.SPI.SDI, pins.sdi); err != nil {
+			return fmt.Errorf("profile %q: %w", p.Name, err)
+		}
+	}
+
+	if p.Motor.UART.Used() {
+		if p.Motor.UART.Bus > 1 {
+			return fmt.Errorf("profile %q: invalid uart %d", p.Name, p.Motor.UART.Bus)
+		}
+		pins := uartPins[p.Motor.UART.Bus]
+		bus := fmt.Sprintf("uart%d", p.Motor.UART.Bus)
+		if err := checkBusPin(bus, "tx", p.Motor.UART.TX, pins.tx); err != nil {
+			return fmt.Errorf("profile %q: %w", p.Name, err)
+		}
+		if err := checkBusPin(bus, "rx", p.Motor.UART.RX, pins.rx); err != nil {
+			return fmt.Errorf("profile %q: %w", p.Name, err)
+		}
+		if p.Motor.TMCAddress > 3 {
+			return fmt.Errorf("profile %q: invalid tmc2209 address %d", p.Name, p.Motor.TMCAddress)
+		}
+	}
+
+	// 检查位置传感器
+	switch p.ShaftSensor.Type {
+	case ShaftSensorNone:
+	case ShaftSensorQuadrature:
+		if !p.ShaftSensor.APin.Used() || !p.ShaftSensor.BPin.Used() {
+			return fmt.Errorf("profile %q: quadrature shaft sensor requires a and b pins", p.Name)
+		}
+		if p.ShaftSensor.CountsPerCircle <= 0 {
+			return fmt.Errorf("profile %q: quadrature shaft sensor requires counts per circle", p.Name)
+		}
+	case ShaftSensorAS5600:
+		if !p.I2C.Used() {
+			return fmt.Errorf("profile %q: as5600 shaft sensor requires i2c", p.Name)
+		}
+	default:
+		return fmt.Errorf("profile %q: unknown shaft sensor type %d", p.Name, p.ShaftSensor.Type)
+	}
+
+	// 检查 IMU
+	switch {
+	case p.IMU.Type == IMUNone:
+	case p.IMU.Type > IMUICM42688:
+		return fmt.Errorf("profile %q: unknown imu type %d", p.Name, p.IMU.Type)
+	case !p.I2C.Used():
+		return fmt.Errorf("profile %q: imu %s requires i2c", p.Name, p.IMU.Type)
+	case p.IMU.Axis > imu.AxisZ:
+		return fmt.Errorf("profile %q: invalid imu axis %d", p.Name, p.IMU.Axis)
+	}
+
+	// 检查电源电压检测
+	if p.Supply.Pin.Used() {
+		if !containsPin(adcPins, p.Supply.Pin) {
+			return fmt.Errorf("profile %q: supply can not use %s, adc pins are GPIO26 to GPIO29", p.Name, p.Supply.Pin)
+		}
+		if err := p.Supply.Config.Validate(); err != nil {
+			return fmt.Errorf("profile %q: supply: %w", p.Name, err)
+		}
+	}
+
+	// 检查外部触发输入
+	for _, t := range p.Triggers {
+		switch {
+		case !t.Pin.Used():
+			return fmt.Errorf("profile %q: trigger %q requires a pin", p.Name, t.Name)
+		case t.Pull > PullDown:
+			return fmt.Errorf("profile %q: trigger %q has unknown pull %d", p.Name, t.Name, t.Pull)
+		case t.Edge > menu.EdgeBoth:
+			return fmt.Errorf("profile %q: trigger %q has unknown edge %d", p.Name, t.Name, t.Edge)
+		case t.Action > TriggerEStop:
+			return fmt.Errorf("profile %q: trigger %q has unknown action %d", p.Name, t.Name, t.Action)
+		}
+	}
+
+	// 检查显示器
+	switch {
+	case p.Display.Type.UsesI2C() && !p.I2C.Used():
+		return fmt.Errorf("profile %q: display %s requires i2c", p.Name, p.Display.Type)
+	case p.Display.Type.UsesSPI() && !p.SPI.Used():
+		return fmt.Errorf("profile %q: display %s requires spi", p.Name, p.Display.Type)
+	case p.Display.Type.UsesSPI() && (!p.Display.DCPin.Used() || !p.Display.CSPin.Used()):
+		return fmt.Errorf("profile %q: display %s requires dc and cs pins", p.Name, p.Display.Type)
+	}
+
+	return nil
+}
+
+// assignments 返回所有针脚分配
+func (p *Profile) assignments() []pinAssignment {
+	ret := []pinAssignment{
+		{name: "led", pin: p.LEDPin},
+		{name: "pixel", pin: p.PixelPin},
+		{name: "buzzer", pin: p.BuzzerPin, pwm: p.BuzzerPWM},
+		{name: "motor step", pin: p.Motor.StepPin, pwm: true},
+		{name: "motor dir", pin: p.Motor.DirPin},
+		{name: "motor en", pin: p.Motor.EnPin},
+		{name: "encoder a", pin: p.Encoder.APin},
+		{name: "encoder b", pin: p.Encoder.BPin},
+		{name: "encoder button", pin: p.Encoder.ButtonPin},
+		{name: "supply", pin: p.Supply.Pin},
+	}
+	if p.ShaftSensor.Type == ShaftSensorQuadrature {
+		ret = append(ret,
+			pinAssignment{name: "shaft sensor a", pin: p.ShaftSensor.APin},
+			pinAssignment{name: "shaft sensor b", pin: p.ShaftSensor.BPin},
+		)
+	}
+	for _, t := range p.Triggers {
+		ret = append(ret, pinAssignment{name: "trigger " + t.Name, pin: t.Pin})
+	}
+	if p.Motor.UART.Used() {
+		ret = append(ret,
+			pinAssignment{name: "motor uart tx", pin: p.Motor.UART.TX},
+			pinAssignment{name: "motor uart rx", pin: p.Motor.UART.RX},
+		)
+	}
+	if p.I2C.Used() {
+		ret = append(ret,
+			pinAssignment{name: "i2c sda", pin: p.I2C.SDA},
+			pinAssignment{name: "i2c scl", pin: p.I2C.SCL},
+		)
+	}
+	if p.SPI.Used() {
+		ret = append(ret,
+			pinAssignment{name: "spi sck", pin: p.SPI.SCK},
+			pinAssignment{name: "spi sdo", pin: p.SPI.SDO},
+			pinAssignment{name: "spi sdi", pin: p.SPI.SDI},
+		)
+	}
+	if p.Display.Type.UsesSPI() {
+		ret = append(ret,
+			pinAssignment{name: "display cs", pin: p.Display.CSPin},
+			pinAssignment{name: "display dc", pin: p.Display.DCPin},
+			pinAssignment{name: "display reset", pin: p.Display.ResetPin},
+			pinAssignment{name: "display backlight", pin: p.Display.BacklightPin},
+		)
+	}
+	return ret
+}
@@ -0,0 +1,153 @@
+package board
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/display"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/imu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/menu"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/power"
+)
+
+// TestProfiles 测试内置配置均有效
+func TestProfiles(t *testing.T) {
+	for _, p := range Profiles {
+		if err := p.Validate(); err != nil {
+			t.Errorf("profile %q is invalid: %v", p.Name, err)
+		}
+		if Lookup(p.Name) != p {
+			t.Errorf("lookup %q failed", p.Name)
+		}
+	}
+}
+
+// TestProfile_Validate 测试 Profile.Validate
+func TestProfile_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(p *Profile)
+		errMsg string
+	}{
+		{name: "conflict", modify: func(p *Profile) {
+			p.Encoder.ButtonPin = p.Motor.DirPin
+		}, errMsg: "GPIO3 is already used by motor dir"},
+		{name: "reserved", modify: func(p *Profile) {
+			p.Motor.EnPin = 25
+		}, errMsg: "GPIO25 is reserved"},
+		{name: "invalid-pin", modify: func(p *Profile) {
+			p.Motor.StepPin = 30
+		}, errMsg: "invalid pin 30"},
+		{name: "i2c-pin", modify: func(p *Profile) {
+			p.I2C.SDA = 12
+		}, errMsg: "i2c1 sda can not use GPIO12"},
+		{name: "tmc-uart", modify: func(p *Profile) {
+			p.Motor.Driver = golfclubs.DriverTMC2209
+			p.Motor.UART = UART{Bus: 1, TX: 20, RX: 21, BaudRate: 115200}
+		}},
+		{name: "tmc-uart-pin", modify: func(p *Profile) {
+			p.Motor.UART = UART{Bus: 0, TX: 20, RX: 21, BaudRate: 115200}
+		}, errMsg: "uart0 tx can not use GPIO20"},
+		{name: "quadrature-conflict", modify: func(p *Profile) {
+			p.ShaftSensor = ShaftSensor{Type: ShaftSensorQuadrature, APin: 6, BPin: 12, CountsPerCircle: 600}
+		}, errMsg: "GPIO6 is already used by encoder a"},
+		{name: "as5600", modify: func(p *Profile) {
+			p.ShaftSensor = ShaftSensor{Type: ShaftSensorAS5600, AutoRehome: true}
+		}},
+		{name: "pwm-buzzer", modify: func(p *Profile) {
+			p.BuzzerPin = 16
+			p.BuzzerPWM = true
+		}},
+		{name: "pwm-buzzer-slice", modify: func(p *Profile) {
+			p.BuzzerPin = 18
+			p.BuzzerPWM = true
+		}, errMsg: "buzzer shares pwm slice 1"},
+		{name: "imu", modify: func(p *Profile) {
+			p.IMU = IMU{Type: IMUICM42688, Axis: imu.AxisZ, ClubLength: 0.3}
+		}},
+		{name: "imu-without-i2c", modify: func(p *Profile) {
+			p.I2C = I2C{SDA: NoPin, SCL: NoPin}
+			p.Display.Type = display.TypeNone
+			p.IMU = IMU{Type: IMUMPU6050}
+		}, errMsg: "imu mpu6050 requires i2c"},
+		{name: "triggers", modify: func(p *Profile) {
+			p.Triggers = []Trigger{
+				{Name: "pedal", Pin: 14, Pull: PullUp, Edge: menu.EdgeFalling, Debounce: 20 * time.Millisecond, Action: TriggerSwing},
+				{Name: "estop", Pin: 15, Pull: PullUp, Edge: menu.EdgeFalling, Action: TriggerEStop},
+			}
+		}},
+		{name: "trigger-conflict", modify: func(p *Profile) {
+			p.Triggers = []Trigger{{Name: "pedal", Pin: 8, Action: TriggerRepeat}}
+		}, errMsg: "GPIO8 is already used by encoder button"},
+		{name: "trigger-action", modify: func(p *Profile) {
+			p.Triggers = []Trigger{{Name: "pedal", Pin: 14, Action: 9}}
+		}, errMsg: "unknown action 9"},
+		{name: "supply", modify: func(p *Profile) {
+			// 12V 电源经 47k/10k 分压
+			p.Supply = Supply{Pin: 26, Config: power.Config{Divider: 5.7, Full: 12.6, Empty: 10.5, Low: 11, Critical: 10}}
+		}},
+		{name: "supply-not-adc", modify: func(p *Profile) {
+			p.Supply = Supply{Pin: 14, Config: power.Config{Divider: 5.7, Full: 12.6, Empty: 10.5}}
+		}, errMsg: "supply can not use GPIO14"},
+		{name: "supply-reserved", modify: func(p *Profile) {
+			p.Supply = Supply{Pin: 29, Config: power.Config{Divider: 3, Full: 5.5, Empty: 4}}
+		}, errMsg: "GPIO29 is reserved"},
+		{name: "supply-divider", modify: func(p *Profile) {
+			p.Supply = Supply{Pin: 27, Config: power.Config{Full: 12.6, Empty: 10.5}}
+		}, errMsg: "invalid divider ratio"},
+		{name: "display-without-spi", modify: func(p *Profile) {
+			p.Display.Type = display.TypeST7789
+		}, errMsg: "requires spi"},
+		{name: "spi-display", modify: func(p *Profile) {
+			p.SPI = SPI{Bus: 0, SCK: 18, SDO: 19, SDI: NoPin, Frequency: 16_000_000}
+			p.Display = Display{
+				Type:         display.TypeST7789,
+				Width:        240,
+				Height:       240,
+				CSPin:        17,
+				DCPin:        20,
+				ResetPin:     21,
+				BacklightPin: 22,
+			}
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := PicoW
+			c.modify(&p)
+			err := p.Validate()
+			switch {
+			case c.errMsg == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case c.errMsg != "" && err == nil:
+				t.Errorf("expected error containing %q, got nil", c.errMsg)
+			case c.errMsg != "" && !strings.Contains(err.Error(), c.errMsg):
+				t.Errorf("expected error containing %q, got %v", c.errMsg, err)
+			}
+		})
+	}
+}
+
+// TestPWMSlice 测试 PWMSlice
+func TestPWMSlice(t *testing.T) {
+	cases := []struct {
+		pin     Pin
+		slice   uint8
+		channel uint8
+	}{
+		{pin: 0, slice: 0, channel: 0},
+		{pin: 2, slice: 1, channel: 0},
+		{pin: 3, slice: 1, channel: 1},
+		{pin: 15, slice: 7, channel: 1},
+		{pin: 16, slice: 0, channel: 0},
+		{pin: 28, slice: 6, channel: 0},
+	}
+	for _, c := range cases {
+		slice, channel := PWMSlice(c.pin)
+		if slice != c.slice || channel != c.channel {
+			t.Errorf("pin %d: expected (%d, %d), got (%d, %d)", c.pin, c.slice, c.channel, slice, channel)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package board
+
+import (
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/display"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+)
+
+// Pico Raspberry Pi Pico 默认配置
+var Pico = Profile{
+	Name: "pico",
+	// GPIO23 控制 SMPS ， GPIO24 检测 VBUS ， GPIO29 检测 VSYS
+	Reserved:  []Pin{23, 24, 29},
+	LEDPin:    25,
+	PixelPin:  NoPin,
+	BuzzerPin: NoPin,
+	Motor: Motor{
+		StepPin: 2,
+		DirPin:  3,
+		EnPin:   4,
+		Driver:  golfclubs.DriverTB6600,
+		UART:    UART{TX: NoPin, RX: NoPin},
+		// 挥杆后保持 2 秒防止球杆下落，每分钟最多挥杆 20 次，超过后冷却 30 秒
+		Power: golfclubs.PowerConfig{
+			HoldTime:   2 * time.Second,
+			MaxMotions: 20,
+			Cooldown:   30 * time.Second,
+		},
+	},
+	I2C: I2C{
+		Bus:       1,
+		SDA:       10,
+		SCL:       11,
+		Frequency: 400_000,
+	},
+	SPI: SPI{SCK: NoPin, SDO: NoPin, SDI: NoPin},
+	Display: Display{
+		Type:         display.TypeSH1106I2C,
+		Width:        128,
+		Height:       32,
+		CSPin:        NoPin,
+		DCPin:        NoPin,
+		ResetPin:     NoPin,
+		BacklightPin: NoPin,
+	},
+	Encoder: Encoder{
+		APin:      6,
+		BPin:      7,
+		ButtonPin: 8,
+	},
+	Supply: Supply{Pin: NoPin},
+}
+
+// PicoW Raspberry Pi Pico W 默认配置
+var PicoW = func() Profile {
+	p := Pico
+	p.Name = "pico-w"
+	// GPIO23 、 GPIO24 、 GPIO25 和 GPIO29 连接无线模块
+	p.Reserved = []Pin{23, 24, 25, 29}
+	// 板载 LED 连接在无线模块上
+	p.LEDPin = NoPin
+	return p
+}()
+
+// Profiles 所有内置配置
+var Profiles = []*Profile{&Pico, &PicoW}
+
+// Lookup 基于名字查找内置配置，找不到时返回 nil
+func Lookup(name string) *Profile {
+	for _, p := range Profiles {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
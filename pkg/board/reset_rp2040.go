@@ -0,0 +1,26 @@
+//go:build rp2040
+
+package board
+
+import "device/rp"
+
+// watchdogMagic 程序启动看门狗时写入 WATCHDOG.SCRATCH4 的值，与 pico-sdk 的 WATCHDOG_NON_REBOOT_MAGIC 相同
+//
+// 烧录（ picotool 、 UF2 ）和 bootrom 复位也通过看门狗重启，同样会设置 REASON 的 TIMER 位，
+// 但不会写入该值（ bootrom 在 SCRATCH4 写入自己的值），据此区分程序卡死引起的复位。
+const watchdogMagic = 0x6ab73121
+
+// MarkWatchdog 启动看门狗时调用，记录看门狗由程序启动
+func MarkWatchdog() {
+	rp.WATCHDOG.SCRATCH4.Set(watchdogMagic)
+}
+
+// WatchdogReset 返回上次复位是否由程序启动的看门狗超时引起，并清除 MarkWatchdog 的记录
+//
+// 看门狗复位不会清除 WATCHDOG 的 REASON 和 SCRATCH 寄存器，上电和 RUN 针脚复位时均为 0 。
+func WatchdogReset() bool {
+	reset := rp.WATCHDOG.REASON.HasBits(rp.WATCHDOG_REASON_TIMER) &&
+		rp.WATCHDOG.SCRATCH4.Get() == watchdogMagic
+	rp.WATCHDOG.SCRATCH4.Set(0)
+	return reset
+}
@@ -0,0 +1,66 @@
+package buildinfo
+
+import "time"
+
+// 构建时通过 -ldflags 设置，如：
+//
+//	tinygo flash -target pico -ldflags "-X github.com/yhlooo/ns-sports-golf-clubs/pkg/buildinfo.Version=v0.1.0
+//	  -X github.com/yhlooo/ns-sports-golf-clubs/pkg/buildinfo.Commit=$(git rev-parse HEAD)
+//	  -X github.com/yhlooo/ns-sports-golf-clubs/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/golf-clubs
+var (
+	// Version 固件版本
+	Version = "dev"
+	// Commit 构建时的 git 提交
+	Commit = ""
+	// BuildTime 构建时间， RFC 3339 格式
+	BuildTime = ""
+)
+
+// shortCommitLen 短提交号长度
+const shortCommitLen = 7
+
+// Info 固件信息
+type Info struct {
+	// 版本
+	Version string
+	// git 提交，未设置时为空
+	Commit string
+	// 构建时间，未设置或格式错误时为零值
+	BuildTime time.Time
+}
+
+// Get 返回构建时设置的固件信息
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit}
+	if Version == "" {
+		info.Version = "dev"
+	}
+	if t, err := time.Parse(time.RFC3339, BuildTime); err == nil {
+		info.BuildTime = t.UTC()
+	}
+	return info
+}
+
+// ShortCommit 返回短提交号，未设置时返回 "unknown"
+func (i Info) ShortCommit() string {
+	switch {
+	case i.Commit == "":
+		return "unknown"
+	case len(i.Commit) > shortCommitLen:
+		return i.Commit[:shortCommitLen]
+	}
+	return i.Commit
+}
+
+// Built 返回构建日期，未设置时返回 "unknown"
+func (i Info) Built() string {
+	if i.BuildTime.IsZero() {
+		return "unknown"
+	}
+	return i.BuildTime.Format(time.DateOnly)
+}
+
+// String 返回 "<版本> (<短提交号>, <构建日期>)"
+func (i Info) String() string {
+	return i.Version + " (" + i.ShortCommit() + ", " + i.Built() + ")"
+}
@@ -0,0 +1,41 @@
+package buildinfo
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGet 测试 Get 解析构建时设置的信息
+func TestGet(t *testing.T) {
+	defer func(version, commit, buildTime string) {
+		Version, Commit, BuildTime = version, commit, buildTime
+	}(Version, Commit, BuildTime)
+
+	Version, Commit, BuildTime = "", "", ""
+	info := Get()
+	if info.String() != "dev (unknown, unknown)" {
+		t.Errorf("unexpected default info: %s", info)
+	}
+
+	Version = "v1.2.0"
+	Commit = "0123456789abcdef"
+	BuildTime = "2026-10-19T08:30:00+08:00"
+	info = Get()
+	expected := Info{
+		Version:   "v1.2.0",
+		Commit:    "0123456789abcdef",
+		BuildTime: time.Date(2026, 10, 19, 0, 30, 0, 0, time.UTC),
+	}
+	if info != expected {
+		t.Errorf("expected %+v, got %+v", expected, info)
+	}
+	if info.String() != "v1.2.0 (0123456, 2026-10-19)" {
+		t.Errorf("unexpected info string: %s", info)
+	}
+
+	// 格式错误的构建时间视为未设置
+	BuildTime = "yesterday"
+	if info := Get(); !info.BuildTime.IsZero() || info.Built() != "unknown" {
+		t.Errorf("expected unknown build time, got %s", info.BuildTime)
+	}
+}
@@ -0,0 +1,171 @@
+package calibration
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrNotEnoughPoints 校准点不足，无法插值
+	ErrNotEnoughPoints = errors.New("at least 2 calibration points required")
+	// ErrOutOfRange 目标超出校准范围
+	ErrOutOfRange = errors.New("target out of calibrated range")
+)
+
+// Point 校准点
+type Point struct {
+	// 挥杆设置，球杆为速度百分比，推杆为后摆角度
+	// JSON 键沿用旧版本的 speed ，兼容已保存的校准数据
+	Setting uint8 `json:"speed"`
+	// 游戏中观察到的距离
+	Distance float32 `json:"distance"`
+}
+
+// Curve 一个球杆的设置-距离曲线
+type Curve struct {
+	// 球杆名
+	Club string `json:"club"`
+	// 校准点，按设置递增
+	Points []Point `json:"points"`
+}
+
+// Add 添加校准点，已有相同设置的点时取代它
+func (c *Curve) Add(p Point) {
+	i := sort.Search(len(c.Points), func(i int) bool {
+		return c.Points[i].Setting >= p.Setting
+	})
+	if i < len(c.Points) && c.Points[i].Setting == p.Setting {
+		c.Points[i] = p
+		return
+	}
+	c.Points = append(c.Points, Point{})
+	copy(c.Points[i+1:], c.Points[i:])
+	c.Points[i] = p
+}
+
+// Fit 返回拟合后的曲线点
+//
+// 距离应随设置增加而增加，游戏中观察到的距离有误差，使用保序回归（相邻违反者合并）将距离修正为单调不减。
+func (c *Curve) Fit() []Point {
+	type block struct {
+		sum   float32
+		count int
+	}
+	blocks := make([]block, 0, len(c.Points))
+	for _, p := range c.Points {
+		blocks = append(blocks, block{sum: p.Distance, count: 1})
+		// 与前一块均值违反单调时合并
+		for len(blocks) > 1 {
+			last, prev := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if prev.sum/float32(prev.count) <= last.sum/float32(last.count) {
+				break
+			}
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1] = block{sum: prev.sum + last.sum, count: prev.count + last.count}
+		}
+	}
+
+	fitted := make([]Point, 0, len(c.Points))
+	for _, b := range blocks {
+		mean := b.sum / float32(b.count)
+		for j := 0; j < b.count; j++ {
+			fitted = append(fitted, Point{Setting: c.Points[len(fitted)].Setting, Distance: mean})
+		}
+	}
+	return fitted
+}
+
+// Distance 返回以 setting 设置挥杆的预计距离
+func (c *Curve) Distance(setting uint8) (float32, error) {
+	points := c.Fit()
+	if len(points) < 2 {
+		return 0, ErrNotEnoughPoints
+	}
+	if setting < points[0].Setting || setting > points[len(points)-1].Setting {
+		return 0, fmt.Errorf("%w: setting %d not in [%d, %d]", ErrOutOfRange, setting, points[0].Setting, points[len(points)-1].Setting)
+	}
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		if setting <= b.Setting {
+			ratio := float32(setting-a.Setting) / float32(b.Setting-a.Setting)
+			return a.Distance + ratio*(b.Distance-a.Distance), nil
+		}
+	}
+	return points[len(points)-1].Distance, nil
+}
+
+// Setting 返回达到 distance 距离所需的设置
+//
+// 在拟合曲线上线性插值，多个设置距离相同时取最小的设置。
+func (c *Curve) Setting(distance float32) (uint8, error) {
+	points := c.Fit()
+	if len(points) < 2 {
+		return 0, ErrNotEnoughPoints
+	}
+	first, last := points[0], points[len(points)-1]
+	// distance 为 NaN 时也超出范围
+	if !(distance >= first.Distance && distance <= last.Distance) {
+		return 0, fmt.Errorf("%w: distance %.0f not in [%.0f, %.0f]", ErrOutOfRange, distance, first.Distance, last.Distance)
+	}
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		if distance > b.Distance {
+			continue
+		}
+		if b.Distance == a.Distance {
+			return a.Setting, nil
+		}
+		ratio := (distance - a.Distance) / (b.Distance - a.Distance)
+		setting := float32(a.Setting) + ratio*float32(b.Setting-a.Setting)
+		return uint8(setting + 0.5), nil
+	}
+	return last.Setting, nil
+}
+
+// Range 返回已校准的距离范围
+func (c *Curve) Range() (min, max float32, err error) {
+	points := c.Fit()
+	if len(points) < 2 {
+		return 0, 0, ErrNotEnoughPoints
+	}
+	return points[0].Distance, points[len(points)-1].Distance, nil
+}
+
+// Table 各球杆的校准曲线
+type Table struct {
+	Curves []Curve `json:"curves"`
+}
+
+// Curve 返回球杆 club 的曲线，不存在时创建
+func (t *Table) Curve(club string) *Curve {
+	for i := range t.Curves {
+		if t.Curves[i].Club == club {
+			return &t.Curves[i]
+		}
+	}
+	t.Curves = append(t.Curves, Curve{Club: club})
+	return &t.Curves[len(t.Curves)-1]
+}
+
+// Lookup 返回球杆 club 的曲线，不存在时返回 nil
+func (t *Table) Lookup(club string) *Curve {
+	for i := range t.Curves {
+		if t.Curves[i].Club == club {
+			return &t.Curves[i]
+		}
+	}
+	return nil
+}
+
+// SweepSpeeds 返回从 from 到 to （含）每隔 step 的校准速度
+func SweepSpeeds(from, to, step uint8) []uint8 {
+	if step == 0 {
+		step = 1
+	}
+	var speeds []uint8
+	for s := int(from); s <= int(to); s += int(step) {
+		speeds = append(speeds, uint8(s))
+	}
+	return speeds
+}
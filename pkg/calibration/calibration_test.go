@@ -0,0 +1,104 @@
+package calibration
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestCurve_Add 测试 Curve.Add
+func TestCurve_Add(t *testing.T) {
+	c := &Curve{}
+	for _, p := range []Point{{Setting: 50, Distance: 100}, {Setting: 10, Distance: 20}, {Setting: 30, Distance: 60}, {Setting: 50, Distance: 110}} {
+		c.Add(p)
+	}
+	expected := []Point{{Setting: 10, Distance: 20}, {Setting: 30, Distance: 60}, {Setting: 50, Distance: 110}}
+	if len(c.Points) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, c.Points)
+	}
+	for i := range expected {
+		if c.Points[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, c.Points)
+			break
+		}
+	}
+}
+
+// TestCurve_Fit 测试 Curve.Fit
+func TestCurve_Fit(t *testing.T) {
+	c := &Curve{Points: []Point{
+		{Setting: 10, Distance: 20},
+		{Setting: 20, Distance: 50},
+		{Setting: 30, Distance: 40},
+		{Setting: 40, Distance: 80},
+	}}
+	expected := []float32{20, 45, 45, 80}
+	for i, p := range c.Fit() {
+		if p.Setting != c.Points[i].Setting || p.Distance != expected[i] {
+			t.Errorf("point %d: expected %d/%f, got %+v", i, c.Points[i].Setting, expected[i], p)
+		}
+	}
+}
+
+// TestCurve_Interpolate 测试 Curve.Distance 和 Curve.Setting
+func TestCurve_Interpolate(t *testing.T) {
+	c := &Curve{Points: []Point{
+		{Setting: 20, Distance: 40},
+		{Setting: 40, Distance: 100},
+		{Setting: 60, Distance: 100},
+		{Setting: 100, Distance: 220},
+	}}
+
+	distances := map[uint8]float32{20: 40, 30: 70, 50: 100, 80: 160, 100: 220}
+	for speed, expected := range distances {
+		if d, err := c.Distance(speed); err != nil || d != expected {
+			t.Errorf("distance at speed %d: expected %f, got %f, %v", speed, expected, d, err)
+		}
+	}
+	speeds := map[float32]uint8{40: 20, 70: 30, 100: 40, 103: 61, 220: 100}
+	for distance, expected := range speeds {
+		if s, err := c.Setting(distance); err != nil || s != expected {
+			t.Errorf("speed for distance %f: expected %d, got %d, %v", distance, expected, s, err)
+		}
+	}
+
+	if _, err := c.Setting(300); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected out of range error, got %v", err)
+	}
+	if _, err := c.Setting(float32(math.NaN())); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected out of range error for NaN, got %v", err)
+	}
+	if _, err := c.Distance(10); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected out of range error, got %v", err)
+	}
+	if _, err := (&Curve{Points: c.Points[:1]}).Setting(40); !errors.Is(err, ErrNotEnoughPoints) {
+		t.Errorf("expected not enough points error, got %v", err)
+	}
+}
+
+// TestSweepSpeeds 测试 SweepSpeeds
+func TestSweepSpeeds(t *testing.T) {
+	got := SweepSpeeds(20, 100, 20)
+	expected := []uint8{20, 40, 60, 80, 100}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, got)
+			break
+		}
+	}
+}
+
+// TestPoint_JSON 测试校准点的 JSON 键与旧版本保存的数据兼容
+func TestPoint_JSON(t *testing.T) {
+	var c Curve
+	if err := json.Unmarshal([]byte(`{"club":"Driver","points":[{"speed":60,"distance":180}]}`), &c); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if len(c.Points) != 1 || c.Points[0] != (Point{Setting: 60, Distance: 180}) {
+		t.Errorf("unexpected points: %+v", c.Points)
+	}
+}
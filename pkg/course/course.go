@@ -0,0 +1,195 @@
+package course
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MaxNameLength 球场名最大长度
+const MaxNameLength = 24
+
+// Shot 计划的一次击球
+//
+// Speed 和 Distance 只需指定其一，都指定时使用 Distance 。
+type Shot struct {
+	// 球杆名
+	Club string `json:"club"`
+	// 挥杆速度百分比，推杆为后摆角度（单位：度），与校准点的设置一致
+	Speed uint8 `json:"speed,omitempty"`
+	// 目标距离，推杆单位为米，其它球杆单位为码
+	Distance float32 `json:"distance,omitempty"`
+	// 瞄准说明
+	Note string `json:"note,omitempty"`
+}
+
+// Validate 检查击球计划是否有效
+func (s *Shot) Validate() error {
+	switch {
+	case s.Club == "":
+		return errors.New("club is required")
+	case s.Speed == 0 && s.Distance <= 0:
+		return errors.New("speed or distance is required")
+	case s.Speed > 100:
+		return fmt.Errorf("speed %d out of range [1, 100]", s.Speed)
+	}
+	return nil
+}
+
+// Hole 球洞
+type Hole struct {
+	// 洞号，从 1 开始
+	Number int `json:"number"`
+	// 标准杆数
+	Par int `json:"par,omitempty"`
+	// 按顺序计划的击球
+	Shots []Shot `json:"shots"`
+}
+
+// Course 球场
+type Course struct {
+	// 球场名
+	Name string `json:"name"`
+	// 球洞，按洞号排列
+	Holes []Hole `json:"holes"`
+}
+
+// Validate 检查球场是否有效
+func (c *Course) Validate() error {
+	if c.Name == "" || len(c.Name) > MaxNameLength {
+		return fmt.Errorf("course name %q must be 1 to %d characters", c.Name, MaxNameLength)
+	}
+	if len(c.Holes) == 0 {
+		return fmt.Errorf("course %q has no holes", c.Name)
+	}
+	for i := range c.Holes {
+		h := &c.Holes[i]
+		if i > 0 && h.Number <= c.Holes[i-1].Number {
+			return fmt.Errorf("course %q hole %d not after hole %d", c.Name, h.Number, c.Holes[i-1].Number)
+		}
+		for j := range h.Shots {
+			if err := h.Shots[j].Validate(); err != nil {
+				return fmt.Errorf("course %q hole %d shot %d: %w", c.Name, h.Number, j+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Database 球场数据库
+type Database struct {
+	Courses []Course `json:"courses"`
+}
+
+// Unmarshal 从 JSON 解码并检查数据库
+func Unmarshal(data []byte) (*Database, error) {
+	db := &Database{}
+	if err := json.Unmarshal(data, db); err != nil {
+		return nil, err
+	}
+	for i := range db.Courses {
+		if err := db.Courses[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// Find 返回名为 name 的球场，不存在时返回 nil
+func (db *Database) Find(name string) *Course {
+	for i := range db.Courses {
+		if db.Courses[i].Name == name {
+			return &db.Courses[i]
+		}
+	}
+	return nil
+}
+
+// Put 添加球场，已有同名球场时取代它
+func (db *Database) Put(c Course) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if existing := db.Find(c.Name); existing != nil {
+		*existing = c
+		return nil
+	}
+	db.Courses = append(db.Courses, c)
+	return nil
+}
+
+// Delete 删除名为 name 的球场，返回是否存在
+func (db *Database) Delete(name string) bool {
+	for i := range db.Courses {
+		if db.Courses[i].Name == name {
+			db.Courses = append(db.Courses[:i], db.Courses[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Player 按顺序逐杆打一个球场
+type Player struct {
+	Course *Course
+
+	hole int
+	shot int
+}
+
+// NewPlayer 创建从第一洞第一杆开始的 *Player
+func NewPlayer(c *Course) *Player {
+	return &Player{Course: c}
+}
+
+// Hole 返回当前球洞，没有球洞时返回 nil
+func (p *Player) Hole() *Hole {
+	if p.Course == nil || p.hole >= len(p.Course.Holes) {
+		return nil
+	}
+	return &p.Course.Holes[p.hole]
+}
+
+// Shot 返回当前击球序号（从 0 开始）和计划，当前洞已打完时返回 nil
+func (p *Player) Shot() (int, *Shot) {
+	h := p.Hole()
+	if h == nil || p.shot >= len(h.Shots) {
+		return p.shot, nil
+	}
+	return p.shot, &h.Shots[p.shot]
+}
+
+// Next 前进到下一杆，当前洞打完后进入下一洞
+func (p *Player) Next() {
+	h := p.Hole()
+	if h == nil {
+		return
+	}
+	p.shot++
+	if p.shot >= len(h.Shots) {
+		p.NextHole()
+	}
+}
+
+// NextHole 进入下一洞，最后一洞之后回到第一洞
+func (p *Player) NextHole() {
+	if p.Course == nil || len(p.Course.Holes) == 0 {
+		return
+	}
+	p.hole = (p.hole + 1) % len(p.Course.Holes)
+	p.shot = 0
+}
+
+// SetHole 进入第 i 洞（从 0 开始）
+func (p *Player) SetHole(i int) {
+	if p.Course == nil || i < 0 || i >= len(p.Course.Holes) {
+		return
+	}
+	p.hole = i
+	p.shot = 0
+}
+
+// HoleIndex 返回当前球洞序号（从 0 开始）
+func (p *Player) HoleIndex() int {
+	return p.hole
+}
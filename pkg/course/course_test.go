@@ -0,0 +1,114 @@
+package course
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testDatabase = `{"courses": [{
+	"name": "Classic",
+	"holes": [
+		{"number": 1, "par": 3, "shots": [
+			{"club": "5-Iron", "distance": 150, "note": "aim left of flag"},
+			{"club": "Putter", "distance": 3.5}
+		]},
+		{"number": 2, "par": 4, "shots": [
+			{"club": "Driver", "speed": 95}
+		]}
+	]
+}]}`
+
+// TestUnmarshal 测试 Unmarshal
+func TestUnmarshal(t *testing.T) {
+	db, err := Unmarshal([]byte(testDatabase))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	c := db.Find("Classic")
+	if c == nil || len(c.Holes) != 2 || c.Holes[0].Shots[0].Note != "aim left of flag" {
+		t.Fatalf("unexpected database: %+v", db)
+	}
+
+	invalid := map[string]string{
+		"no-name":   `{"courses": [{"holes": [{"number": 1}]}]}`,
+		"no-holes":  `{"courses": [{"name": "a"}]}`,
+		"order":     `{"courses": [{"name": "a", "holes": [{"number": 2}, {"number": 1}]}]}`,
+		"no-club":   `{"courses": [{"name": "a", "holes": [{"number": 1, "shots": [{"speed": 50}]}]}]}`,
+		"no-target": `{"courses": [{"name": "a", "holes": [{"number": 1, "shots": [{"club": "Driver"}]}]}]}`,
+		"json":      `{"courses": [`,
+	}
+	for name, data := range invalid {
+		if _, err := Unmarshal([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// TestDatabase_Put 测试 Database.Put 和 Database.Delete
+func TestDatabase_Put(t *testing.T) {
+	db, err := Unmarshal([]byte(testDatabase))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	c := *db.Find("Classic")
+	c.Holes = c.Holes[:1]
+	if err := db.Put(c); err != nil {
+		t.Fatalf("put error: %v", err)
+	}
+	if len(db.Courses) != 1 || len(db.Find("Classic").Holes) != 1 {
+		t.Errorf("expected course replaced, got %+v", db.Courses)
+	}
+	c.Name = "Resort"
+	if err := db.Put(c); err != nil {
+		t.Fatalf("put error: %v", err)
+	}
+	if len(db.Courses) != 2 {
+		t.Errorf("expected 2 courses, got %d", len(db.Courses))
+	}
+	if !db.Delete("Classic") || db.Delete("Classic") || db.Find("Resort") == nil {
+		t.Errorf("unexpected delete result: %+v", db.Courses)
+	}
+
+	data, err := json.Marshal(db)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	if _, err := Unmarshal(data); err != nil {
+		t.Errorf("round trip error: %v", err)
+	}
+}
+
+// TestPlayer 测试 Player
+func TestPlayer(t *testing.T) {
+	db, err := Unmarshal([]byte(testDatabase))
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	p := NewPlayer(db.Find("Classic"))
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		n, shot := p.Shot()
+		if shot == nil {
+			t.Fatalf("step %d: no shot", i)
+		}
+		got = append(got, string(rune('0'+p.Hole().Number))+"/"+string(rune('1'+n))+" "+shot.Club)
+		p.Next()
+	}
+	expected := []string{"1/1 5-Iron", "1/2 Putter", "2/1 Driver", "1/1 5-Iron"}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, got)
+			break
+		}
+	}
+
+	p.SetHole(1)
+	if p.HoleIndex() != 1 || p.Hole().Number != 2 {
+		t.Errorf("expected hole 2, got %+v", p.Hole())
+	}
+	p.SetHole(5)
+	if p.HoleIndex() != 1 {
+		t.Errorf("expected invalid hole ignored, got %d", p.HoleIndex())
+	}
+}
@@ -0,0 +1,99 @@
+package display
+
+import (
+	"fmt"
+	"image/color"
+
+	"tinygo.org/x/drivers"
+)
+
+// DefaultI2CAddress SH1106 和 SSD1306 的默认 I2C 地址
+const DefaultI2CAddress uint16 = 0x3c
+
+// Type 显示器类型
+type Type uint8
+
+const (
+	// TypeNone 无显示器
+	TypeNone Type = iota
+	// TypeSH1106I2C 通过 I2C 连接的 SH1106 单色 OLED
+	TypeSH1106I2C
+	// TypeSSD1306I2C 通过 I2C 连接的 SSD1306 单色 OLED
+	TypeSSD1306I2C
+	// TypeSSD1306SPI 通过 SPI 连接的 SSD1306 单色 OLED
+	TypeSSD1306SPI
+	// TypeST7789 通过 SPI 连接的 ST7789 彩色 TFT
+	TypeST7789
+)
+
+// String 返回类型名
+func (t Type) String() string {
+	switch t {
+	case TypeNone:
+		return "none"
+	case TypeSH1106I2C:
+		return "sh1106-i2c"
+	case TypeSSD1306I2C:
+		return "ssd1306-i2c"
+	case TypeSSD1306SPI:
+		return "ssd1306-spi"
+	case TypeST7789:
+		return "st7789"
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(t))
+}
+
+// IsColor 返回是否为彩色显示器
+func (t Type) IsColor() bool {
+	return t == TypeST7789
+}
+
+// UsesI2C 返回是否通过 I2C 连接
+func (t Type) UsesI2C() bool {
+	return t == TypeSH1106I2C || t == TypeSSD1306I2C
+}
+
+// UsesSPI 返回是否通过 SPI 连接
+func (t Type) UsesSPI() bool {
+	return t == TypeSSD1306SPI || t == TypeST7789
+}
+
+// Rotate 返回以软件方式将 d 顺时针旋转 rotation 后的 drivers.Displayer
+// 用于驱动本身不支持旋转的显示器
+func Rotate(d drivers.Displayer, rotation drivers.Rotation) drivers.Displayer {
+	if rotation%4 == drivers.Rotation0 {
+		return d
+	}
+	return &rotated{Displayer: d, rotation: rotation % 4}
+}
+
+// rotated 以软件方式旋转的显示器
+type rotated struct {
+	drivers.Displayer
+	rotation drivers.Rotation
+}
+
+var _ drivers.Displayer = (*rotated)(nil)
+
+// Size 返回旋转后的尺寸
+func (r *rotated) Size() (x, y int16) {
+	w, h := r.Displayer.Size()
+	if r.rotation == drivers.Rotation90 || r.rotation == drivers.Rotation270 {
+		return h, w
+	}
+	return w, h
+}
+
+// SetPixel 设置旋转后坐标系中的像素
+func (r *rotated) SetPixel(x, y int16, c color.RGBA) {
+	w, h := r.Displayer.Size()
+	switch r.rotation {
+	case drivers.Rotation90:
+		x, y = w-1-y, x
+	case drivers.Rotation180:
+		x, y = w-1-x, h-1-y
+	case drivers.Rotation270:
+		x, y = y, h-1-x
+	}
+	r.Displayer.SetPixel(x, y, c)
+}
@@ -0,0 +1,36 @@
+package display
+
+import (
+	"image/color"
+	"testing"
+
+	"tinygo.org/x/drivers"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/memdisplay"
+)
+
+// TestRotate 测试 Rotate
+func TestRotate(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	cases := []struct {
+		rotation     drivers.Rotation
+		expectedSize [2]int16
+		expectedXY   [2]int16
+	}{
+		{rotation: drivers.Rotation0, expectedSize: [2]int16{8, 4}, expectedXY: [2]int16{1, 0}},
+		{rotation: drivers.Rotation90, expectedSize: [2]int16{4, 8}, expectedXY: [2]int16{7, 1}},
+		{rotation: drivers.Rotation180, expectedSize: [2]int16{8, 4}, expectedXY: [2]int16{6, 3}},
+		{rotation: drivers.Rotation270, expectedSize: [2]int16{4, 8}, expectedXY: [2]int16{0, 2}},
+	}
+	for _, c := range cases {
+		d := memdisplay.New(8, 4)
+		r := Rotate(d, c.rotation)
+		if w, h := r.Size(); w != c.expectedSize[0] || h != c.expectedSize[1] {
+			t.Errorf("rotation %d: expected size %v, got (%d, %d)", c.rotation, c.expectedSize, w, h)
+		}
+		r.SetPixel(1, 0, white)
+		if d.Pixel(c.expectedXY[0], c.expectedXY[1]) != white {
+			t.Errorf("rotation %d: expected pixel at %v, got:\n%s", c.rotation, c.expectedXY, d.ASCII())
+		}
+	}
+}
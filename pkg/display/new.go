@@ -0,0 +1,85 @@
+//go:build tinygo
+
+package display
+
+import (
+	"fmt"
+	"image/color"
+	"machine"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/sh1106"
+	"tinygo.org/x/drivers/ssd1306"
+	"tinygo.org/x/drivers/st7789"
+)
+
+// Config 显示器配置
+type Config struct {
+	// 显示器类型
+	Type Type
+	// 显示器宽度（旋转前）
+	Width int16
+	// 显示器高度（旋转前）
+	Height int16
+	// 顺时针旋转
+	Rotation drivers.Rotation
+
+	// I2C 总线，需已配置，用于 I2C 显示器
+	I2C drivers.I2C
+	// I2C 地址，为 0 时使用驱动默认地址
+	Address uint16
+
+	// SPI 总线，需已配置，用于 SPI 显示器
+	SPI drivers.SPI
+	// 数据/命令选择针脚
+	DCPin machine.Pin
+	// 复位针脚
+	ResetPin machine.Pin
+	// 片选针脚
+	CSPin machine.Pin
+	// 背光针脚，仅用于 TFT
+	BacklightPin machine.Pin
+}
+
+// New 基于配置创建并初始化显示器
+// 若 cfg.Type 为 TypeNone 则返回 nil
+func New(cfg Config) (drivers.Displayer, error) {
+	switch cfg.Type {
+	case TypeNone:
+		return nil, nil
+	case TypeSH1106I2C:
+		if cfg.I2C == nil {
+			return nil, fmt.Errorf("display %s requires i2c bus", cfg.Type)
+		}
+		d := sh1106.NewI2C(cfg.I2C)
+		d.Configure(sh1106.Config{Width: cfg.Width, Height: cfg.Height, Address: cfg.Address})
+		d.ClearDisplay()
+		return Rotate(&d, cfg.Rotation), nil
+	case TypeSSD1306I2C:
+		if cfg.I2C == nil {
+			return nil, fmt.Errorf("display %s requires i2c bus", cfg.Type)
+		}
+		d := ssd1306.NewI2C(cfg.I2C)
+		d.Configure(ssd1306.Config{Width: cfg.Width, Height: cfg.Height, Address: cfg.Address})
+		d.ClearDisplay()
+		return Rotate(&d, cfg.Rotation), nil
+	case TypeSSD1306SPI:
+		if cfg.SPI == nil {
+			return nil, fmt.Errorf("display %s requires spi bus", cfg.Type)
+		}
+		d := ssd1306.NewSPI(cfg.SPI, cfg.DCPin, cfg.ResetPin, cfg.CSPin)
+		d.Configure(ssd1306.Config{Width: cfg.Width, Height: cfg.Height})
+		d.ClearDisplay()
+		return Rotate(&d, cfg.Rotation), nil
+	case TypeST7789:
+		if cfg.SPI == nil {
+			return nil, fmt.Errorf("display %s requires spi bus", cfg.Type)
+		}
+		d := st7789.New(cfg.SPI, cfg.ResetPin, cfg.DCPin, cfg.CSPin, cfg.BacklightPin)
+		d.Configure(st7789.Config{Width: cfg.Width, Height: cfg.Height, Rotation: cfg.Rotation})
+		d.FillScreen(color.RGBA{A: 255})
+		d.EnableBacklight(true)
+		return &d, nil
+	}
+	return nil, fmt.Errorf("unknown display type: %s", cfg.Type)
+}
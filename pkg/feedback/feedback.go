@@ -0,0 +1,238 @@
+package feedback
+
+import (
+	"image/color"
+	"sync"
+	"time"
+)
+
+// DefaultInterval Player 默认更新间隔
+const DefaultInterval = 10 * time.Millisecond
+
+// Tone 蜂鸣器
+type Tone interface {
+	// SetTone 以 freq （单位： Hz ）发声，为 0 时静音
+	SetTone(freq uint32)
+}
+
+// Light 状态灯
+type Light interface {
+	// SetColor 设置颜色，全 0 时熄灭
+	SetColor(c color.RGBA)
+}
+
+// Step 提示模式中的一步
+type Step struct {
+	// 持续时长
+	Duration time.Duration
+	// 蜂鸣器频率（单位： Hz ），为 0 时静音
+	Freq uint32
+	// 状态灯颜色，全 0 时熄灭
+	Color color.RGBA
+}
+
+// Pattern 由若干步组成的声光提示模式
+type Pattern struct {
+	// 名字
+	Name string
+	// 各步
+	Steps []Step
+}
+
+// duration 返回总时长
+func (p *Pattern) duration() time.Duration {
+	var d time.Duration
+	for _, s := range p.Steps {
+		d += s.Duration
+	}
+	return d
+}
+
+// 音符频率
+const (
+	NoteA3 uint32 = 220
+	NoteA4 uint32 = 440
+	NoteC5 uint32 = 523
+	NoteE5 uint32 = 659
+	NoteG5 uint32 = 784
+	NoteA5 uint32 = 880
+	NoteC6 uint32 = 1047
+)
+
+// 状态颜色
+var (
+	ColorOff    = color.RGBA{}
+	ColorGreen  = color.RGBA{G: 0x40, A: 0xff}
+	ColorAmber  = color.RGBA{R: 0x40, G: 0x20, A: 0xff}
+	ColorBlue   = color.RGBA{B: 0x40, A: 0xff}
+	ColorRed    = color.RGBA{R: 0x40, A: 0xff}
+	ColorOrange = color.RGBA{R: 0x40, G: 0x10, A: 0xff}
+)
+
+// 预定义的提示模式
+var (
+	// BootOK 开机完成，上行三音
+	BootOK = &Pattern{Name: "boot-ok", Steps: []Step{
+		{Duration: 100 * time.Millisecond, Freq: NoteC5, Color: ColorGreen},
+		{Duration: 100 * time.Millisecond, Freq: NoteE5, Color: ColorGreen},
+		{Duration: 150 * time.Millisecond, Freq: NoteG5, Color: ColorGreen},
+		{Duration: 350 * time.Millisecond, Color: ColorGreen},
+	}}
+	// Armed 已准备挥杆，琥珀色慢闪，每次亮起时短促提示
+	Armed = &Pattern{Name: "armed", Steps: []Step{
+		{Duration: 50 * time.Millisecond, Freq: NoteA5, Color: ColorAmber},
+		{Duration: 450 * time.Millisecond, Color: ColorAmber},
+		{Duration: 500 * time.Millisecond},
+	}}
+	// Swinging 正在挥杆，蓝色常亮
+	Swinging = &Pattern{Name: "swinging", Steps: []Step{
+		{Duration: time.Second, Color: ColorBlue},
+	}}
+	// Fault 故障，红色闪烁并低音报警
+	Fault = &Pattern{Name: "fault", Steps: []Step{
+		{Duration: 300 * time.Millisecond, Freq: NoteA3, Color: ColorRed},
+		{Duration: 200 * time.Millisecond, Color: ColorRed},
+		{Duration: 500 * time.Millisecond},
+	}}
+	// LowBattery 电量低，橙色双闪双响
+	LowBattery = &Pattern{Name: "low-battery", Steps: []Step{
+		{Duration: 100 * time.Millisecond, Freq: NoteA4, Color: ColorOrange},
+		{Duration: 100 * time.Millisecond},
+		{Duration: 100 * time.Millisecond, Freq: NoteA4, Color: ColorOrange},
+		{Duration: 700 * time.Millisecond},
+	}}
+)
+
+// Beep 返回以 freq 发声并点亮 c 持续 d 的提示模式
+func Beep(freq uint32, d time.Duration, c color.RGBA) *Pattern {
+	return &Pattern{Name: "beep", Steps: []Step{{Duration: d, Freq: freq, Color: c}}}
+}
+
+// Player 非阻塞地播放声光提示模式
+//
+// 背景模式表示持续的状态（如已准备、正在挥杆、故障），循环播放；
+// 前景模式表示一次性的事件（如开机完成、倒计时），播放一次，期间暂停背景模式，结束后从头继续播放背景模式。
+// 播放进度由 Update 推进，可调用 Start 在后台定时推进。
+type Player struct {
+	// 蜂鸣器，可以为 nil
+	Tone Tone
+	// 状态灯，可以为 nil
+	Light Light
+
+	lock       sync.Mutex
+	background *Pattern
+	foreground *Pattern
+	current    *Pattern
+	step       int
+	stepEnd    time.Time
+	restart    bool
+
+	started bool
+	freq    uint32
+	color   color.RGBA
+}
+
+// Play 从下次 Update 开始播放一次 pattern ，替换正在播放的前景模式
+func (p *Player) Play(pattern *Pattern) {
+	if pattern == nil || pattern.duration() <= 0 {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.foreground = pattern
+	p.restart = true
+}
+
+// SetBackground 设置循环播放的背景模式，为 nil 时停止
+func (p *Player) SetBackground(pattern *Pattern) {
+	if pattern != nil && pattern.duration() <= 0 {
+		pattern = nil
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if pattern == p.background {
+		return
+	}
+	p.background = pattern
+	if p.foreground == nil {
+		p.restart = true
+	}
+}
+
+// Background 返回当前背景模式
+func (p *Player) Background() *Pattern {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.background
+}
+
+// Update 推进播放进度到 now ，并更新蜂鸣器和状态灯
+func (p *Player) Update(now time.Time) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.restart {
+		p.restart = false
+		p.begin(now)
+	}
+	for p.current != nil && !now.Before(p.stepEnd) {
+		p.step++
+		if p.step < len(p.current.Steps) {
+			p.stepEnd = p.stepEnd.Add(p.current.Steps[p.step].Duration)
+			continue
+		}
+		if p.current == p.foreground {
+			// 前景模式播放完毕，回到背景模式
+			p.foreground = nil
+			p.begin(now)
+			continue
+		}
+		// 背景模式循环播放
+		p.step = 0
+		p.stepEnd = p.stepEnd.Add(p.current.Steps[0].Duration)
+	}
+
+	var s Step
+	if p.current != nil {
+		s = p.current.Steps[p.step]
+	}
+	p.output(s.Freq, s.Color)
+}
+
+// Start 在后台每隔 interval 推进一次播放进度，为 0 时使用 DefaultInterval
+func (p *Player) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	go func() {
+		for {
+			p.Update(time.Now())
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// begin 从 now 开始播放前景模式，没有时播放背景模式
+func (p *Player) begin(now time.Time) {
+	p.current = p.foreground
+	if p.current == nil {
+		p.current = p.background
+	}
+	p.step = 0
+	if p.current != nil {
+		p.stepEnd = now.Add(p.current.Steps[0].Duration)
+	}
+}
+
+// output 更新有变化的输出
+func (p *Player) output(freq uint32, c color.RGBA) {
+	if p.Tone != nil && (!p.started || freq != p.freq) {
+		p.Tone.SetTone(freq)
+	}
+	if p.Light != nil && (!p.started || c != p.color) {
+		p.Light.SetColor(c)
+	}
+	p.started = true
+	p.freq = freq
+	p.color = c
+}
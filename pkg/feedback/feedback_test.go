@@ -0,0 +1,107 @@
+package feedback
+
+import (
+	"fmt"
+	"image/color"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeTimeline 记录输出变化的假针脚
+type fakeTimeline struct {
+	now    *time.Duration
+	events []string
+}
+
+// SetTone 记录蜂鸣器变化
+func (f *fakeTimeline) SetTone(freq uint32) {
+	f.events = append(f.events, fmt.Sprintf("%d tone %d", f.now.Milliseconds(), freq))
+}
+
+// SetColor 记录状态灯变化
+func (f *fakeTimeline) SetColor(c color.RGBA) {
+	f.events = append(f.events, fmt.Sprintf("%d light %02x%02x%02x", f.now.Milliseconds(), c.R, c.G, c.B))
+}
+
+// newTestPlayer 创建输出到假针脚的 Player ，返回 Player 、时间线和以 10ms 间隔推进到指定时刻的函数
+func newTestPlayer() (*Player, *fakeTimeline, func(ms int)) {
+	var now time.Duration
+	tl := &fakeTimeline{now: &now}
+	p := &Player{Tone: tl, Light: tl}
+	start := time.Unix(0, 0)
+	advance := func(ms int) {
+		for ; now <= time.Duration(ms)*time.Millisecond; now += 10 * time.Millisecond {
+			p.Update(start.Add(now))
+		}
+		now -= 10 * time.Millisecond
+	}
+	return p, tl, advance
+}
+
+// TestPlayer_Foreground 测试一次性模式播放后回到背景模式
+func TestPlayer_Foreground(t *testing.T) {
+	p, tl, advance := newTestPlayer()
+	p.SetBackground(Swinging)
+	p.Play(&Pattern{Steps: []Step{
+		{Duration: 50 * time.Millisecond, Freq: 1000, Color: ColorGreen},
+		{Duration: 30 * time.Millisecond, Color: ColorGreen},
+	}})
+	advance(200)
+
+	expected := []string{
+		"0 tone 1000",
+		"0 light 004000",
+		"50 tone 0",
+		"80 light 000040",
+	}
+	if !reflect.DeepEqual(tl.events, expected) {
+		t.Errorf("expected %v, got %v", expected, tl.events)
+	}
+}
+
+// TestPlayer_Background 测试背景模式循环播放和切换
+func TestPlayer_Background(t *testing.T) {
+	p, tl, advance := newTestPlayer()
+	p.SetBackground(Fault)
+	advance(1500)
+	p.SetBackground(nil)
+	advance(1600)
+
+	expected := []string{
+		"0 tone 220",
+		"0 light 400000",
+		"300 tone 0",
+		"500 light 000000",
+		"1000 tone 220",
+		"1000 light 400000",
+		"1300 tone 0",
+		"1500 light 000000",
+	}
+	if !reflect.DeepEqual(tl.events, expected) {
+		t.Errorf("expected %v, got %v", expected, tl.events)
+	}
+
+	// 设置相同的背景模式不会从头播放
+	p.SetBackground(Armed)
+	advance(1700)
+	p.SetBackground(Armed)
+	advance(2200)
+	if last := tl.events[len(tl.events)-1]; last != "2100 light 000000" {
+		t.Errorf("unexpected last event %q in %v", last, tl.events)
+	}
+}
+
+// TestPlayer_Empty 测试空模式被忽略
+func TestPlayer_Empty(t *testing.T) {
+	p, tl, advance := newTestPlayer()
+	p.Play(&Pattern{})
+	p.SetBackground(&Pattern{Steps: []Step{{Color: ColorRed}}})
+	advance(100)
+	if !reflect.DeepEqual(tl.events, []string{"0 tone 0", "0 light 000000"}) {
+		t.Errorf("unexpected events %v", tl.events)
+	}
+	if p.Background() != nil {
+		t.Errorf("expected no background")
+	}
+}
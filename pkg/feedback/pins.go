@@ -0,0 +1,106 @@
+//go:build tinygo
+
+package feedback
+
+import (
+	"image/color"
+	"machine"
+
+	"tinygo.org/x/drivers/ws2812"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/pwm"
+)
+
+// PinTone 有源蜂鸣器，高电平发声，只能以固定音调发声
+type PinTone struct {
+	Pin machine.Pin
+}
+
+var _ Tone = (*PinTone)(nil)
+
+// NewPinTone 配置 pin 并创建 *PinTone
+func NewPinTone(pin machine.Pin) *PinTone {
+	pin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	pin.Low()
+	return &PinTone{Pin: pin}
+}
+
+// SetTone freq 不为 0 时发声
+func (t *PinTone) SetTone(freq uint32) {
+	t.Pin.Set(freq > 0)
+}
+
+// PWMTone 以 PWM 驱动的无源压电蜂鸣器，可发出不同音调
+type PWMTone struct {
+	pwm pwm.Group
+	ch  uint8
+}
+
+var _ Tone = (*PWMTone)(nil)
+
+// NewPWMTone 配置 pin 为 PWM 输出并创建 *PWMTone
+func NewPWMTone(pin machine.Pin) (*PWMTone, error) {
+	group, err := pwm.ForPin(pin)
+	if err != nil {
+		return nil, err
+	}
+	if err := group.Configure(machine.PWMConfig{Period: 1e9 / uint64(NoteA4)}); err != nil {
+		return nil, err
+	}
+	ch, err := group.Channel(pin)
+	if err != nil {
+		return nil, err
+	}
+	group.Set(ch, 0)
+	return &PWMTone{pwm: group, ch: ch}, nil
+}
+
+// SetTone 以 50% 占空比发出 freq 的方波， 0 时静音
+func (t *PWMTone) SetTone(freq uint32) {
+	if freq == 0 {
+		t.pwm.Set(t.ch, 0)
+		return
+	}
+	if err := t.pwm.SetPeriod(1e9 / uint64(freq)); err != nil {
+		t.pwm.Set(t.ch, 0)
+		return
+	}
+	t.pwm.Set(t.ch, t.pwm.Top()/2)
+}
+
+// PinLight 单色状态 LED ，高电平点亮，颜色不全为 0 时点亮
+type PinLight struct {
+	Pin machine.Pin
+}
+
+var _ Light = (*PinLight)(nil)
+
+// NewPinLight 配置 pin 并创建 *PinLight
+func NewPinLight(pin machine.Pin) *PinLight {
+	pin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	pin.Low()
+	return &PinLight{Pin: pin}
+}
+
+// SetColor 颜色不全为 0 时点亮
+func (l *PinLight) SetColor(c color.RGBA) {
+	l.Pin.Set(c.R|c.G|c.B != 0)
+}
+
+// PixelLight 单个 WS2812 彩色状态灯
+type PixelLight struct {
+	dev ws2812.Device
+}
+
+var _ Light = (*PixelLight)(nil)
+
+// NewPixelLight 配置 pin 并创建 *PixelLight
+func NewPixelLight(pin machine.Pin) *PixelLight {
+	pin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	return &PixelLight{dev: ws2812.NewWS2812(pin)}
+}
+
+// SetColor 设置颜色
+func (l *PixelLight) SetColor(c color.RGBA) {
+	_ = l.dev.WriteColors([]color.RGBA{c})
+}
@@ -0,0 +1,101 @@
+package golfclubs
+
+import (
+	"errors"
+	"fmt"
+
+	"tinygo.org/x/drivers"
+)
+
+const (
+	// AS5600Address AS5600 默认 I2C 地址
+	AS5600Address uint16 = 0x36
+
+	as5600RegStatus   uint8 = 0x0B
+	as5600RegRawAngle uint8 = 0x0C
+
+	as5600StatusMagnetHigh     uint8 = 1 << 3
+	as5600StatusMagnetLow      uint8 = 1 << 4
+	as5600StatusMagnetDetected uint8 = 1 << 5
+)
+
+// ErrNoMagnet 未检测到磁铁
+var ErrNoMagnet = errors.New("as5600 magnet not detected")
+
+// AS5600 基于 AS5600 磁编码器的位置传感器
+//
+// AS5600 只能测量一圈内的角度，需要至少每半圈读取一次以累计多圈位置
+type AS5600 struct {
+	// I2C 总线
+	Bus drivers.I2C
+	// I2C 地址
+	// 默认为 AS5600Address
+	Address uint16
+
+	started bool
+	last    int32
+	turns   int32
+}
+
+var _ PositionSensor = (*AS5600)(nil)
+
+// Configure 检查磁铁状态
+func (s *AS5600) Configure() error {
+	status, err := s.Status()
+	if err != nil {
+		return err
+	}
+	if status&as5600StatusMagnetDetected == 0 {
+		return ErrNoMagnet
+	}
+	if status&(as5600StatusMagnetHigh|as5600StatusMagnetLow) != 0 {
+		return fmt.Errorf("as5600 magnet too strong or too weak (status %#x)", status)
+	}
+	return nil
+}
+
+// Status 读取状态寄存器
+func (s *AS5600) Status() (uint8, error) {
+	buf := []byte{0}
+	if err := s.Bus.Tx(s.address(), []byte{as5600RegStatus}, buf); err != nil {
+		return 0, fmt.Errorf("read as5600 status error: %w", err)
+	}
+	return buf[0], nil
+}
+
+// RawAngle 读取一圈内的原始角度， 0 到 4095
+func (s *AS5600) RawAngle() (int32, error) {
+	buf := []byte{0, 0}
+	if err := s.Bus.Tx(s.address(), []byte{as5600RegRawAngle}, buf); err != nil {
+		return 0, fmt.Errorf("read as5600 raw angle error: %w", err)
+	}
+	return int32(buf[0]&0x0f)<<8 | int32(buf[1]), nil
+}
+
+// Position 返回电机轴累计转过的角度
+func (s *AS5600) Position() (int32, error) {
+	angle, err := s.RawAngle()
+	if err != nil {
+		return 0, err
+	}
+	if s.started {
+		// 跨越零点时累计圈数
+		switch diff := angle - s.last; {
+		case diff > PositionUnitsPerCircle/2:
+			s.turns--
+		case diff < -PositionUnitsPerCircle/2:
+			s.turns++
+		}
+	}
+	s.started = true
+	s.last = angle
+	return s.turns*PositionUnitsPerCircle + angle, nil
+}
+
+// address 返回 I2C 地址
+func (s *AS5600) address() uint16 {
+	if s.Address == 0 {
+		return AS5600Address
+	}
+	return s.Address
+}
@@ -0,0 +1,114 @@
+package golfclubs
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultMotorSteps 电机旋转一周默认所需整步数（ 1.8° 步距角）
+	DefaultMotorSteps uint32 = 200
+	// DefaultMicrosteps 默认细分数
+	DefaultMicrosteps uint32 = 8
+)
+
+// DriverConfig 步进电机驱动器配置
+//
+// 零值表示 TB6600 ，电机 200 步每圈、 8 细分
+type DriverConfig struct {
+	// 驱动器名
+	Name string
+	// 使能信号高电平有效，为 false 时高电平禁用电机
+	EnableActiveHigh bool
+	// 方向信号取反
+	InvertDir bool
+	// 脉冲信号最小高（低）电平持续时间
+	MinPulseWidth time.Duration
+	// 方向信号变化后到下一个脉冲前需等待的时间
+	DirSetupDelay time.Duration
+	// 使能后到第一个脉冲前需等待的时间
+	EnableSettleDelay time.Duration
+	// 电机旋转一周所需整步数
+	// 默认为 DefaultMotorSteps
+	MotorSteps uint32
+	// 细分数
+	// 默认为 DefaultMicrosteps
+	Microsteps uint32
+}
+
+var (
+	// DriverTB6600 TB6600 驱动器，共阳极接法
+	DriverTB6600 = DriverConfig{
+		Name:              "TB6600",
+		MinPulseWidth:     2500 * time.Nanosecond,
+		DirSetupDelay:     5 * time.Microsecond,
+		EnableSettleDelay: 5 * time.Microsecond,
+		Microsteps:        8,
+	}
+	// DriverA4988 A4988 驱动器
+	DriverA4988 = DriverConfig{
+		Name:              "A4988",
+		MinPulseWidth:     time.Microsecond,
+		DirSetupDelay:     200 * time.Nanosecond,
+		EnableSettleDelay: time.Millisecond,
+		Microsteps:        16,
+	}
+	// DriverDRV8825 DRV8825 驱动器
+	DriverDRV8825 = DriverConfig{
+		Name:              "DRV8825",
+		MinPulseWidth:     1900 * time.Nanosecond,
+		DirSetupDelay:     650 * time.Nanosecond,
+		EnableSettleDelay: 1700 * time.Microsecond,
+		Microsteps:        32,
+	}
+	// DriverTMC2209 以 step/dir 独立模式工作的 TMC2209 驱动器
+	DriverTMC2209 = DriverConfig{
+		Name:              "TMC2209",
+		MinPulseWidth:     100 * time.Nanosecond,
+		DirSetupDelay:     20 * time.Nanosecond,
+		EnableSettleDelay: time.Millisecond,
+		Microsteps:        8,
+	}
+)
+
+// Drivers 所有内置驱动器配置
+var Drivers = []*DriverConfig{&DriverTB6600, &DriverA4988, &DriverDRV8825, &DriverTMC2209}
+
+// PulsesPerCircle 返回电机旋转一周所需脉冲数
+func (d *DriverConfig) PulsesPerCircle() uint32 {
+	steps := d.MotorSteps
+	if steps == 0 {
+		steps = DefaultMotorSteps
+	}
+	return steps * d.EffectiveMicrosteps()
+}
+
+// EffectiveMicrosteps 返回实际使用的细分数， Microsteps 为 0 时为 DefaultMicrosteps
+func (d *DriverConfig) EffectiveMicrosteps() uint32 {
+	if d.Microsteps == 0 {
+		return DefaultMicrosteps
+	}
+	return d.Microsteps
+}
+
+// MinPeriod 返回 50% 占空比下满足最小脉冲宽度的最小脉冲周期（单位：纳秒）
+func (d *DriverConfig) MinPeriod() uint64 {
+	return 2 * uint64(d.MinPulseWidth.Nanoseconds())
+}
+
+// ErrStalled 电机堵转
+var ErrStalled = errors.New("motor stalled")
+
+// ErrEmergencyStop 急停
+var ErrEmergencyStop = errors.New("emergency stop")
+
+// StallMinRPM 检测堵转的最低转速（单位： rpm ），低于该转速时电机反电动势太小，负载值不可靠
+const StallMinRPM float32 = 30
+
+// StallDetector 堵转检测
+//
+// 转速低于 StallMinRPM 或刚加减速时不检测堵转。
+type StallDetector interface {
+	// Stalled 返回电机是否堵转
+	Stalled() (bool, error)
+}
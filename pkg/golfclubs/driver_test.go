@@ -0,0 +1,43 @@
+package golfclubs
+
+import "testing"
+
+// TestDriverConfig_PulsesPerCircle 测试 DriverConfig.PulsesPerCircle
+func TestDriverConfig_PulsesPerCircle(t *testing.T) {
+	cases := []struct {
+		driver   DriverConfig
+		expected uint32
+	}{
+		{driver: DriverConfig{}, expected: 1600},
+		{driver: DriverTB6600, expected: 1600},
+		{driver: DriverA4988, expected: 3200},
+		{driver: DriverDRV8825, expected: 6400},
+		{driver: DriverTMC2209, expected: 1600},
+		{driver: DriverConfig{MotorSteps: 400, Microsteps: 4}, expected: 1600},
+	}
+	for _, c := range cases {
+		if got := c.driver.PulsesPerCircle(); got != c.expected {
+			t.Errorf("driver %q: expected %d, got %d", c.driver.Name, c.expected, got)
+		}
+	}
+}
+
+// TestDriverConfig_EffectiveMicrosteps 测试 DriverConfig.EffectiveMicrosteps
+func TestDriverConfig_EffectiveMicrosteps(t *testing.T) {
+	if got := (&DriverConfig{}).EffectiveMicrosteps(); got != DefaultMicrosteps {
+		t.Errorf("expected default %d, got %d", DefaultMicrosteps, got)
+	}
+	if got := DriverDRV8825.EffectiveMicrosteps(); got != 32 {
+		t.Errorf("expected 32, got %d", got)
+	}
+}
+
+// TestDriverConfig_MinPeriod 测试 DriverConfig.MinPeriod
+func TestDriverConfig_MinPeriod(t *testing.T) {
+	if got := DriverTB6600.MinPeriod(); got != 5000 {
+		t.Errorf("expected 5000, got %d", got)
+	}
+	if got := (&DriverConfig{}).MinPeriod(); got != 0 {
+		t.Errorf("expected 0, got %d", got)
+	}
+}
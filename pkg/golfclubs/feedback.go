@@ -0,0 +1,113 @@
+package golfclubs
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// PositionUnitsPerCircle 位置传感器每圈的单位数
+	PositionUnitsPerCircle int32 = 4096
+	// DefaultFeedbackTolerance 默认允许的指令位置与实测位置最大偏差， 5% 圈
+	DefaultFeedbackTolerance = PositionUnitsPerCircle * 5 / 100
+)
+
+// ErrPositionMismatch 指令位置与实测位置不一致，通常是丢步或被阻挡
+var ErrPositionMismatch = errors.New("position mismatch")
+
+// PositionSensor 电机轴位置传感器
+type PositionSensor interface {
+	// Position 返回电机轴累计转过的角度，单位为 1/PositionUnitsPerCircle 圈
+	Position() (int32, error)
+}
+
+// Counter 计数器，如 *encoder.Encoder
+type Counter interface {
+	// Value 当前值
+	Value() int32
+}
+
+// CounterSensor 基于正交编码器计数的位置传感器
+type CounterSensor struct {
+	// 编码器
+	Counter Counter
+	// 电机轴旋转一周编码器计数
+	CountsPerCircle int32
+}
+
+var _ PositionSensor = (*CounterSensor)(nil)
+
+// Position 返回电机轴累计转过的角度
+func (s *CounterSensor) Position() (int32, error) {
+	if s.CountsPerCircle == 0 {
+		return 0, errors.New("counts per circle not set")
+	}
+	return int32(int64(s.Counter.Value()) * int64(PositionUnitsPerCircle) / int64(s.CountsPerCircle)), nil
+}
+
+// Feedback 基于位置传感器检测电机是否按指令转动
+type Feedback struct {
+	// 位置传感器
+	Sensor PositionSensor
+	// 允许的指令位置与实测位置最大偏差，单位为 1/PositionUnitsPerCircle 圈
+	// 默认为 DefaultFeedbackTolerance
+	Tolerance int32
+	// 传感器正方向与向前挥杆方向相反
+	Invert bool
+
+	zero      int32
+	deviation int32
+}
+
+// Reset 将当前实测位置对应到指令位置 commanded
+func (f *Feedback) Reset(commanded int32) error {
+	pos, err := f.Sensor.Position()
+	if err != nil {
+		return fmt.Errorf("read position error: %w", err)
+	}
+	if f.Invert {
+		pos = -pos
+	}
+	f.zero = pos - commanded
+	f.deviation = 0
+	return nil
+}
+
+// Measured 返回以指令位置为参考的实测位置
+func (f *Feedback) Measured() (int32, error) {
+	pos, err := f.Sensor.Position()
+	if err != nil {
+		return 0, fmt.Errorf("read position error: %w", err)
+	}
+	if f.Invert {
+		pos = -pos
+	}
+	return pos - f.zero, nil
+}
+
+// Check 比较指令位置 commanded 与实测位置，偏差超出允许范围时返回 ErrPositionMismatch
+func (f *Feedback) Check(commanded int32) error {
+	pos, err := f.Sensor.Position()
+	if err != nil {
+		return fmt.Errorf("read position error: %w", err)
+	}
+	if f.Invert {
+		pos = -pos
+	}
+	measured := pos - f.zero
+	f.deviation = measured - commanded
+
+	tolerance := f.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultFeedbackTolerance
+	}
+	if f.deviation > tolerance || f.deviation < -tolerance {
+		return fmt.Errorf("%w: commanded %d, measured %d", ErrPositionMismatch, commanded, measured)
+	}
+	return nil
+}
+
+// Deviation 返回最近一次检测时实测位置与指令位置的偏差
+func (f *Feedback) Deviation() int32 {
+	return f.deviation
+}
@@ -0,0 +1,116 @@
+package golfclubs
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeSensor 返回固定位置的传感器
+type fakeSensor struct {
+	pos int32
+}
+
+// Position 返回电机轴累计转过的角度
+func (s *fakeSensor) Position() (int32, error) {
+	return s.pos, nil
+}
+
+// TestFeedback_Check 测试 Feedback.Check
+func TestFeedback_Check(t *testing.T) {
+	sensor := &fakeSensor{pos: 1000}
+	f := &Feedback{Sensor: sensor, Tolerance: 100, Invert: true}
+	if err := f.Reset(0); err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+
+	// 传感器反向，向前转动时读数减小
+	sensor.pos = 1000 - 2048
+	if err := f.Check(2000); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if f.Deviation() != 48 {
+		t.Errorf("expected deviation 48, got %d", f.Deviation())
+	}
+
+	// 丢步
+	if err := f.Check(2200); !errors.Is(err, ErrPositionMismatch) {
+		t.Errorf("expected position mismatch, got %v", err)
+	}
+}
+
+// TestCounterSensor_Position 测试 CounterSensor.Position
+func TestCounterSensor_Position(t *testing.T) {
+	counter := &fakeCounter{value: -150}
+	s := &CounterSensor{Counter: counter, CountsPerCircle: 600}
+	if pos, err := s.Position(); err != nil || pos != -1024 {
+		t.Errorf("expected -1024, got %d, %v", pos, err)
+	}
+}
+
+// fakeCounter 固定值的计数器
+type fakeCounter struct {
+	value int32
+}
+
+// Value 当前值
+func (c *fakeCounter) Value() int32 {
+	return c.value
+}
+
+// fakeI2C 模拟 AS5600 的 I2C 总线
+type fakeI2C struct {
+	regs map[uint8]byte
+}
+
+// Tx 读寄存器
+func (b *fakeI2C) Tx(addr uint16, w, r []byte) error {
+	if addr != AS5600Address {
+		return errors.New("nack")
+	}
+	for i := range r {
+		r[i] = b.regs[w[0]+uint8(i)]
+	}
+	return nil
+}
+
+// setAngle 设置原始角度
+func (b *fakeI2C) setAngle(angle int32) {
+	b.regs[as5600RegRawAngle] = byte(angle >> 8)
+	b.regs[as5600RegRawAngle+1] = byte(angle)
+}
+
+// TestAS5600_Position 测试 AS5600.Position 累计多圈
+func TestAS5600_Position(t *testing.T) {
+	bus := &fakeI2C{regs: map[uint8]byte{as5600RegStatus: as5600StatusMagnetDetected}}
+	s := &AS5600{Bus: bus}
+	if err := s.Configure(); err != nil {
+		t.Fatalf("configure error: %v", err)
+	}
+
+	steps := []struct {
+		angle    int32
+		expected int32
+	}{
+		{angle: 4000, expected: 4000},
+		{angle: 100, expected: 4196},
+		{angle: 2000, expected: 6096},
+		{angle: 3900, expected: 7996},
+		{angle: 50, expected: 8242},
+		{angle: 4050, expected: 8146},
+	}
+	for _, step := range steps {
+		bus.setAngle(step.angle)
+		pos, err := s.Position()
+		if err != nil {
+			t.Fatalf("read position error: %v", err)
+		}
+		if pos != step.expected {
+			t.Errorf("angle %d: expected %d, got %d", step.angle, step.expected, pos)
+		}
+	}
+
+	bus.regs[as5600RegStatus] = 0
+	if err := s.Configure(); !errors.Is(err, ErrNoMagnet) {
+		t.Errorf("expected no magnet error, got %v", err)
+	}
+}
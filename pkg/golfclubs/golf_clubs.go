@@ -1,18 +1,31 @@
+//go:build tinygo
+
 package golfclubs
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"machine"
 	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/pwm"
 )
 
 const (
 	// DefaultPulsesPerCircle 电机旋转一周默认所需脉冲数
-	DefaultPulsesPerCircle uint32 = 1600
-	// MaxSpeed 最大挥杆速度（单位： rpm ）
-	MaxSpeed uint32 = 400
-	// minSpeedPercent 最小速度百分比
-	minSpeedPercent uint8 = 10
+	DefaultPulsesPerCircle = DefaultMotorSteps * DefaultMicrosteps
+	// monitorInterval 转动时检测堵转和位置的间隔
+	monitorInterval = 10 * time.Millisecond
+	// homeSpeedPercent 归位速度百分比
+	homeSpeedPercent uint8 = 10
+	// homeBackOffPercent 归位碰到限位后回退的圈数百分比
+	homeBackOffPercent uint8 = 2
+	// recordInterval 录制轨迹的采样间隔
+	recordInterval = 20 * time.Millisecond
+	// recordIdle 录制轨迹时静止超过该时长后结束
+	recordIdle = time.Second
+	// recordThreshold 录制轨迹时转动超过该角度（单位：度）后开始
+	recordThreshold float32 = 2
 )
 
 // New 创建一个 GolfClubs
@@ -33,113 +46,88 @@ type GolfClubs struct {
 	// 脱机控制
 	EnPin machine.Pin
 
-	pwm   PWMGroup
-	pwmCh uint8
+	// 脉冲输出和电机使能
+	motorOutput
 
 	reverse         bool
 	pulsesPerCircle uint32
+	driver          DriverConfig
+	dir             bool
+	stall           StallDetector
+	feedback        *Feedback
+	autoRehome      bool
+
+	// 当前是否向前转动
+	forward bool
+	// 指令位置，单位为脉冲，向前为正
+	position int32
+	// 正在归位
+	homing bool
+	// 故障状态，可在其它协程中设置
+	state StateMachine
+	// 最近一次运动的统计
+	lastMotion Motion
+	// 运动观察者
+	observers []MotionObserver
+	// 电机电源
+	supply Supply
+	// 看门狗，运动期间定期喂狗
+	watchdog Watchdog
 }
 
 // PWMGroup PWM 组
-type PWMGroup interface {
-	// Configure enables and configures this PWM.
-	Configure(config machine.PWMConfig) error
-	// Channel returns a PWM channel for the given pin. If pin does
-	// not belong to PWM peripheral ErrInvalidOutputPin error is returned.
-	// It also configures pin as PWM output.
-	Channel(pin machine.Pin) (channel uint8, err error)
-	// SetPeriod updates the period of this PWM peripheral in nanoseconds.
-	// To set a particular frequency, use the following formula:
-	//
-	//	period = 1e9 / frequency
-	//
-	// Where frequency is in hertz. If you use a period of 0, a period
-	// that works well for LEDs will be picked.
-	//
-	// SetPeriod will try not to modify TOP if possible to reach the target period.
-	// If the period is unattainable with current TOP SetPeriod will modify TOP
-	// by the bare minimum to reach the target period. It will also enable phase
-	// correct to reach periods above 130ms.
-	SetPeriod(period uint64) error
-	// Top returns the current counter top, for use in duty cycle calculation.
-	//
-	// The value returned here is hardware dependent. In general, it's best to treat
-	// it as an opaque value that can be divided by some number and passed to Set
-	// (see Set documentation for more information).
-	Top() uint32
-	// Counter returns the current counter value of the timer in this PWM
-	// peripheral. It may be useful for debugging.
-	Counter() uint32
-	// Period returns the used PWM period in nanoseconds.
-	Period() uint64
-	// SetInverting sets whether to invert the output of this channel.
-	// Without inverting, a 25% duty cycle would mean the output is high for 25% of
-	// the time and low for the rest. Inverting flips the output as if a NOT gate
-	// was placed at the output, meaning that the output would be 25% low and 75%
-	// high with a duty cycle of 25%.
-	SetInverting(channel uint8, inverting bool)
-	// Set updates the channel value. This is used to control the channel duty
-	// cycle, in other words the fraction of time the channel output is high (or low
-	// when inverted). For example, to set it to a 25% duty cycle, use:
-	//
-	//	pwm.Set(channel, pwm.Top() / 4)
-	//
-	// pwm.Set(channel, 0) will set the output to low and pwm.Set(channel,
-	// pwm.Top()) will set the output to high, assuming the output isn't inverted.
-	Set(channel uint8, value uint32)
-	// Get current level (last set by Set). Default value on initialization is 0.
-	Get(channel uint8) (value uint32)
-	// SetTop sets TOP control register. Max value is 16bit (0xffff).
-	SetTop(top uint32)
-	// SetCounter sets counter control register. Max value is 16bit (0xffff).
-	// Useful for synchronising two different PWM peripherals.
-	SetCounter(ctr uint32)
-	// Enable enables or disables PWM peripheral channels.
-	Enable(enable bool)
-	// IsEnabled returns true if peripheral is enabled.
-	IsEnabled() (enabled bool)
-}
+type PWMGroup = pwm.Group
 
 // Config 配置
 type Config struct {
 	// 反向挥杆
 	Reverse bool
 	// 电机旋转一周所需脉冲数
-	// 默认为 DefaultPulsesPerCircle
+	// 默认由 Driver 的电机整步数和细分数计算得到
 	PulsesPerCircle uint32
+	// 驱动器配置
+	Driver DriverConfig
+	// 电机电源管理配置
+	Power PowerConfig
 }
 
 // Configure 初始配置
 func (c *GolfClubs) Configure(cfg Config) error {
 	c.reverse = cfg.Reverse
+	c.driver = cfg.Driver
 	c.pulsesPerCircle = cfg.PulsesPerCircle
 	if c.pulsesPerCircle == 0 {
-		c.pulsesPerCircle = DefaultPulsesPerCircle
+		c.pulsesPerCircle = c.driver.PulsesPerCircle()
 	}
 
 	// 配置 GPIO
 	c.DirPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	c.DirPin.Low()
+	c.dir = c.driver.InvertDir
+	c.DirPin.Set(c.dir)
 	c.EnPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	c.EnPin.High() // 先禁用
+	c.disable() // 先禁用
+	c.power = &MotorPower{Enable: c.enable, Disable: c.disable}
+	c.power.SetConfig(cfg.Power)
+	c.state.OnChange = c.stateChanged
 
 	// 获取 PWM 脚对应的 PWM 组
-	pwmI, err := machine.PWMPeripheral(c.PWMPin)
+	group, err := pwm.ForPin(c.PWMPin)
 	if err != nil {
 		return err
 	}
-	c.pwm = getPWMGroup(pwmI)
 
 	// 配置 PWM
-	if err := c.pwm.Configure(machine.PWMConfig{
+	if err := group.Configure(machine.PWMConfig{
 		Period: 1e9 / 1000,
 	}); err != nil {
 		return err
 	}
-	if c.pwmCh, err = c.pwm.Channel(c.PWMPin); err != nil {
+	if c.pwmCh, err = group.Channel(c.PWMPin); err != nil {
 		return err
 	}
-	c.pwm.Set(c.pwmCh, c.pwm.Top()/2)
+	group.Set(c.pwmCh, group.Top()/2)
+	// 配置完成后才能运动
+	c.pwm = group
 
 	return nil
 }
@@ -154,68 +142,465 @@ func (c *GolfClubs) SetPulsesPerCircle(pulsesPerCircle uint32) {
 	c.pulsesPerCircle = pulsesPerCircle
 }
 
+// SetStallDetector 设置堵转检测，为 nil 时不检测
+func (c *GolfClubs) SetStallDetector(stall StallDetector) {
+	c.stall = stall
+}
+
+// SetFeedback 设置闭环位置检测，为 nil 时开环运行
+// autoRehome 为 true 时，检测到位置偏差后自动归位
+func (c *GolfClubs) SetFeedback(feedback *Feedback, autoRehome bool) error {
+	c.feedback = feedback
+	c.autoRehome = autoRehome
+	if feedback == nil {
+		return nil
+	}
+	return feedback.Reset(c.positionUnits(c.position))
+}
+
+// AddObserver 添加运动观察者
+func (c *GolfClubs) AddObserver(observer MotionObserver) {
+	c.observers = append(c.observers, observer)
+}
+
+// SetSupply 设置电机电源检测，每次运动前检查，电压不足时降低速度或拒绝运动，为 nil 时不检查
+func (c *GolfClubs) SetSupply(supply Supply) {
+	c.supply = supply
+}
+
+// SetPowerConfig 设置电机电源管理配置
+func (c *GolfClubs) SetPowerConfig(cfg PowerConfig) {
+	c.power.SetConfig(cfg)
+}
+
+// PowerConfig 返回电机电源管理配置
+func (c *GolfClubs) PowerConfig() PowerConfig {
+	return c.power.Config()
+}
+
+// Cooldown 返回电机剩余的冷却时长，没有冷却时返回 0
+func (c *GolfClubs) Cooldown() time.Duration {
+	return c.power.Cooldown()
+}
+
+// Moving 返回是否正在运动（包括归位），可在其它协程中调用
+func (c *GolfClubs) Moving() bool {
+	return c.power.Busy()
+}
+
+// SetWatchdog 设置看门狗，运动期间定期喂狗，为 nil 时不喂狗
+func (c *GolfClubs) SetWatchdog(watchdog Watchdog) {
+	c.watchdog = watchdog
+}
+
+// State 返回球杆状态和原因
+func (c *GolfClubs) State() (State, error) {
+	return c.state.State()
+}
+
+// Fault 返回锁定的故障或急停，没有时返回 nil
+func (c *GolfClubs) Fault() error {
+	return c.state.Err()
+}
+
+// Trip 锁定故障 cause ，立即停止转动并脱机，直到 ClearFault 或 Home
+func (c *GolfClubs) Trip(cause error) {
+	c.state.Trip(cause)
+}
+
+// EStop 急停，立即停止转动并脱机，锁定故障直到 ClearFault 或 Home
+//
+// 可在其它协程（如外部触发输入的采样协程）中调用，正在进行的运动返回 ErrEmergencyStop 。
+func (c *GolfClubs) EStop() {
+	c.state.EStop()
+}
+
+// ClearFault 清除锁定的故障，并以当前实测位置作为指令位置
+func (c *GolfClubs) ClearFault() error {
+	if c.feedback != nil {
+		if err := c.feedback.Reset(c.positionUnits(c.position)); err != nil {
+			return err
+		}
+	}
+	c.state.Reset()
+	return nil
+}
+
+// CanHome 返回是否支持归位
+func (c *GolfClubs) CanHome() bool {
+	return c.stall != nil || c.feedback != nil
+}
+
+// CanRecord 返回是否支持录制轨迹
+func (c *GolfClubs) CanRecord() bool {
+	return c.feedback != nil
+}
+
+// positionUnits 将脉冲数转换为位置传感器单位
+func (c *GolfClubs) positionUnits(pulses int32) int32 {
+	return int32(int64(pulses) * int64(PositionUnitsPerCircle) / int64(c.pulsesPerCircle))
+}
+
 // setDirFront 向前挥杆
 func (c *GolfClubs) setDirFront() {
-	c.DirPin.Set(c.reverse)
+	c.forward = true
+	c.setDir(c.reverse != c.driver.InvertDir)
 }
 
 // setDirBack 向后挥杆
 func (c *GolfClubs) setDirBack() {
-	c.DirPin.Set(!c.reverse)
+	c.forward = false
+	c.setDir(c.reverse == c.driver.InvertDir)
+}
+
+// setDir 设置方向信号，信号变化时等待驱动器建立方向
+func (c *GolfClubs) setDir(dir bool) {
+	if dir == c.dir {
+		return
+	}
+	c.dir = dir
+	c.DirPin.Set(dir)
+	if c.driver.DirSetupDelay > 0 {
+		time.Sleep(c.driver.DirSetupDelay)
+	}
+}
+
+// enable 使能电机
+func (c *GolfClubs) enable() {
+	c.EnPin.Set(c.driver.EnableActiveHigh)
+	if c.driver.EnableSettleDelay > 0 {
+		time.Sleep(c.driver.EnableSettleDelay)
+	}
+}
+
+// disable 禁用电机（脱机）
+func (c *GolfClubs) disable() {
+	c.EnPin.Set(!c.driver.EnableActiveHigh)
+}
+
+// Swing 以默认挥杆参数和 speedPercent 速度挥杆一次
+func (c *GolfClubs) Swing(speedPercent uint8) error {
+	return c.SwingProfile(DefaultSwingProfile.WithSpeed(speedPercent))
+}
+
+// SwingProfile 按挥杆参数挥杆一次
+func (c *GolfClubs) SwingProfile(profile SwingProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+	return c.Run(profile.Plan(c.pulsesPerCircle))
+}
+
+// Putt 按推杆参数推杆一次
+func (c *GolfClubs) Putt(profile PuttProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+	return c.Run(profile.Plan(c.pulsesPerCircle))
+}
+
+// Jog 以最低速度向前转动 angle 度再转回，用于检查电机和驱动器
+func (c *GolfClubs) Jog(angle uint16) error {
+	pulses := anglePulses(uint32(angle), c.pulsesPerCircle)
+	rpm := speedRPM(minSpeedPercent)
+	return c.Run([]Segment{
+		{Forward: true, RPM: rpm, Pulses: pulses},
+		{Forward: false, RPM: rpm, Pulses: pulses},
+	})
+}
+
+// Run 依次执行运动段
+//
+// 运动被拒绝时返回包装 ErrRefused 的错误， LastMotion 为零值
+func (c *GolfClubs) Run(segments []Segment) (err error) {
+	c.lastMotion = Motion{}
+	if !c.configured() {
+		return refuse(ErrNotConfigured)
+	}
+	if err := c.Fault(); err != nil {
+		return refuse(fmt.Errorf("fault latched: %w", err))
+	}
+	// 每次运动前重新检查警告
+	c.state.Clear(StateWarning)
+	if c.supply != nil {
+		if err := c.supply.Check(); err != nil {
+			c.state.Warn(err)
+			return refuse(err)
+		}
+		if scale := c.supply.SpeedScale(); scale < 1 {
+			logger.Warnf("supply low, speed derated to %d%%", int(scale*100))
+			c.state.Warn(ErrSpeedDerated)
+			segments = ScaleSpeed(segments, scale)
+		}
+	}
+	if err := c.power.Acquire(true); err != nil {
+		c.state.Warn(err)
+		return refuse(err)
+	}
+	defer c.power.Release()
+	for _, o := range c.observers {
+		o.MotionStarted(segments, c.pulsesPerCircle)
+	}
+	defer func() {
+		for _, o := range c.observers {
+			o.MotionFinished(err)
+		}
+	}()
+	c.hold()
+
+	start := time.Now()
+	defer func() {
+		c.lastMotion.Duration = time.Since(start)
+	}()
+
+	for _, seg := range segments {
+		if seg.Pulses == 0 || seg.RPM <= 0 {
+			// 停顿
+			c.hold()
+			if err := c.sleep(seg.Pause); err != nil {
+				return c.abort(err)
+			}
+			continue
+		}
+		if seg.Forward != c.forward {
+			c.hold()
+		}
+		if seg.Forward {
+			c.setDirFront()
+		} else {
+			c.setDirBack()
+		}
+		from := c.position
+		err := c.moveAt(seg.RPM, seg.Pulses)
+		c.lastMotion.Steps += uint32(max(c.position-from, from-c.position))
+		if err != nil {
+			return c.abort(err)
+		}
+	}
+	c.hold()
+	return nil
+}
+
+// LastMotion 返回最近一次 Run 的运动统计
+func (c *GolfClubs) LastMotion() Motion {
+	return c.lastMotion
+}
+
+// PlayTrajectory 在 limits 限制下从当前位置回放轨迹
+func (c *GolfClubs) PlayTrajectory(t *Trajectory, limits TrajectoryLimits) error {
+	segments, err := t.Plan(c.pulsesPerCircle, limits)
+	if err != nil {
+		return err
+	}
+	return c.Run(segments)
 }
 
-// Swing 挥杆一次
-func (c *GolfClubs) Swing(speedPercent uint8) {
+// RecordTrajectory 在电机脱机时读取位置传感器，录制手动转动球杆的轨迹，最长录制 maxDuration
+func (c *GolfClubs) RecordTrajectory(maxDuration time.Duration) (*Trajectory, error) {
+	if c.feedback == nil {
+		return nil, errors.New("recording requires position feedback")
+	}
+	if !c.configured() {
+		return nil, ErrNotConfigured
+	}
 	c.hold()
-	c.EnPin.Low()
+	c.power.Off()
+	t, err := RecordTrajectory(
+		c.feedback.Sensor, c.feedback.Invert,
+		recordInterval, recordIdle, maxDuration, recordThreshold,
+		func(d time.Duration) {
+			time.Sleep(d)
+			c.feed()
+		},
+	)
+
+	// 球杆被手动转动过，以实测位置为指令位置
+	if syncErr := c.syncPosition(); syncErr != nil && err == nil {
+		err = syncErr
+	}
+	return t, err
+}
 
-	// 向后摆 38% 圈
+// syncPosition 以实测位置更新指令位置
+func (c *GolfClubs) syncPosition() error {
+	measured, err := c.feedback.Measured()
+	if err != nil {
+		return err
+	}
+	c.position = int32(int64(measured) * int64(c.pulsesPerCircle) / int64(PositionUnitsPerCircle))
+	return c.feedback.Reset(c.positionUnits(c.position))
+}
+
+// abort 因错误停止转动，位置偏差或堵转时锁定故障并按需自动归位
+func (c *GolfClubs) abort(err error) error {
+	c.hold()
+	if !errors.Is(err, ErrPositionMismatch) && !errors.Is(err, ErrStalled) {
+		return err
+	}
+	c.state.Trip(err)
+	if c.autoRehome && c.CanHome() {
+		time.Sleep(100 * time.Millisecond)
+		if homeErr := c.Home(); homeErr != nil {
+			logger.Errorf("auto rehome error: %v", homeErr)
+		} else {
+			logger.Infof("auto rehome done")
+		}
+	}
+	return err
+}
+
+// Home 向后转动直到碰到限位（通过堵转或位置偏差检测），然后稍微回退，并以该位置为原点
+func (c *GolfClubs) Home() error {
+	if !c.CanHome() {
+		return errors.New("homing requires stall detection or position feedback")
+	}
+	if !c.configured() {
+		return ErrNotConfigured
+	}
+	// 急停只能通过 ClearFault 确认后清除
+	if c.state.EStopped() {
+		return fmt.Errorf("e-stop latched: %w", ErrEmergencyStop)
+	}
+	c.hold()
+	// 归位不计入运动次数限制，冷却时也可归位
+	_ = c.power.Acquire(false)
+	defer c.power.Release()
+
+	if c.feedback != nil {
+		if err := c.feedback.Reset(c.positionUnits(c.position)); err != nil {
+			return err
+		}
+	}
+
+	c.homing = true
 	c.setDirBack()
-	c.swingRaw(minSpeedPercent, 38)
+	err := c.moveAt(speedRPM(homeSpeedPercent), c.pulsesPerCircle)
+	c.homing = false
 	c.hold()
-	time.Sleep(time.Second)
+	if err == nil {
+		return errors.New("no end stop found within one circle")
+	}
+	if !errors.Is(err, ErrStalled) {
+		return err
+	}
 
-	// 挥杆 76% 圈
+	// 离开限位
+	time.Sleep(100 * time.Millisecond)
+	c.position = 0
+	if c.feedback != nil {
+		if err := c.feedback.Reset(0); err != nil {
+			return err
+		}
+	}
 	c.setDirFront()
-	c.swingRaw(max(uint8(uint32(speedPercent)*1/10), minSpeedPercent), 2)
-	c.swingRaw(max(uint8(uint32(speedPercent)*2/10), minSpeedPercent), 2)
-	c.swingRaw(max(uint8(uint32(speedPercent)*3/10), minSpeedPercent), 2)
-	c.swingRaw(max(uint8(uint32(speedPercent)*4/10), minSpeedPercent), 2)
-	c.swingRaw(max(uint8(uint32(speedPercent)*5/10), minSpeedPercent), 2)
-	c.swingRaw(max(uint8(uint32(speedPercent)*6/10), minSpeedPercent), 3)
-	c.swingRaw(max(uint8(uint32(speedPercent)*7/10), minSpeedPercent), 3)
-	c.swingRaw(max(uint8(uint32(speedPercent)*8/10), minSpeedPercent), 4)
-	c.swingRaw(max(uint8(uint32(speedPercent)*9/10), minSpeedPercent), 5)
-	c.swingRaw(speedPercent, 25)
-	c.swingRaw(max(uint8(uint32(speedPercent)*9/10), minSpeedPercent), 5)
-	c.swingRaw(max(uint8(uint32(speedPercent)*8/10), minSpeedPercent), 4)
-	c.swingRaw(max(uint8(uint32(speedPercent)*7/10), minSpeedPercent), 3)
-	c.swingRaw(max(uint8(uint32(speedPercent)*6/10), minSpeedPercent), 3)
-	c.swingRaw(max(uint8(uint32(speedPercent)*5/10), minSpeedPercent), 2)
-	c.swingRaw(max(uint8(uint32(speedPercent)*4/10), minSpeedPercent), 2)
-	c.swingRaw(max(uint8(uint32(speedPercent)*3/10), minSpeedPercent), 2)
-	c.swingRaw(max(uint8(uint32(speedPercent)*2/10), minSpeedPercent), 2)
-	c.swingRaw(max(uint8(uint32(speedPercent)*1/10), minSpeedPercent), 2)
-	c.EnPin.High()
-}
-
-// swingRaw 挥杆
-func (c *GolfClubs) swingRaw(speedPercent uint8, ringPercent uint8) {
-	period := 1e9 * 60 / uint64(MaxSpeed*uint32(speedPercent)/100) / uint64(c.pulsesPerCircle)
-	d := time.Duration(uint64(c.pulsesPerCircle*uint32(ringPercent)/100) * period)
+	stall := c.stall
+	c.stall = nil
+	err = c.moveAt(speedRPM(homeSpeedPercent), c.pulsesPerCircle*uint32(homeBackOffPercent)/100)
+	c.stall = stall
+	c.hold()
+	if err != nil {
+		return err
+	}
+
+	// 以回退后的位置为原点
+	c.position = 0
+	if c.feedback != nil {
+		if err := c.feedback.Reset(0); err != nil {
+			return err
+		}
+	}
+	c.state.Reset()
+	return nil
+}
+
+// moveAt 以 rpm 转速转动 pulses 个脉冲
+func (c *GolfClubs) moveAt(rpm float32, pulses uint32) error {
+	period := uint64(1e9 * 60 / float64(rpm) / float64(c.pulsesPerCircle))
+	if minPeriod := c.driver.MinPeriod(); period < minPeriod {
+		// 脉冲过窄驱动器无法识别，限制速度
+		period = minPeriod
+	}
 
 	// 设置旋转速度
 	if err := c.pwm.SetPeriod(period); err != nil {
-		log.Printf("ERROR set pwm period to %d error: %v", period, err)
-		return
+		return fmt.Errorf("set pwm period to %d error: %w", period, err)
 	}
 
 	// 挥
+	stallAfter, checkStall := c.setSpeed(rpm)
 	c.pwm.Set(c.pwmCh, c.pwm.Top()/2)
-	time.Sleep(d)
+	return c.wait(pulses, period, stallAfter, checkStall)
+}
+
+// wait 等待电机以 period 周期转动 pulses 个脉冲，期间检测急停、堵转和位置偏差，并更新指令位置
+//
+// checkStall 为 false 时不检测堵转，否则转动 stallAfter 后开始检测
+func (c *GolfClubs) wait(pulses uint32, period uint64, stallAfter time.Duration, checkStall bool) error {
+	from := c.position
+	sign := int32(-1)
+	if c.forward {
+		sign = 1
+	}
+	d := time.Duration(uint64(pulses) * period)
+	// 按实际经过的时间推算位置，检测和喂狗的耗时不会使脉冲多于指令位置
+	start := time.Now()
+	for {
+		elapsed := time.Since(start)
+		if elapsed >= d {
+			break
+		}
+		time.Sleep(min(d-elapsed, monitorInterval))
+		elapsed = min(time.Since(start), d)
+		c.position = from + sign*int32(uint64(elapsed)/period)
+
+		c.feed()
+		if c.state.EStopped() {
+			return ErrEmergencyStop
+		}
+
+		if checkStall && c.stall != nil && elapsed >= stallAfter {
+			stalled, err := c.stall.Stalled()
+			if err != nil {
+				logger.Errorf("check stall error: %v", err)
+			} else if stalled {
+				return ErrStalled
+			}
+		}
+		if c.feedback != nil {
+			err := c.feedback.Check(c.positionUnits(c.position))
+			switch {
+			case err == nil:
+			case !errors.Is(err, ErrPositionMismatch):
+				logger.Errorf("check position error: %v", err)
+			case c.homing:
+				// 归位时位置偏差说明碰到了限位
+				return ErrStalled
+			default:
+				return err
+			}
+		}
+	}
+	c.position = from + sign*int32(pulses)
+	return nil
+}
+
+// sleep 等待 d ，期间急停时返回 ErrEmergencyStop
+func (c *GolfClubs) sleep(d time.Duration) error {
+	for d > 0 {
+		step := min(d, monitorInterval)
+		time.Sleep(step)
+		d -= step
+		c.feed()
+		if c.state.EStopped() {
+			return ErrEmergencyStop
+		}
+	}
+	return nil
 }
 
-// hold 停住球杆
-func (c *GolfClubs) hold() {
-	c.pwm.Set(c.pwmCh, 0)
+// feed 喂狗
+func (c *GolfClubs) feed() {
+	if c.watchdog != nil {
+		c.watchdog.Update()
+	}
 }
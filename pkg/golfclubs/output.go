@@ -0,0 +1,74 @@
+package golfclubs
+
+import (
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+)
+
+// stallSettleTime 转速变化后负载值稳定所需时间，期间不检测堵转
+const stallSettleTime = 50 * time.Millisecond
+
+// logger 球杆日志
+var logger = logging.New("clubs")
+
+// pulseOutput 输出步进脉冲所需的 PWM 组功能， PWMGroup 的子集
+type pulseOutput interface {
+	SetPeriod(period uint64) error
+	Top() uint32
+	Set(channel uint8, value uint32)
+}
+
+// motorOutput 电机脉冲输出和使能管理
+//
+// Configure 失败时 pwm 或 power 可能为 nil ，此时不能运动，但进入故障时仍尽量停住并脱机。
+type motorOutput struct {
+	// 步进脉冲输出， Configure 完成后才设置
+	pwm   pulseOutput
+	pwmCh uint8
+	// 当前转速（单位： rpm ），停住时为 0
+	rpm float32
+	// 电机使能管理
+	power *MotorPower
+}
+
+// configured 返回是否已配置完成，可以输出脉冲
+func (o *motorOutput) configured() bool {
+	return o.pwm != nil
+}
+
+// hold 停止输出脉冲，停住球杆
+func (o *motorOutput) hold() {
+	o.rpm = 0
+	if o.pwm == nil {
+		return
+	}
+	o.pwm.Set(o.pwmCh, 0)
+}
+
+// setSpeed 记录转速变为 rpm ，返回开始检测堵转前需等待的时间，不检测堵转时 ok 为 false
+//
+// 转速低于 StallMinRPM 时负载值不可靠；加减速后负载值需要一段时间才能稳定。
+func (o *motorOutput) setSpeed(rpm float32) (settle time.Duration, ok bool) {
+	prev := o.rpm
+	o.rpm = rpm
+	switch {
+	case rpm < StallMinRPM:
+		return 0, false
+	case rpm != prev:
+		return stallSettleTime, true
+	}
+	return 0, true
+}
+
+// stateChanged 进入故障或急停时停止转动并脱机
+func (o *motorOutput) stateChanged(state State, cause error) {
+	if state < StateFault {
+		return
+	}
+	o.hold()
+	if o.power != nil {
+		o.power.Off()
+	}
+	logger.Errorf("%s: %v", state, cause)
+}
@@ -0,0 +1,87 @@
+package golfclubs
+
+import (
+	"testing"
+	"time"
+)
+
+// fakePulseOutput 记录输出值的 pulseOutput
+type fakePulseOutput struct {
+	values map[uint8]uint32
+}
+
+func (p *fakePulseOutput) SetPeriod(uint64) error { return nil }
+func (p *fakePulseOutput) Top() uint32            { return 1000 }
+func (p *fakePulseOutput) Set(channel uint8, value uint32) {
+	if p.values == nil {
+		p.values = map[uint8]uint32{}
+	}
+	p.values[channel] = value
+}
+
+// TestMotorOutput_StateChanged 测试进入故障时停住并脱机，未配置完成时不会出错
+func TestMotorOutput_StateChanged(t *testing.T) {
+	// Configure 在设置 PWM 前失败：只有电机使能管理
+	var disables int
+	o := &motorOutput{power: &MotorPower{Enable: func() {}, Disable: func() { disables++ }}}
+	m := &StateMachine{OnChange: o.stateChanged}
+	if o.configured() {
+		t.Errorf("expected unconfigured")
+	}
+	m.Trip(ErrWatchdogReset)
+	if disables != 1 {
+		t.Errorf("expected disabled on fault, got %d disables", disables)
+	}
+	m.EStop()
+	if disables != 2 {
+		t.Errorf("expected disabled on e-stop, got %d disables", disables)
+	}
+
+	// 从未配置
+	o = &motorOutput{}
+	m = &StateMachine{OnChange: o.stateChanged}
+	m.Trip(ErrStalled)
+
+	// 已配置：停止输出脉冲，警告不影响输出
+	pwm := &fakePulseOutput{}
+	pwm.Set(2, 500)
+	o = &motorOutput{pwm: pwm, pwmCh: 2, power: &MotorPower{Enable: func() {}, Disable: func() {}}}
+	m = &StateMachine{OnChange: o.stateChanged}
+	m.Warn(ErrSpeedDerated)
+	if pwm.values[2] != 500 {
+		t.Errorf("expected output kept on warning, got %d", pwm.values[2])
+	}
+	m.Trip(ErrStalled)
+	if pwm.values[2] != 0 {
+		t.Errorf("expected output stopped on fault, got %d", pwm.values[2])
+	}
+}
+
+// TestMotorOutput_SetSpeed 测试低速和加减速时不检测堵转
+func TestMotorOutput_SetSpeed(t *testing.T) {
+	o := &motorOutput{}
+	cases := []struct {
+		rpm    float32
+		settle time.Duration
+		ok     bool
+	}{
+		{rpm: StallMinRPM / 2, ok: false},
+		{rpm: StallMinRPM, settle: stallSettleTime, ok: true},
+		{rpm: 200, settle: stallSettleTime, ok: true},
+		// 匀速
+		{rpm: 200, settle: 0, ok: true},
+		{rpm: 100, settle: stallSettleTime, ok: true},
+	}
+	for _, c := range cases {
+		settle, ok := o.setSpeed(c.rpm)
+		if settle != c.settle || ok != c.ok {
+			t.Errorf("setSpeed(%v): expected %s %t, got %s %t", c.rpm, c.settle, c.ok, settle, ok)
+		}
+	}
+
+	// 停住后重新起步
+	o.hold()
+	if settle, ok := o.setSpeed(100); settle != stallSettleTime || !ok {
+		t.Errorf("expected settle after hold, got %s %t", settle, ok)
+	}
+}
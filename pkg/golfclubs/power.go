@@ -0,0 +1,233 @@
+package golfclubs
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultDutyWindow 默认统计运动次数的时间窗口
+const DefaultDutyWindow = time.Minute
+
+// ErrCooldown 运动次数超过限制，电机冷却中
+var ErrCooldown = errors.New("motor cooling down")
+
+// PowerConfig 电机电源管理配置
+type PowerConfig struct {
+	// 运动结束后保持转矩的时长，之后脱机，为 0 时运动结束立即脱机
+	HoldTime time.Duration
+	// Window 内最多运动次数，为 0 时不限制
+	MaxMotions uint16
+	// 统计运动次数的时间窗口
+	// 默认为 DefaultDutyWindow
+	Window time.Duration
+	// 达到运动次数限制后的冷却时长，冷却期间拒绝运动，至少冷却到窗口内最早的一次运动移出窗口
+	Cooldown time.Duration
+}
+
+// window 返回统计运动次数的时间窗口
+func (c *PowerConfig) window() time.Duration {
+	if c.Window <= 0 {
+		return DefaultDutyWindow
+	}
+	return c.Window
+}
+
+// DutyCycle 按滑动时间窗口限制运动次数，防止电机和驱动器长时间连续工作过热
+type DutyCycle struct {
+	// 配置，仅使用 MaxMotions 、 Window 和 Cooldown
+	Config PowerConfig
+
+	starts    []time.Time
+	coolUntil time.Time
+}
+
+// Allow 返回 now 时是否允许开始一次运动，冷却中时返回 ErrCooldown
+func (d *DutyCycle) Allow(now time.Time) error {
+	if d.Config.MaxMotions == 0 {
+		return nil
+	}
+	if d.Cooldown(now) == 0 {
+		d.prune(now)
+		if len(d.starts) < int(d.Config.MaxMotions) {
+			return nil
+		}
+		// 达到限制，开始冷却
+		d.coolUntil = now.Add(d.Config.Cooldown)
+		if oldest := d.starts[0].Add(d.Config.window()); oldest.After(d.coolUntil) {
+			d.coolUntil = oldest
+		}
+	}
+	return fmt.Errorf("%w: %s remaining", ErrCooldown, d.Cooldown(now).Round(time.Second))
+}
+
+// Record 记录 now 时开始的一次运动
+func (d *DutyCycle) Record(now time.Time) {
+	if d.Config.MaxMotions == 0 {
+		return
+	}
+	d.prune(now)
+	d.starts = append(d.starts, now)
+}
+
+// Cooldown 返回 now 时剩余的冷却时长，没有冷却时返回 0
+func (d *DutyCycle) Cooldown(now time.Time) time.Duration {
+	if !now.Before(d.coolUntil) {
+		return 0
+	}
+	return d.coolUntil.Sub(now)
+}
+
+// Count 返回 now 时窗口内的运动次数
+func (d *DutyCycle) Count(now time.Time) int {
+	d.prune(now)
+	return len(d.starts)
+}
+
+// prune 丢弃移出窗口的运动
+func (d *DutyCycle) prune(now time.Time) {
+	start := now.Add(-d.Config.window())
+	i := 0
+	for i < len(d.starts) && !d.starts[i].After(start) {
+		i++
+	}
+	d.starts = d.starts[i:]
+}
+
+// MotorPower 电机电源管理
+//
+// 运动前使能电机，运动结束后保持转矩 PowerConfig.HoldTime 再脱机，期间开始新的运动时继续保持使能；
+// 并按 PowerConfig 限制运动次数。
+type MotorPower struct {
+	// 使能电机
+	Enable func()
+	// 禁用电机（脱机）
+	Disable func()
+
+	lock    sync.Mutex
+	duty    DutyCycle
+	enabled bool
+	busy    bool
+	// 每次状态变化时递增，使过期的延迟脱机失效
+	gen uint32
+
+	// 测试时替换的时钟和定时器
+	now       func() time.Time
+	afterFunc func(d time.Duration, f func())
+}
+
+// clock 返回当前时间
+func (p *MotorPower) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
+
+// after 在 d 后在其它协程中调用 f
+func (p *MotorPower) after(d time.Duration, f func()) {
+	if p.afterFunc != nil {
+		p.afterFunc(d, f)
+		return
+	}
+	time.AfterFunc(d, f)
+}
+
+// SetConfig 设置配置
+func (p *MotorPower) SetConfig(cfg PowerConfig) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.duty.Config = cfg
+}
+
+// Config 返回配置
+func (p *MotorPower) Config() PowerConfig {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.duty.Config
+}
+
+// Acquire 开始运动前调用，使能电机
+// counted 为 true 时该运动计入运动次数限制，冷却中时返回 ErrCooldown 且不使能电机
+func (p *MotorPower) Acquire(counted bool) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if counted {
+		now := p.clock()
+		if err := p.duty.Allow(now); err != nil {
+			return err
+		}
+		p.duty.Record(now)
+	}
+	p.busy = true
+	p.gen++
+	if !p.enabled {
+		p.Enable()
+		p.enabled = true
+	}
+	return nil
+}
+
+// Release 运动结束后调用，保持转矩 PowerConfig.HoldTime 后脱机
+func (p *MotorPower) Release() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.busy = false
+	p.gen++
+	hold := p.duty.Config.HoldTime
+	if hold <= 0 {
+		p.off()
+		return
+	}
+	gen := p.gen
+	p.after(hold, func() {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		if p.gen == gen && !p.busy {
+			p.off()
+		}
+	})
+}
+
+// Off 立即脱机，如急停或手动转动球杆时
+func (p *MotorPower) Off() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.gen++
+	p.off()
+}
+
+// off 脱机，调用前需持有锁
+func (p *MotorPower) off() {
+	p.Disable()
+	p.enabled = false
+}
+
+// Enabled 返回电机是否使能
+func (p *MotorPower) Enabled() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.enabled
+}
+
+// Busy 返回是否正在运动（ Acquire 后尚未 Release ）
+func (p *MotorPower) Busy() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.busy
+}
+
+// Cooldown 返回剩余的冷却时长，没有冷却时返回 0
+func (p *MotorPower) Cooldown() time.Duration {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.duty.Cooldown(p.clock())
+}
+
+// Count 返回当前窗口内的运动次数
+func (p *MotorPower) Count() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.duty.Count(p.clock())
+}
@@ -0,0 +1,177 @@
+package golfclubs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDutyCycle 测试 DutyCycle 限制运动次数和冷却
+func TestDutyCycle(t *testing.T) {
+	d := &DutyCycle{Config: PowerConfig{MaxMotions: 3, Window: time.Minute, Cooldown: 90 * time.Second}}
+	start := time.Unix(0, 0)
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+
+	for _, s := range []int{0, 10, 20} {
+		if err := d.Allow(at(s)); err != nil {
+			t.Fatalf("unexpected error at %ds: %v", s, err)
+		}
+		d.Record(at(s))
+	}
+	if n := d.Count(at(30)); n != 3 {
+		t.Errorf("expected 3 motions, got %d", n)
+	}
+
+	// 第 4 次开始冷却 90s
+	if err := d.Allow(at(30)); !errors.Is(err, ErrCooldown) {
+		t.Fatalf("expected ErrCooldown, got %v", err)
+	}
+	if remaining := d.Cooldown(at(60)); remaining != time.Minute {
+		t.Errorf("expected 60s cooldown remaining, got %s", remaining)
+	}
+	// 冷却期间即使窗口内运动已移出也拒绝
+	if err := d.Allow(at(100)); !errors.Is(err, ErrCooldown) {
+		t.Errorf("expected ErrCooldown during cooldown, got %v", err)
+	}
+	if err := d.Allow(at(120)); err != nil {
+		t.Errorf("unexpected error after cooldown: %v", err)
+	}
+	if n := d.Count(at(120)); n != 0 {
+		t.Errorf("expected 0 motions after cooldown, got %d", n)
+	}
+}
+
+// TestDutyCycle_Window 测试冷却时长短于窗口时冷却到最早的运动移出窗口
+func TestDutyCycle_Window(t *testing.T) {
+	d := &DutyCycle{Config: PowerConfig{MaxMotions: 2, Cooldown: 5 * time.Second}}
+	start := time.Unix(0, 0)
+	d.Record(start)
+	d.Record(start.Add(40 * time.Second))
+	if err := d.Allow(start.Add(45 * time.Second)); !errors.Is(err, ErrCooldown) {
+		t.Fatalf("expected ErrCooldown, got %v", err)
+	}
+	if remaining := d.Cooldown(start.Add(45 * time.Second)); remaining != 15*time.Second {
+		t.Errorf("expected 15s cooldown remaining, got %s", remaining)
+	}
+	if err := d.Allow(start.Add(60 * time.Second)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// 不限制
+	d = &DutyCycle{}
+	for i := 0; i < 100; i++ {
+		if err := d.Allow(start); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		d.Record(start)
+	}
+}
+
+// fakeClock 可手动推进的时钟和定时器
+type fakeClock struct {
+	t      time.Time
+	timers []fakeTimer
+}
+
+// fakeTimer fakeClock 上等待到期的定时器
+type fakeTimer struct {
+	at time.Time
+	f  func()
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func (c *fakeClock) afterFunc(d time.Duration, f func()) {
+	c.timers = append(c.timers, fakeTimer{at: c.t.Add(d), f: f})
+}
+
+// advance 推进时钟 d ，并按注册顺序调用到期的定时器
+func (c *fakeClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+	var pending []fakeTimer
+	var due []func()
+	for _, timer := range c.timers {
+		if timer.at.After(c.t) {
+			pending = append(pending, timer)
+		} else {
+			due = append(due, timer.f)
+		}
+	}
+	c.timers = pending
+	for _, f := range due {
+		f()
+	}
+}
+
+// TestMotorPower 测试 MotorPower 保持转矩后脱机
+func TestMotorPower(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	var enables, disables int
+	p := &MotorPower{
+		Enable:    func() { enables++ },
+		Disable:   func() { disables++ },
+		now:       clock.now,
+		afterFunc: clock.afterFunc,
+	}
+	p.SetConfig(PowerConfig{HoldTime: 50 * time.Millisecond, MaxMotions: 2, Cooldown: time.Minute})
+
+	if err := p.Acquire(true); err != nil {
+		t.Fatalf("acquire error: %v", err)
+	}
+	if !p.Busy() {
+		t.Errorf("expected busy after acquire")
+	}
+	p.Release()
+	if p.Busy() {
+		t.Errorf("expected not busy after release")
+	}
+	// 保持期间开始新的运动，不重新使能，之前的延迟脱机失效
+	clock.advance(20 * time.Millisecond)
+	if err := p.Acquire(true); err != nil {
+		t.Fatalf("acquire error: %v", err)
+	}
+	clock.advance(50 * time.Millisecond)
+	if !p.Enabled() || !p.Busy() || enables != 1 || disables != 0 {
+		t.Fatalf("expected enabled once, got enabled %t enables %d disables %d", p.Enabled(), enables, disables)
+	}
+	p.Release()
+	clock.advance(49 * time.Millisecond)
+	if !p.Enabled() {
+		t.Fatalf("expected enabled during hold")
+	}
+	clock.advance(time.Millisecond)
+	if p.Enabled() || disables != 1 {
+		t.Fatalf("expected disabled after hold, got enabled %t disables %d", p.Enabled(), disables)
+	}
+	if n := p.Count(); n != 2 {
+		t.Errorf("expected 2 motions, got %d", n)
+	}
+
+	// 超过次数限制时不使能，归位不受限制
+	if err := p.Acquire(true); !errors.Is(err, ErrCooldown) {
+		t.Errorf("expected ErrCooldown, got %v", err)
+	}
+	if enables != 1 || p.Busy() {
+		t.Errorf("expected no enable during cooldown, got enables %d busy %t", enables, p.Busy())
+	}
+	if remaining := p.Cooldown(); remaining != time.Minute {
+		t.Errorf("expected 1m cooldown remaining, got %s", remaining)
+	}
+	if err := p.Acquire(false); err != nil {
+		t.Errorf("unexpected uncounted acquire error: %v", err)
+	}
+	p.Off()
+	if p.Enabled() || disables != 2 {
+		t.Errorf("expected disabled immediately, got enabled %t disables %d", p.Enabled(), disables)
+	}
+
+	// 冷却结束后可以再次运动
+	p.Release()
+	clock.advance(time.Minute)
+	if p.Cooldown() != 0 {
+		t.Errorf("expected cooldown to end")
+	}
+	if err := p.Acquire(true); err != nil {
+		t.Errorf("unexpected acquire error after cooldown: %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package golfclubs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// MaxSpeed 最大挥杆速度（单位： rpm ）
+	MaxSpeed uint32 = 400
+	// minSpeedPercent 最小速度百分比
+	minSpeedPercent uint8 = 10
+	// rampSteps 加速或减速阶段划分的匀速段数
+	rampSteps = 10
+	// backswingSpeedPercent 后摆速度百分比
+	backswingSpeedPercent uint8 = minSpeedPercent
+)
+
+// 挥杆参数范围
+const (
+	MinBackswingAngle     uint16 = 10
+	MaxBackswingAngle     uint16 = 270
+	MaxPause                     = 5 * time.Second
+	MinPeakSpeedPercent   uint8  = 1
+	MaxPeakSpeedPercent   uint8  = 100
+	MinAcceleration       uint32 = 500
+	MaxAcceleration       uint32 = 20000
+	MinFollowThroughAngle uint16 = 0
+	MaxFollowThroughAngle uint16 = 270
+)
+
+// Segment 运动段，电机以固定速度转动若干脉冲，或停顿一段时间
+type Segment struct {
+	// 转动方向，向前为 true
+	Forward bool
+	// 速度（单位： rpm ），为 0 时表示停顿
+	RPM float32
+	// 转动的脉冲数
+	Pulses uint32
+	// 停顿时长
+	Pause time.Duration
+}
+
+// ErrRefused 运动被拒绝，没有开始转动，如故障锁定、电源电压过低或电机冷却中
+//
+// Run 返回的错误同时包装 ErrRefused 和具体原因，可以用 errors.Is 分别判断。
+var ErrRefused = errors.New("motion refused")
+
+// refuse 返回包装 ErrRefused 和 err 的错误
+func refuse(err error) error {
+	return fmt.Errorf("%w: %w", ErrRefused, err)
+}
+
+// Motion 一次运动的统计
+type Motion struct {
+	// 运动时长，包括停顿
+	Duration time.Duration
+	// 电机转动的步数（脉冲数）
+	Steps uint32
+}
+
+// MotionObserver 运动观察者，如记录实际角速度的 IMU
+type MotionObserver interface {
+	// MotionStarted 开始执行运动段前调用
+	MotionStarted(segments []Segment, pulsesPerCircle uint32)
+	// MotionFinished 运动结束后调用， err 为运动返回的错误
+	MotionFinished(err error)
+}
+
+// Supply 电机电源，如电压检测
+type Supply interface {
+	// Check 电源无法支持运动（如电压过低）时返回错误
+	Check() error
+	// SpeedScale 返回运动速度的缩放比例，为 1 时不降速
+	SpeedScale() float32
+}
+
+// ScaleSpeed 返回各转动段速度乘以 scale 的运动段副本，停顿不变
+func ScaleSpeed(segments []Segment, scale float32) []Segment {
+	ret := make([]Segment, len(segments))
+	for i, seg := range segments {
+		seg.RPM *= scale
+		ret[i] = seg
+	}
+	return ret
+}
+
+// SwingProfile 挥杆参数
+type SwingProfile struct {
+	// 名字，通常为球杆名
+	Name string `json:"name"`
+	// 后摆角度（单位：度）
+	BackswingAngle uint16 `json:"backswingAngle"`
+	// 后摆后停顿时长
+	Pause time.Duration `json:"pause"`
+	// 峰值速度占 MaxSpeed 的百分比
+	PeakSpeedPercent uint8 `json:"peakSpeedPercent"`
+	// 挥杆加速度（单位： rpm/s ），减速度与之相同
+	Acceleration uint32 `json:"acceleration"`
+	// 挥杆越过起始位置后继续转动的角度（单位：度）
+	FollowThroughAngle uint16 `json:"followThroughAngle"`
+}
+
+// DefaultSwingProfile 默认挥杆参数，后摆 38% 圈，挥杆 75% 圈
+var DefaultSwingProfile = SwingProfile{
+	Name:               "Default",
+	BackswingAngle:     137,
+	Pause:              time.Second,
+	PeakSpeedPercent:   100,
+	Acceleration:       5000,
+	FollowThroughAngle: 133,
+}
+
+// DefaultClubProfiles 各球杆默认挥杆参数，推杆使用 PuttProfile
+func DefaultClubProfiles() []SwingProfile {
+	clubs := []struct {
+		name  string
+		speed uint8
+	}{
+		{name: "Driver", speed: 100},
+		{name: "Spoon", speed: 90},
+		{name: "3-Iron", speed: 80},
+		{name: "5-Iron", speed: 70},
+		{name: "7-Iron", speed: 60},
+		{name: "9-Iron", speed: 50},
+		{name: "Wedge", speed: 40},
+	}
+	ret := make([]SwingProfile, len(clubs))
+	for i, club := range clubs {
+		ret[i] = DefaultSwingProfile
+		ret[i].Name = club.name
+		ret[i].PeakSpeedPercent = club.speed
+	}
+	return ret
+}
+
+// WithSpeed 返回峰值速度为 speedPercent 的参数副本
+func (p SwingProfile) WithSpeed(speedPercent uint8) SwingProfile {
+	p.PeakSpeedPercent = speedPercent
+	return p
+}
+
+// Validate 检查参数是否在允许范围内
+func (p *SwingProfile) Validate() error {
+	switch {
+	case p.BackswingAngle < MinBackswingAngle || p.BackswingAngle > MaxBackswingAngle:
+		return fmt.Errorf("backswing angle %d out of range [%d, %d]", p.BackswingAngle, MinBackswingAngle, MaxBackswingAngle)
+	case p.Pause < 0 || p.Pause > MaxPause:
+		return fmt.Errorf("pause %s out of range [0, %s]", p.Pause, MaxPause)
+	case p.PeakSpeedPercent < MinPeakSpeedPercent || p.PeakSpeedPercent > MaxPeakSpeedPercent:
+		return fmt.Errorf("peak speed %d%% out of range [%d, %d]", p.PeakSpeedPercent, MinPeakSpeedPercent, MaxPeakSpeedPercent)
+	case p.Acceleration < MinAcceleration || p.Acceleration > MaxAcceleration:
+		return fmt.Errorf("acceleration %d out of range [%d, %d]", p.Acceleration, MinAcceleration, MaxAcceleration)
+	case p.FollowThroughAngle > MaxFollowThroughAngle:
+		return fmt.Errorf("follow through angle %d out of range [%d, %d]", p.FollowThroughAngle, MinFollowThroughAngle, MaxFollowThroughAngle)
+	}
+	return nil
+}
+
+// Plan 将挥杆参数转换为运动段
+//
+// 先以低速后摆，停顿后向前按加速度加速到峰值速度，再以相同减速度减速，停在越过起始位置 FollowThroughAngle 处。
+// 若挥杆行程不足以加速到峰值速度，则降低峰值速度。
+func (p *SwingProfile) Plan(pulsesPerCircle uint32) []Segment {
+	backPulses := anglePulses(uint32(p.BackswingAngle), pulsesPerCircle)
+	segments := []Segment{
+		{Forward: false, RPM: speedRPM(backswingSpeedPercent), Pulses: backPulses},
+		{Pause: p.Pause},
+	}
+	forward := Segment{Forward: true}
+	return append(segments, planTrapezoid(
+		forward,
+		backPulses+anglePulses(uint32(p.FollowThroughAngle), pulsesPerCircle),
+		speedRPM(p.PeakSpeedPercent),
+		float32(p.Acceleration),
+		speedRPM(minSpeedPercent),
+		pulsesPerCircle,
+	)...)
+}
+
+// planTrapezoid 规划以加速度 accel （单位： rpm/s ）从 minRPM 加速到 peakRPM 再对称减速、共转动 pulses 个脉冲的运动段
+// 加速和减速阶段分别划分为 rampSteps 个匀速段
+func planTrapezoid(base Segment, pulses uint32, peakRPM, accel, minRPM float32, pulsesPerCircle uint32) []Segment {
+	if pulses == 0 {
+		return nil
+	}
+	peakRPM = max(peakRPM, minRPM)
+
+	// 匀加速从 0 到 v 转过的圈数为 v²/(2a) ，速度单位为 rps
+	rampCircles := func(rpm float32) float32 {
+		rps := rpm / 60
+		return rps * rps / (2 * accel / 60)
+	}
+	total := float32(pulses) / float32(pulsesPerCircle)
+	if 2*rampCircles(peakRPM) > total {
+		// 行程不足，降低峰值速度使加减速恰好完成
+		peakRPM = max(float32(math.Sqrt(float64(total*accel/60)))*60, minRPM)
+	}
+
+	var ramp []Segment
+	var rampPulses uint32
+	prevCircles := float32(0)
+	for i := 1; i <= rampSteps; i++ {
+		rpm := peakRPM * float32(i) / rampSteps
+		circles := rampCircles(rpm)
+		n := uint32((circles - prevCircles) * float32(pulsesPerCircle))
+		prevCircles = circles
+		if 2*(rampPulses+n) > pulses {
+			n = pulses/2 - rampPulses
+		}
+		if n == 0 {
+			continue
+		}
+		seg := base
+		// 以段内平均速度运行
+		seg.RPM = max(peakRPM*(float32(i)-0.5)/rampSteps, minRPM)
+		seg.Pulses = n
+		ramp = append(ramp, seg)
+		rampPulses += n
+	}
+
+	segments := append([]Segment(nil), ramp...)
+	if cruise := pulses - 2*rampPulses; cruise > 0 {
+		seg := base
+		seg.RPM = peakRPM
+		seg.Pulses = cruise
+		segments = append(segments, seg)
+	}
+	for i := len(ramp) - 1; i >= 0; i-- {
+		segments = append(segments, ramp[i])
+	}
+	return segments
+}
+
+// anglePulses 返回转动 angle 度所需脉冲数
+func anglePulses(angle uint32, pulsesPerCircle uint32) uint32 {
+	return uint32(uint64(angle) * uint64(pulsesPerCircle) / 360)
+}
+
+// speedRPM 返回速度百分比对应的转速
+func speedRPM(speedPercent uint8) float32 {
+	return float32(MaxSpeed) * float32(speedPercent) / 100
+}
@@ -0,0 +1,128 @@
+package golfclubs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestSwingProfile_Validate 测试 SwingProfile.Validate
+func TestSwingProfile_Validate(t *testing.T) {
+	for _, p := range DefaultClubProfiles() {
+		if err := p.Validate(); err != nil {
+			t.Errorf("default profile %q invalid: %v", p.Name, err)
+		}
+	}
+
+	cases := map[string]func(p *SwingProfile){
+		"backswing":      func(p *SwingProfile) { p.BackswingAngle = 5 },
+		"pause":          func(p *SwingProfile) { p.Pause = 10 * time.Second },
+		"speed":          func(p *SwingProfile) { p.PeakSpeedPercent = 0 },
+		"acceleration":   func(p *SwingProfile) { p.Acceleration = 100000 },
+		"follow-through": func(p *SwingProfile) { p.FollowThroughAngle = 300 },
+	}
+	for name, modify := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := DefaultSwingProfile
+			modify(&p)
+			if err := p.Validate(); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+// TestSwingProfile_Plan 测试 SwingProfile.Plan
+func TestSwingProfile_Plan(t *testing.T) {
+	const ppc = 1600
+	cases := []struct {
+		name    string
+		profile SwingProfile
+		// 是否能加速到峰值速度
+		reachPeak bool
+	}{
+		{name: "default", profile: DefaultSwingProfile, reachPeak: true},
+		{name: "slow", profile: DefaultSwingProfile.WithSpeed(30), reachPeak: true},
+		{name: "low-acceleration", profile: SwingProfile{
+			BackswingAngle: 137, Pause: time.Second, PeakSpeedPercent: 100, Acceleration: 1000, FollowThroughAngle: 133,
+		}, reachPeak: false},
+		{name: "no-follow-through", profile: SwingProfile{
+			BackswingAngle: 90, PeakSpeedPercent: 50, Acceleration: 20000,
+		}, reachPeak: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			segments := c.profile.Plan(ppc)
+			if len(segments) < 3 {
+				t.Fatalf("too few segments: %+v", segments)
+			}
+
+			back, pause := segments[0], segments[1]
+			if back.Forward || back.Pulses != anglePulses(uint32(c.profile.BackswingAngle), ppc) {
+				t.Errorf("unexpected backswing segment: %+v", back)
+			}
+			if pause.Pulses != 0 || pause.Pause != c.profile.Pause {
+				t.Errorf("unexpected pause segment: %+v", pause)
+			}
+
+			var pulses uint32
+			var peak float32
+			forward := segments[2:]
+			for i, seg := range forward {
+				if !seg.Forward || seg.Pulses == 0 {
+					t.Errorf("unexpected forward segment %d: %+v", i, seg)
+				}
+				// 速度先增后减且对称
+				if mirror := forward[len(forward)-1-i]; mirror.RPM != seg.RPM {
+					t.Errorf("segment %d rpm %f not symmetric with %f", i, seg.RPM, mirror.RPM)
+				}
+				if seg.RPM < speedRPM(minSpeedPercent) {
+					t.Errorf("segment %d rpm %f below minimum", i, seg.RPM)
+				}
+				pulses += seg.Pulses
+				peak = max(peak, seg.RPM)
+			}
+			expected := anglePulses(uint32(c.profile.BackswingAngle), ppc) +
+				anglePulses(uint32(c.profile.FollowThroughAngle), ppc)
+			if pulses != expected {
+				t.Errorf("expected %d forward pulses, got %d", expected, pulses)
+			}
+			target := speedRPM(c.profile.PeakSpeedPercent)
+			if peak > target {
+				t.Errorf("peak rpm %f exceeds target %f", peak, target)
+			}
+			if reached := peak == target; reached != c.reachPeak {
+				t.Errorf("expected reach peak %t, got peak %f target %f", c.reachPeak, peak, target)
+			}
+		})
+	}
+}
+
+// TestScaleSpeed 测试 ScaleSpeed
+func TestScaleSpeed(t *testing.T) {
+	segments := DefaultSwingProfile.Plan(1600)
+	scaled := ScaleSpeed(segments, 0.5)
+	if len(scaled) != len(segments) {
+		t.Fatalf("expected %d segments, got %d", len(segments), len(scaled))
+	}
+	for i, seg := range scaled {
+		orig := segments[i]
+		if seg.RPM != orig.RPM*0.5 || seg.Pulses != orig.Pulses || seg.Pause != orig.Pause || seg.Forward != orig.Forward {
+			t.Errorf("unexpected segment %d: %+v from %+v", i, seg, orig)
+		}
+	}
+	if segments[0].RPM == scaled[0].RPM {
+		t.Errorf("original segments modified")
+	}
+}
+
+// TestRefuse 测试 refuse 返回的错误同时包装 ErrRefused 和原因
+func TestRefuse(t *testing.T) {
+	err := refuse(ErrCooldown)
+	if !errors.Is(err, ErrRefused) || !errors.Is(err, ErrCooldown) {
+		t.Errorf("expected ErrRefused and ErrCooldown, got %v", err)
+	}
+	if errors.Is(ErrStalled, ErrRefused) {
+		t.Errorf("unexpected ErrRefused")
+	}
+}
@@ -0,0 +1,99 @@
+package golfclubs
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// puttSteps 推杆每个摆动阶段划分的匀速段数
+	puttSteps = 16
+)
+
+// 推杆参数范围
+const (
+	MinPuttBackswingAngle uint16 = 2
+	MaxPuttBackswingAngle uint16 = 90
+	MinPuttTempo                 = 200 * time.Millisecond
+	MaxPuttTempo                 = 2 * time.Second
+)
+
+// PuttProfile 推杆参数
+//
+// 推杆像钟摆一样运动：以 Tempo 时长平滑后摆 BackswingAngle 度，停顿 Pause 后，再以相同时长向前摆到起始位置另一侧对称的位置。
+// 节奏固定时，后摆越大击球越快，因此后摆角度是控制距离的主要参数。
+type PuttProfile struct {
+	// 后摆角度（单位：度）
+	BackswingAngle uint16 `json:"backswingAngle"`
+	// 后摆和向前摆动各自的时长
+	Tempo time.Duration `json:"tempo"`
+	// 后摆后停顿时长
+	Pause time.Duration `json:"pause"`
+}
+
+// DefaultPuttProfile 默认推杆参数
+var DefaultPuttProfile = PuttProfile{
+	BackswingAngle: 20,
+	Tempo:          600 * time.Millisecond,
+	Pause:          200 * time.Millisecond,
+}
+
+// WithBackswing 返回后摆角度为 angle 的参数副本
+func (p PuttProfile) WithBackswing(angle uint16) PuttProfile {
+	p.BackswingAngle = angle
+	return p
+}
+
+// Validate 检查参数是否在允许范围内
+func (p *PuttProfile) Validate() error {
+	switch {
+	case p.BackswingAngle < MinPuttBackswingAngle || p.BackswingAngle > MaxPuttBackswingAngle:
+		return fmt.Errorf("putt backswing angle %d out of range [%d, %d]", p.BackswingAngle, MinPuttBackswingAngle, MaxPuttBackswingAngle)
+	case p.Tempo < MinPuttTempo || p.Tempo > MaxPuttTempo:
+		return fmt.Errorf("putt tempo %s out of range [%s, %s]", p.Tempo, MinPuttTempo, MaxPuttTempo)
+	case p.Pause < 0 || p.Pause > MaxPause:
+		return fmt.Errorf("pause %s out of range [0, %s]", p.Pause, MaxPause)
+	}
+	return nil
+}
+
+// Plan 将推杆参数转换为运动段
+//
+// 每个摆动阶段的位置按余弦曲线变化，起止速度为 0 ，中间速度最大。速度不受 minSpeedPercent 限制。
+func (p *PuttProfile) Plan(pulsesPerCircle uint32) []Segment {
+	back := anglePulses(uint32(p.BackswingAngle), pulsesPerCircle)
+	segments := planCosine(Segment{Forward: false}, back, p.Tempo, pulsesPerCircle)
+	segments = append(segments, Segment{Pause: p.Pause})
+	return append(segments, planCosine(Segment{Forward: true}, 2*back, p.Tempo, pulsesPerCircle)...)
+}
+
+// planCosine 规划在 duration 内按余弦曲线从静止到静止转动 pulses 个脉冲的运动段
+func planCosine(base Segment, pulses uint32, duration time.Duration, pulsesPerCircle uint32) []Segment {
+	var segments []Segment
+	step := duration / puttSteps
+	var prev uint32
+	var carry, last time.Duration
+	for i := 1; i <= puttSteps; i++ {
+		pos := uint32(math.Round(float64(pulses) * (1 - math.Cos(math.Pi*float64(i)/puttSteps)) / 2))
+		n := pos - prev
+		prev = pos
+		carry += step
+		if n == 0 {
+			// 脉冲太少，时间并入下一段
+			continue
+		}
+		seg := base
+		seg.Pulses = n
+		seg.RPM = float32(float64(n) / float64(pulsesPerCircle) / carry.Minutes())
+		segments = append(segments, seg)
+		last = carry
+		carry = 0
+	}
+	if carry > 0 && len(segments) > 0 {
+		// 结尾剩余的时间并入最后一段
+		seg := &segments[len(segments)-1]
+		seg.RPM = float32(float64(seg.Pulses) / float64(pulsesPerCircle) / (last + carry).Minutes())
+	}
+	return segments
+}
@@ -0,0 +1,67 @@
+package golfclubs
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPuttProfile_Plan 测试 PuttProfile.Plan
+func TestPuttProfile_Plan(t *testing.T) {
+	const ppc = 1600
+	for _, angle := range []uint16{MinPuttBackswingAngle, 20, MaxPuttBackswingAngle} {
+		p := DefaultPuttProfile.WithBackswing(angle)
+		if err := p.Validate(); err != nil {
+			t.Fatalf("angle %d: validate error: %v", angle, err)
+		}
+		segments := p.Plan(ppc)
+
+		var back, forward uint32
+		var backTime, forwardTime time.Duration
+		var peak float32
+		pauses := 0
+		for _, seg := range segments {
+			if seg.Pulses == 0 {
+				pauses++
+				continue
+			}
+			d := time.Duration(float64(seg.Pulses) / float64(ppc) / float64(seg.RPM) * float64(time.Minute))
+			if seg.Forward {
+				forward += seg.Pulses
+				forwardTime += d
+			} else {
+				back += seg.Pulses
+				backTime += d
+			}
+			peak = max(peak, seg.RPM)
+		}
+		if pauses != 1 {
+			t.Errorf("angle %d: expected 1 pause, got %d", angle, pauses)
+		}
+		if expected := anglePulses(uint32(angle), ppc); back != expected || forward != 2*expected {
+			t.Errorf("angle %d: expected back %d forward %d, got %d %d", angle, expected, 2*expected, back, forward)
+		}
+		for name, d := range map[string]time.Duration{"back": backTime, "forward": forwardTime} {
+			if diff := d - p.Tempo; diff > time.Millisecond || diff < -time.Millisecond {
+				t.Errorf("angle %d: expected %s time %s, got %s", angle, name, p.Tempo, d)
+			}
+		}
+		if angle == MinPuttBackswingAngle && peak >= speedRPM(minSpeedPercent) {
+			t.Errorf("expected small putt slower than minimum swing speed, got %f rpm", peak)
+		}
+	}
+
+	// 对称：向前摆动的速度曲线首尾对称
+	segments := DefaultPuttProfile.Plan(ppc)
+	var forward []Segment
+	for _, seg := range segments {
+		if seg.Forward {
+			forward = append(forward, seg)
+		}
+	}
+	for i := range forward {
+		mirror := forward[len(forward)-1-i]
+		if mirror.Pulses != forward[i].Pulses {
+			t.Errorf("forward segment %d not symmetric: %+v vs %+v", i, forward[i], mirror)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package golfclubs
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrWatchdogReset 上次运行时程序卡死，被看门狗复位
+	ErrWatchdogReset = errors.New("watchdog reset")
+	// ErrSpeedDerated 电源电压偏低，运动已降速
+	ErrSpeedDerated = errors.New("supply low, speed derated")
+	// ErrNotConfigured 电机未能完成配置，不能运动
+	ErrNotConfigured = errors.New("motor not configured")
+)
+
+// Watchdog 看门狗，如 machine.Watchdog
+type Watchdog interface {
+	// Update 喂狗
+	Update()
+}
+
+// State 球杆状态
+type State uint8
+
+const (
+	// StateOK 正常
+	StateOK State = iota
+	// StateWarning 有警告（如电源电压偏低、电机冷却中），仍可运动
+	StateWarning
+	// StateFault 锁定的故障（如堵转、位置偏差、看门狗复位），清除前禁止运动
+	StateFault
+	// StateEStop 锁定的急停，清除前禁止运动
+	StateEStop
+)
+
+// String 返回状态名
+func (s State) String() string {
+	switch s {
+	case StateOK:
+		return "ok"
+	case StateWarning:
+		return "warning"
+	case StateFault:
+		return "fault"
+	case StateEStop:
+		return "e-stop"
+	}
+	return "unknown"
+}
+
+// StateMachine 故障状态机
+//
+// 状态只会按 StateOK 、 StateWarning 、 StateFault 、 StateEStop 的顺序升级，
+// 降级需调用 Clear 或 Reset 。可在多个协程中使用。
+type StateMachine struct {
+	// 状态变化后调用，如进入故障时脱机
+	OnChange func(state State, cause error)
+
+	lock  sync.Mutex
+	state State
+	cause error
+}
+
+// State 返回当前状态和原因
+func (m *StateMachine) State() (State, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.state, m.cause
+}
+
+// Err 返回锁定的故障或急停原因，可以运动时返回 nil
+func (m *StateMachine) Err() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.state < StateFault {
+		return nil
+	}
+	return m.cause
+}
+
+// EStopped 返回是否已急停
+func (m *StateMachine) EStopped() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.state == StateEStop
+}
+
+// Warn 没有更严重的状态时进入 StateWarning
+func (m *StateMachine) Warn(cause error) {
+	m.raise(StateWarning, cause)
+}
+
+// Trip 没有急停时进入 StateFault
+func (m *StateMachine) Trip(cause error) {
+	m.raise(StateFault, cause)
+}
+
+// EStop 进入 StateEStop
+func (m *StateMachine) EStop() {
+	m.raise(StateEStop, ErrEmergencyStop)
+}
+
+// Clear 当前状态为 state 时恢复为 StateOK
+func (m *StateMachine) Clear(state State) {
+	m.lock.Lock()
+	if m.state != state {
+		m.lock.Unlock()
+		return
+	}
+	m.set(StateOK, nil)
+}
+
+// Reset 恢复为 StateOK
+func (m *StateMachine) Reset() {
+	m.lock.Lock()
+	if m.state == StateOK {
+		m.lock.Unlock()
+		return
+	}
+	m.set(StateOK, nil)
+}
+
+// raise 当前状态不比 state 严重时进入 state
+func (m *StateMachine) raise(state State, cause error) {
+	m.lock.Lock()
+	if m.state > state || (m.state == state && m.cause == cause) {
+		m.lock.Unlock()
+		return
+	}
+	m.set(state, cause)
+}
+
+// set 设置状态，调用前需持有锁，返回前释放锁并调用 OnChange
+func (m *StateMachine) set(state State, cause error) {
+	m.state = state
+	m.cause = cause
+	m.lock.Unlock()
+	if m.OnChange != nil {
+		m.OnChange(state, cause)
+	}
+}
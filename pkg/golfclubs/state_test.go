@@ -0,0 +1,62 @@
+package golfclubs
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestStateMachine 测试 StateMachine 状态升级、锁定和清除
+func TestStateMachine(t *testing.T) {
+	var changes []State
+	m := &StateMachine{OnChange: func(state State, _ error) { changes = append(changes, state) }}
+	check := func(expected State, expectedErr error) {
+		t.Helper()
+		state, _ := m.State()
+		if state != expected {
+			t.Errorf("expected state %s, got %s", expected, state)
+		}
+		if err := m.Err(); !errors.Is(err, expectedErr) || (expectedErr == nil && err != nil) {
+			t.Errorf("expected err %v, got %v", expectedErr, err)
+		}
+	}
+
+	m.Warn(ErrSpeedDerated)
+	check(StateWarning, nil)
+	m.Clear(StateWarning)
+	check(StateOK, nil)
+
+	m.Trip(ErrStalled)
+	check(StateFault, ErrStalled)
+	// 不会降级为警告
+	m.Warn(ErrCooldown)
+	check(StateFault, ErrStalled)
+	m.Clear(StateWarning)
+	check(StateFault, ErrStalled)
+
+	m.EStop()
+	check(StateEStop, ErrEmergencyStop)
+	if !m.EStopped() {
+		t.Errorf("expected e-stopped")
+	}
+	// 急停时故障不覆盖急停
+	m.Trip(ErrPositionMismatch)
+	check(StateEStop, ErrEmergencyStop)
+	m.Clear(StateEStop)
+	check(StateOK, nil)
+
+	m.Trip(ErrWatchdogReset)
+	m.Trip(ErrWatchdogReset)
+	m.Reset()
+	check(StateOK, nil)
+
+	expected := []State{StateWarning, StateOK, StateFault, StateEStop, StateOK, StateFault, StateOK}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected changes %v, got %v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("expected changes %v, got %v", expected, changes)
+			break
+		}
+	}
+}
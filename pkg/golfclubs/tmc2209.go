@@ -0,0 +1,495 @@
+package golfclubs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// TMC2209 寄存器地址
+const (
+	tmcRegGCONF      uint8 = 0x00
+	tmcRegGSTAT      uint8 = 0x01
+	tmcRegIOIN       uint8 = 0x06
+	tmcRegIHOLDIRUN  uint8 = 0x10
+	tmcRegTPOWERDOWN uint8 = 0x11
+	tmcRegTCOOLTHRS  uint8 = 0x14
+	tmcRegSGTHRS     uint8 = 0x40
+	tmcRegSGRESULT   uint8 = 0x41
+	tmcRegCHOPCONF   uint8 = 0x6C
+	tmcRegDRVSTATUS  uint8 = 0x6F
+)
+
+// GCONF 寄存器位
+const (
+	tmcGCONFEnSpreadCycle   uint32 = 1 << 2
+	tmcGCONFPDNDisable      uint32 = 1 << 6
+	tmcGCONFMstepRegSelect  uint32 = 1 << 7
+	tmcGCONFMultistepFilter uint32 = 1 << 8
+)
+
+// CHOPCONF 寄存器位
+const (
+	tmcCHOPCONFVsense     uint32 = 1 << 17
+	tmcCHOPCONFMresShift         = 24
+	tmcCHOPCONFMresMask   uint32 = 0xf << tmcCHOPCONFMresShift
+	tmcCHOPCONFIntpol     uint32 = 1 << 28
+	tmcCHOPCONFToffMask   uint32 = 0xf
+	tmcCHOPCONFToffEnable uint32 = 3
+)
+
+const (
+	// tmcSync 数据报同步字节
+	tmcSync byte = 0x05
+	// tmcMasterAddress 驱动器回复数据报中的主机地址
+	tmcMasterAddress byte = 0xff
+	// tmcVersion IOIN 寄存器中的芯片版本号
+	tmcVersion uint32 = 0x21
+	// tmcPowerDown 静止后降为保持电流的延时，单位为 2^18 个内部时钟周期（ 12 MHz 时约 21.8 毫秒），92 约为 2 秒
+	tmcPowerDown uint32 = 92
+	// tmcClock 内部时钟频率（单位： Hz ）
+	tmcClock uint32 = 12_000_000
+	// tmcTStepMax TSTEP 、 TCOOLTHRS 的最大值
+	tmcTStepMax uint32 = 0xfffff
+
+	// DefaultTMCSenseResistor 默认采样电阻（单位：毫欧）
+	DefaultTMCSenseResistor uint32 = 110
+	// DefaultTMCTimeout 默认等待回复超时时间
+	DefaultTMCTimeout = 10 * time.Millisecond
+)
+
+var (
+	// ErrTMCTimeout 等待驱动器回复超时
+	ErrTMCTimeout = errors.New("tmc2209 reply timeout")
+	// ErrTMCCRC 驱动器回复校验失败
+	ErrTMCCRC = errors.New("tmc2209 reply crc mismatch")
+)
+
+// TMCUART 与 TMC2209 通信的串口，通常为 *machine.UART
+type TMCUART interface {
+	// Write 发送数据
+	Write(p []byte) (int, error)
+	// ReadByte 读取一个字节，没有数据时返回错误
+	ReadByte() (byte, error)
+}
+
+// TMCConfig TMC2209 配置
+type TMCConfig struct {
+	// 运行电流有效值（单位：毫安）
+	RunCurrent uint32
+	// 保持电流占运行电流的百分比
+	HoldCurrentPercent uint8
+	// 细分数， 1 到 256 之间的 2 的幂
+	Microsteps uint32
+	// 使用 SpreadCycle 斩波模式，为 false 时使用更安静的 StealthChop
+	// NOTE: StallGuard 仅在 StealthChop 模式下可用
+	SpreadCycle bool
+	// StallGuard 堵转阈值， SG_RESULT 不大于该值 2 倍时视为堵转，为 0 时不检测堵转
+	StallThreshold uint8
+	// 检测堵转的最低步进脉冲频率（单位： Hz ），低于该速度时 StallGuard 不生效，为 0 时在所有速度下生效
+	StallMinStepRate uint32
+}
+
+// TMCStatus TMC2209 驱动器状态
+type TMCStatus struct {
+	// 过温预警
+	OverTemperatureWarning bool
+	// 过温关断
+	OverTemperature bool
+	// A 、 B 相对地短路
+	ShortToGround [2]bool
+	// A 、 B 相对电源短路
+	ShortToSupply [2]bool
+	// A 、 B 相开路
+	OpenLoad [2]bool
+	// 温度超过 120 、 143 、 150 、 157 摄氏度
+	TemperatureOver [4]bool
+	// 实际电流档位， 0 到 31
+	CurrentScale uint8
+	// 处于 StealthChop 模式
+	StealthChop bool
+	// 电机静止
+	Standstill bool
+}
+
+// Fault 返回是否存在需要停机的故障
+func (s TMCStatus) Fault() bool {
+	return s.OverTemperature ||
+		s.ShortToGround[0] || s.ShortToGround[1] ||
+		s.ShortToSupply[0] || s.ShortToSupply[1]
+}
+
+// String 返回状态描述
+func (s TMCStatus) String() string {
+	ret := fmt.Sprintf("cs=%d", s.CurrentScale)
+	if s.StealthChop {
+		ret += " stealth"
+	} else {
+		ret += " spread"
+	}
+	if s.Standstill {
+		ret += " stst"
+	}
+	flags := []struct {
+		name string
+		set  bool
+	}{
+		{name: "otpw", set: s.OverTemperatureWarning},
+		{name: "ot", set: s.OverTemperature},
+		{name: "s2ga", set: s.ShortToGround[0]},
+		{name: "s2gb", set: s.ShortToGround[1]},
+		{name: "s2vsa", set: s.ShortToSupply[0]},
+		{name: "s2vsb", set: s.ShortToSupply[1]},
+		{name: "ola", set: s.OpenLoad[0]},
+		{name: "olb", set: s.OpenLoad[1]},
+	}
+	for _, f := range flags {
+		if f.set {
+			ret += " " + f.name
+		}
+	}
+	return ret
+}
+
+// parseTMCStatus 解析 DRV_STATUS 寄存器
+func parseTMCStatus(v uint32) TMCStatus {
+	bit := func(i uint) bool { return v&(1<<i) != 0 }
+	return TMCStatus{
+		OverTemperatureWarning: bit(0),
+		OverTemperature:        bit(1),
+		ShortToGround:          [2]bool{bit(2), bit(3)},
+		ShortToSupply:          [2]bool{bit(4), bit(5)},
+		OpenLoad:               [2]bool{bit(6), bit(7)},
+		TemperatureOver:        [4]bool{bit(8), bit(9), bit(10), bit(11)},
+		CurrentScale:           uint8(v>>16) & 0x1f,
+		StealthChop:            bit(30),
+		Standstill:             bit(31),
+	}
+}
+
+// TMC2209 通过单线 UART 配置的 TMC2209 驱动器
+//
+// 脉冲、方向和使能信号仍由 GolfClubs 输出， TMC2209 仅负责电流、细分、斩波模式的配置和状态读取
+type TMC2209 struct {
+	// 串口
+	UART TMCUART
+	// 驱动器地址， 0 到 3 ，由 MS1 、 MS2 针脚决定
+	Address uint8
+	// 单线接法时发送的数据会被回读，需要丢弃
+	Echo bool
+	// 采样电阻（单位：毫欧）
+	// 默认为 DefaultTMCSenseResistor
+	SenseResistor uint32
+	// 等待回复超时时间
+	// 默认为 DefaultTMCTimeout
+	Timeout time.Duration
+
+	microsteps       uint32
+	stallThreshold   uint8
+	stallMinStepRate uint32
+}
+
+var _ StallDetector = (*TMC2209)(nil)
+
+// Configure 初始配置
+func (d *TMC2209) Configure(cfg TMCConfig) error {
+	// 检查通信
+	ioin, err := d.ReadRegister(tmcRegIOIN)
+	if err != nil {
+		return fmt.Errorf("read ioin error: %w", err)
+	}
+	if version := ioin >> 24; version != tmcVersion {
+		return fmt.Errorf("unexpected tmc2209 version: %#x", version)
+	}
+
+	// 清除复位等标志
+	if err := d.WriteRegister(tmcRegGSTAT, 0x7); err != nil {
+		return fmt.Errorf("clear gstat error: %w", err)
+	}
+
+	gconf := tmcGCONFPDNDisable | tmcGCONFMstepRegSelect | tmcGCONFMultistepFilter
+	if cfg.SpreadCycle {
+		gconf |= tmcGCONFEnSpreadCycle
+	}
+	if err := d.WriteRegister(tmcRegGCONF, gconf); err != nil {
+		return fmt.Errorf("write gconf error: %w", err)
+	}
+
+	if err := d.SetMicrosteps(cfg.Microsteps); err != nil {
+		return err
+	}
+	if err := d.SetCurrent(cfg.RunCurrent, cfg.HoldCurrentPercent); err != nil {
+		return err
+	}
+
+	// 静止 2 秒后降为保持电流
+	if err := d.WriteRegister(tmcRegTPOWERDOWN, tmcPowerDown); err != nil {
+		return fmt.Errorf("write tpowerdown error: %w", err)
+	}
+
+	d.stallMinStepRate = cfg.StallMinStepRate
+	return d.SetStallThreshold(cfg.StallThreshold)
+}
+
+// SetMicrosteps 设置细分数
+func (d *TMC2209) SetMicrosteps(microsteps uint32) error {
+	var mres uint32
+	switch microsteps {
+	case 256:
+		mres = 0
+	case 128:
+		mres = 1
+	case 64:
+		mres = 2
+	case 32:
+		mres = 3
+	case 16:
+		mres = 4
+	case 8:
+		mres = 5
+	case 4:
+		mres = 6
+	case 2:
+		mres = 7
+	case 1:
+		mres = 8
+	default:
+		return fmt.Errorf("invalid microsteps: %d", microsteps)
+	}
+
+	chop, err := d.ReadRegister(tmcRegCHOPCONF)
+	if err != nil {
+		return fmt.Errorf("read chopconf error: %w", err)
+	}
+	chop = chop&^tmcCHOPCONFMresMask | mres<<tmcCHOPCONFMresShift | tmcCHOPCONFIntpol
+	if chop&tmcCHOPCONFToffMask == 0 {
+		chop |= tmcCHOPCONFToffEnable
+	}
+	if err := d.WriteRegister(tmcRegCHOPCONF, chop); err != nil {
+		return fmt.Errorf("write chopconf error: %w", err)
+	}
+	d.microsteps = microsteps
+	if d.stallThreshold != 0 {
+		// TCOOLTHRS 与细分数有关
+		return d.SetStallThreshold(d.stallThreshold)
+	}
+	return nil
+}
+
+// SetCurrent 设置运行电流有效值（单位：毫安）和保持电流占运行电流的百分比
+func (d *TMC2209) SetCurrent(runCurrent uint32, holdPercent uint8) error {
+	rsense := d.SenseResistor
+	if rsense == 0 {
+		rsense = DefaultTMCSenseResistor
+	}
+
+	// I_rms = (CS+1)/32 * V_fs/(R_sense+20mΩ) / √2
+	vsense := false
+	cs := tmcCurrentScale(runCurrent, rsense, 325)
+	if cs < 16 {
+		// 电流较小时使用低满量程电压以提高分辨率
+		vsense = true
+		cs = tmcCurrentScale(runCurrent, rsense, 180)
+	}
+	hold := cs * uint32(min(holdPercent, 100)) / 100
+
+	chop, err := d.ReadRegister(tmcRegCHOPCONF)
+	if err != nil {
+		return fmt.Errorf("read chopconf error: %w", err)
+	}
+	if vsense {
+		chop |= tmcCHOPCONFVsense
+	} else {
+		chop &^= tmcCHOPCONFVsense
+	}
+	if err := d.WriteRegister(tmcRegCHOPCONF, chop); err != nil {
+		return fmt.Errorf("write chopconf error: %w", err)
+	}
+
+	// IHOLDDELAY 取 8 ，逐渐降为保持电流
+	if err := d.WriteRegister(tmcRegIHOLDIRUN, hold|cs<<8|8<<16); err != nil {
+		return fmt.Errorf("write ihold_irun error: %w", err)
+	}
+	return nil
+}
+
+// tmcTStep 返回以每秒 rate 个脉冲转动时的 TSTEP ，即两个 1/256 微步之间的内部时钟周期数
+// microsteps 为 0 时按上电默认的 256 细分计算， rate 为 0 时返回最大值
+func tmcTStep(rate, microsteps uint32) uint32 {
+	if microsteps == 0 {
+		microsteps = 256
+	}
+	if rate == 0 {
+		return tmcTStepMax
+	}
+	return uint32(min(uint64(tmcClock)*uint64(microsteps)/(256*uint64(rate)), uint64(tmcTStepMax)))
+}
+
+// tmcCurrentScale 计算电流档位 CS ，范围 0 到 31
+// current 单位为毫安， rsense 单位为毫欧， vfs 单位为毫伏
+func tmcCurrentScale(current, rsense, vfs uint32) uint32 {
+	cs := 32*math.Sqrt2*float64(current)*float64(rsense+20)/float64(vfs)/1000 - 1
+	switch {
+	case cs < 0:
+		return 0
+	case cs > 31:
+		return 31
+	}
+	return uint32(cs + 0.5)
+}
+
+// SetStallThreshold 设置 StallGuard 堵转阈值，为 0 时不检测堵转
+func (d *TMC2209) SetStallThreshold(threshold uint8) error {
+	if err := d.WriteRegister(tmcRegSGTHRS, uint32(threshold)); err != nil {
+		return fmt.Errorf("write sgthrs error: %w", err)
+	}
+	// 仅在不低于最低步进频率时启用 StallGuard
+	tcoolthrs := uint32(0)
+	if threshold != 0 {
+		tcoolthrs = tmcTStep(d.stallMinStepRate, d.microsteps)
+	}
+	if err := d.WriteRegister(tmcRegTCOOLTHRS, tcoolthrs); err != nil {
+		return fmt.Errorf("write tcoolthrs error: %w", err)
+	}
+	d.stallThreshold = threshold
+	return nil
+}
+
+// Status 读取驱动器状态
+func (d *TMC2209) Status() (TMCStatus, error) {
+	v, err := d.ReadRegister(tmcRegDRVSTATUS)
+	if err != nil {
+		return TMCStatus{}, fmt.Errorf("read drv_status error: %w", err)
+	}
+	return parseTMCStatus(v), nil
+}
+
+// StallGuard 读取 StallGuard 负载值， 0 到 510 ，值越小负载越大
+func (d *TMC2209) StallGuard() (uint16, error) {
+	v, err := d.ReadRegister(tmcRegSGRESULT)
+	if err != nil {
+		return 0, fmt.Errorf("read sg_result error: %w", err)
+	}
+	return uint16(v & 0x3ff), nil
+}
+
+// Stalled 返回 SG_RESULT 是否低于堵转阈值，即电机是否堵转
+//
+// 不检查过温、短路等驱动器故障，需要时通过 Status 读取。
+func (d *TMC2209) Stalled() (bool, error) {
+	if d.stallThreshold == 0 {
+		return false, nil
+	}
+	sg, err := d.StallGuard()
+	if err != nil {
+		return false, err
+	}
+	return uint32(sg) <= 2*uint32(d.stallThreshold), nil
+}
+
+// StatusLines 返回用于显示的状态行
+func (d *TMC2209) StatusLines() []string {
+	status, err := d.Status()
+	if err != nil {
+		return []string{"ERR " + err.Error()}
+	}
+	lines := []string{status.String()}
+	if sg, err := d.StallGuard(); err == nil {
+		lines = append(lines, fmt.Sprintf("sg=%d thrs=%d", sg, d.stallThreshold))
+	}
+	return lines
+}
+
+// WriteRegister 写寄存器
+func (d *TMC2209) WriteRegister(reg uint8, value uint32) error {
+	datagram := []byte{
+		tmcSync, d.Address, reg | 0x80,
+		byte(value >> 24), byte(value >> 16), byte(value >> 8), byte(value),
+		0,
+	}
+	datagram[7] = tmcCRC(datagram[:7])
+	if _, err := d.UART.Write(datagram); err != nil {
+		return err
+	}
+	if d.Echo {
+		if _, err := d.read(len(datagram)); err != nil {
+			return fmt.Errorf("read echo error: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadRegister 读寄存器
+func (d *TMC2209) ReadRegister(reg uint8) (uint32, error) {
+	d.flush()
+	request := []byte{tmcSync, d.Address, reg & 0x7f, 0}
+	request[3] = tmcCRC(request[:3])
+	if _, err := d.UART.Write(request); err != nil {
+		return 0, err
+	}
+	if d.Echo {
+		if _, err := d.read(len(request)); err != nil {
+			return 0, fmt.Errorf("read echo error: %w", err)
+		}
+	}
+
+	reply, err := d.read(8)
+	if err != nil {
+		return 0, err
+	}
+	if reply[0] != tmcSync || reply[1] != tmcMasterAddress || reply[2] != reg&0x7f {
+		return 0, fmt.Errorf("unexpected tmc2209 reply: % x", reply)
+	}
+	if tmcCRC(reply[:7]) != reply[7] {
+		return 0, ErrTMCCRC
+	}
+	return uint32(reply[3])<<24 | uint32(reply[4])<<16 | uint32(reply[5])<<8 | uint32(reply[6]), nil
+}
+
+// read 读取 n 个字节
+func (d *TMC2209) read(n int) ([]byte, error) {
+	timeout := d.Timeout
+	if timeout == 0 {
+		timeout = DefaultTMCTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	buf := make([]byte, 0, n)
+	for len(buf) < n {
+		c, err := d.UART.ReadByte()
+		if err != nil {
+			if time.Now().After(deadline) {
+				return nil, ErrTMCTimeout
+			}
+			time.Sleep(50 * time.Microsecond)
+			continue
+		}
+		buf = append(buf, c)
+	}
+	return buf, nil
+}
+
+// flush 丢弃串口中残留的数据
+func (d *TMC2209) flush() {
+	for {
+		if _, err := d.UART.ReadByte(); err != nil {
+			return
+		}
+	}
+}
+
+// tmcCRC 计算数据报的 CRC8 校验值（多项式 x^8+x^2+x+1 ，低位先行）
+func tmcCRC(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		for i := 0; i < 8; i++ {
+			if (crc>>7)^(b&0x01) != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+			b >>= 1
+		}
+	}
+	return crc
+}
@@ -0,0 +1,183 @@
+package golfclubs
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeTMC 模拟 TMC2209 单线 UART 及其寄存器
+type fakeTMC struct {
+	address uint8
+	regs    map[uint8]uint32
+	// 回读发送的数据
+	echo bool
+	// 篡改回复的校验值
+	badCRC bool
+
+	in  []byte
+	out []byte
+}
+
+var errEmpty = errors.New("empty")
+
+// Write 接收主机发送的数据报
+func (f *fakeTMC) Write(p []byte) (int, error) {
+	if f.echo {
+		f.out = append(f.out, p...)
+	}
+	f.in = append(f.in, p...)
+	for {
+		switch {
+		case len(f.in) >= 8 && f.in[2]&0x80 != 0:
+			if f.in[1] == f.address && tmcCRC(f.in[:7]) == f.in[7] {
+				f.regs[f.in[2]&0x7f] = uint32(f.in[3])<<24 | uint32(f.in[4])<<16 | uint32(f.in[5])<<8 | uint32(f.in[6])
+			}
+			f.in = f.in[8:]
+		case len(f.in) >= 4 && f.in[2]&0x80 == 0:
+			if f.in[1] == f.address && tmcCRC(f.in[:3]) == f.in[3] {
+				v := f.regs[f.in[2]]
+				reply := []byte{tmcSync, tmcMasterAddress, f.in[2], byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v), 0}
+				reply[7] = tmcCRC(reply[:7])
+				if f.badCRC {
+					reply[7]++
+				}
+				f.out = append(f.out, reply...)
+			}
+			f.in = f.in[4:]
+		default:
+			return len(p), nil
+		}
+	}
+}
+
+// ReadByte 读取驱动器发出的数据
+func (f *fakeTMC) ReadByte() (byte, error) {
+	if len(f.out) == 0 {
+		return 0, errEmpty
+	}
+	c := f.out[0]
+	f.out = f.out[1:]
+	return c, nil
+}
+
+// newFakeTMC 创建处于上电默认状态的 *fakeTMC
+func newFakeTMC() *fakeTMC {
+	return &fakeTMC{
+		address: 1,
+		echo:    true,
+		regs: map[uint8]uint32{
+			tmcRegIOIN:     tmcVersion << 24,
+			tmcRegCHOPCONF: 0x10000053,
+		},
+	}
+}
+
+// TestTMCCRC 测试 tmcCRC
+func TestTMCCRC(t *testing.T) {
+	// 数据手册中读 IOIN 请求
+	if got := tmcCRC([]byte{0x05, 0x00, 0x06}); got != 0x6f {
+		t.Errorf("expected 0x6f, got %#x", got)
+	}
+}
+
+// TestTMC2209_Configure 测试 TMC2209.Configure
+func TestTMC2209_Configure(t *testing.T) {
+	fake := newFakeTMC()
+	d := &TMC2209{UART: fake, Address: 1, Echo: true}
+	if err := d.Configure(TMCConfig{
+		RunCurrent:         800,
+		HoldCurrentPercent: 50,
+		Microsteps:         16,
+		StallThreshold:     40,
+		// 200 步电机 30rpm
+		StallMinStepRate: 1600,
+	}); err != nil {
+		t.Fatalf("configure error: %v", err)
+	}
+
+	gconf := fake.regs[tmcRegGCONF]
+	if gconf&tmcGCONFPDNDisable == 0 || gconf&tmcGCONFMstepRegSelect == 0 {
+		t.Errorf("uart control not enabled in gconf: %#x", gconf)
+	}
+	if gconf&tmcGCONFEnSpreadCycle != 0 {
+		t.Errorf("expected stealthchop, gconf: %#x", gconf)
+	}
+	chop := fake.regs[tmcRegCHOPCONF]
+	if mres := chop & tmcCHOPCONFMresMask >> tmcCHOPCONFMresShift; mres != 4 {
+		t.Errorf("expected mres 4, got %d", mres)
+	}
+	if chop&tmcCHOPCONFToffMask == 0 {
+		t.Errorf("driver disabled by toff=0")
+	}
+	// 800mA 、 110mΩ ： CS = 32*√2*0.8*0.13/0.325 - 1 ≈ 13.5 ，需切换为 vsense
+	if chop&tmcCHOPCONFVsense == 0 {
+		t.Errorf("expected vsense set, chopconf: %#x", chop)
+	}
+	ihold := fake.regs[tmcRegIHOLDIRUN]
+	run, hold := ihold>>8&0x1f, ihold&0x1f
+	if run != 25 || hold != 12 {
+		t.Errorf("expected irun 25 ihold 12, got irun %d ihold %d", run, hold)
+	}
+	// TSTEP = 12MHz * 16 / 256 / 1600Hz
+	if fake.regs[tmcRegSGTHRS] != 40 || fake.regs[tmcRegTCOOLTHRS] != 468 {
+		t.Errorf("stallguard not configured: sgthrs %d tcoolthrs %d", fake.regs[tmcRegSGTHRS], fake.regs[tmcRegTCOOLTHRS])
+	}
+
+	// 细分数变化后重新计算 TCOOLTHRS ，阈值为 0 时关闭 StallGuard
+	if err := d.SetMicrosteps(8); err != nil {
+		t.Fatalf("set microsteps error: %v", err)
+	}
+	if fake.regs[tmcRegTCOOLTHRS] != 234 {
+		t.Errorf("expected tcoolthrs 234, got %d", fake.regs[tmcRegTCOOLTHRS])
+	}
+	if err := d.SetStallThreshold(0); err != nil {
+		t.Fatalf("set stall threshold error: %v", err)
+	}
+	if fake.regs[tmcRegTCOOLTHRS] != 0 {
+		t.Errorf("expected stallguard disabled, got tcoolthrs %d", fake.regs[tmcRegTCOOLTHRS])
+	}
+}
+
+// TestTMC2209_Status 测试 TMC2209.Status 和 TMC2209.Stalled
+func TestTMC2209_Status(t *testing.T) {
+	fake := newFakeTMC()
+	d := &TMC2209{UART: fake, Address: 1, Echo: true}
+	if err := d.SetStallThreshold(50); err != nil {
+		t.Fatalf("set stall threshold error: %v", err)
+	}
+
+	fake.regs[tmcRegDRVSTATUS] = 1<<31 | 1<<30 | 20<<16 | 1<<0
+	status, err := d.Status()
+	if err != nil {
+		t.Fatalf("read status error: %v", err)
+	}
+	if !status.OverTemperatureWarning || status.OverTemperature || status.CurrentScale != 20 ||
+		!status.StealthChop || !status.Standstill || status.Fault() {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	fake.regs[tmcRegSGRESULT] = 300
+	if stalled, err := d.Stalled(); err != nil || stalled {
+		t.Errorf("expected not stalled, got %t, %v", stalled, err)
+	}
+	fake.regs[tmcRegSGRESULT] = 90
+	if stalled, err := d.Stalled(); err != nil || !stalled {
+		t.Errorf("expected stalled, got %t, %v", stalled, err)
+	}
+}
+
+// TestTMC2209_ReadRegister_Errors 测试 TMC2209.ReadRegister 的错误处理
+func TestTMC2209_ReadRegister_Errors(t *testing.T) {
+	fake := newFakeTMC()
+	fake.badCRC = true
+	d := &TMC2209{UART: fake, Address: 1, Echo: true}
+	if _, err := d.ReadRegister(tmcRegIOIN); !errors.Is(err, ErrTMCCRC) {
+		t.Errorf("expected crc error, got %v", err)
+	}
+
+	// 地址不匹配时驱动器不回复
+	d.Address = 2
+	if _, err := d.ReadRegister(tmcRegIOIN); !errors.Is(err, ErrTMCTimeout) {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
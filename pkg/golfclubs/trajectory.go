@@ -0,0 +1,271 @@
+package golfclubs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// MaxTrajectoryNameLength 轨迹名最大长度
+	MaxTrajectoryNameLength = 16
+	// minTrajectoryRPM 回放轨迹时的最低转速，避免速度为 0 的运动段
+	minTrajectoryRPM float32 = 1
+)
+
+// TrajectorySample 轨迹采样点
+type TrajectorySample struct {
+	// 相对轨迹开始的时间
+	Time time.Duration
+	// 相对起始位置的角度（单位：度），向前为正
+	Angle float32
+}
+
+// Trajectory 挥杆轨迹
+type Trajectory struct {
+	// 名字
+	Name string
+	// 采样点，按时间递增
+	Samples []TrajectorySample
+}
+
+// TrajectoryLimits 回放轨迹时的限制
+type TrajectoryLimits struct {
+	// 最大转速（单位： rpm ）
+	MaxSpeed float32
+	// 最大加速度（单位： rpm/s ）
+	MaxAcceleration float32
+}
+
+// DefaultTrajectoryLimits 默认回放轨迹时的限制
+var DefaultTrajectoryLimits = TrajectoryLimits{
+	MaxSpeed:        float32(MaxSpeed),
+	MaxAcceleration: float32(MaxAcceleration),
+}
+
+// Validate 检查轨迹是否有效
+func (t *Trajectory) Validate() error {
+	if len(t.Name) > MaxTrajectoryNameLength {
+		return fmt.Errorf("name %q longer than %d", t.Name, MaxTrajectoryNameLength)
+	}
+	if len(t.Samples) < 2 {
+		return errors.New("trajectory requires at least 2 samples")
+	}
+	for i := 1; i < len(t.Samples); i++ {
+		if t.Samples[i].Time <= t.Samples[i-1].Time {
+			return fmt.Errorf("sample %d time %s not after previous %s", i, t.Samples[i].Time, t.Samples[i-1].Time)
+		}
+	}
+	return nil
+}
+
+// Duration 返回轨迹时长
+func (t *Trajectory) Duration() time.Duration {
+	if len(t.Samples) == 0 {
+		return 0
+	}
+	return t.Samples[len(t.Samples)-1].Time - t.Samples[0].Time
+}
+
+// Plan 将轨迹转换为运动段
+//
+// 相邻采样点之间以匀速转动，速度不超过 limits.MaxSpeed ，相邻运动段的速度变化不超过 limits.MaxAcceleration 所允许的范围，
+// 换向和停顿前后速度从 0 开始计算。受限的运动段会比录制时慢，但转动的角度不变。
+func (t *Trajectory) Plan(pulsesPerCircle uint32, limits TrajectoryLimits) ([]Segment, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 每段的期望方向、脉冲数和速度
+	n := len(t.Samples) - 1
+	segments := make([]Segment, 0, n)
+	prevPulses := anglePulsesSigned(t.Samples[0].Angle, pulsesPerCircle)
+	for i := 1; i <= n; i++ {
+		pulses := anglePulsesSigned(t.Samples[i].Angle, pulsesPerCircle)
+		delta := pulses - prevPulses
+		prevPulses = pulses
+		dt := t.Samples[i].Time - t.Samples[i-1].Time
+		if delta == 0 {
+			// 合并相邻的停顿
+			if len(segments) > 0 && segments[len(segments)-1].Pulses == 0 {
+				segments[len(segments)-1].Pause += dt
+			} else {
+				segments = append(segments, Segment{Pause: dt})
+			}
+			continue
+		}
+		abs := uint32(delta)
+		if delta < 0 {
+			abs = uint32(-delta)
+		}
+		rpm := float32(abs) / float32(pulsesPerCircle) / float32(dt.Minutes())
+		segments = append(segments, Segment{
+			Forward: delta > 0,
+			RPM:     min(rpm, limits.MaxSpeed),
+			Pulses:  abs,
+			Pause:   dt,
+		})
+	}
+
+	// 限制加速度：正向遍历限制加速，反向遍历限制减速
+	connected := func(a, b Segment) bool {
+		return a.Pulses > 0 && b.Pulses > 0 && a.Forward == b.Forward
+	}
+	limit := func(i, prev int) {
+		seg := &segments[i]
+		if seg.Pulses == 0 {
+			return
+		}
+		from := float32(0)
+		if prev >= 0 && prev < len(segments) && connected(segments[prev], *seg) {
+			from = segments[prev].RPM
+		}
+		seg.RPM = min(seg.RPM, from+limits.MaxAcceleration*float32(seg.Pause.Seconds()))
+	}
+	for i := range segments {
+		limit(i, i-1)
+	}
+	for i := len(segments) - 1; i >= 0; i-- {
+		limit(i, i+1)
+	}
+
+	for i := range segments {
+		if segments[i].Pulses > 0 {
+			segments[i].RPM = max(segments[i].RPM, minTrajectoryRPM)
+			segments[i].Pause = 0
+		}
+	}
+	return segments, nil
+}
+
+// MarshalBinary 将轨迹编码为二进制
+//
+// 格式为名字长度（1 字节）、名字、采样点数（ 2 字节），然后每个采样点为毫秒时间（ 4 字节）和百分之一度角度（ 4 字节），小端序。
+func (t *Trajectory) MarshalBinary() ([]byte, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	if len(t.Samples) > math.MaxUint16 {
+		return nil, fmt.Errorf("too many samples: %d", len(t.Samples))
+	}
+	buf := make([]byte, 0, 3+len(t.Name)+8*len(t.Samples))
+	buf = append(buf, byte(len(t.Name)))
+	buf = append(buf, t.Name...)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(t.Samples)))
+	for _, s := range t.Samples {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(s.Time.Milliseconds()))
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(int32(math.Round(float64(s.Angle)*100))))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary 从二进制解码轨迹
+func (t *Trajectory) UnmarshalBinary(data []byte) error {
+	errShort := errors.New("trajectory data too short")
+	if len(data) < 1 {
+		return errShort
+	}
+	nameLen := int(data[0])
+	if len(data) < 3+nameLen {
+		return errShort
+	}
+	name := string(data[1 : 1+nameLen])
+	data = data[1+nameLen:]
+	n := int(binary.LittleEndian.Uint16(data))
+	data = data[2:]
+	if len(data) < 8*n {
+		return errShort
+	}
+	samples := make([]TrajectorySample, n)
+	for i := range samples {
+		samples[i] = TrajectorySample{
+			Time:  time.Duration(binary.LittleEndian.Uint32(data[8*i:])) * time.Millisecond,
+			Angle: float32(int32(binary.LittleEndian.Uint32(data[8*i+4:]))) / 100,
+		}
+	}
+	t.Name = name
+	t.Samples = samples
+	return t.Validate()
+}
+
+// RecordTrajectory 以 interval 间隔读取 sensor 录制轨迹
+//
+// 位置变化超过 threshold （单位：度）后开始录制，之后静止超过 idle 或录制时长达到 maxDuration 时结束。
+// invert 为 true 时位置取反。 sleep 用于等待，为 nil 时使用 time.Sleep 。
+func RecordTrajectory(
+	sensor PositionSensor,
+	invert bool,
+	interval, idle, maxDuration time.Duration,
+	threshold float32,
+	sleep func(time.Duration),
+) (*Trajectory, error) {
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	read := func() (float32, error) {
+		pos, err := sensor.Position()
+		if err != nil {
+			return 0, err
+		}
+		if invert {
+			pos = -pos
+		}
+		return float32(pos) * 360 / float32(PositionUnitsPerCircle), nil
+	}
+	abs := func(v float32) float32 {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}
+
+	zero, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	// 等待开始转动
+	var elapsed time.Duration
+	angle := float32(0)
+	for abs(angle) < threshold {
+		if elapsed >= maxDuration {
+			return nil, errors.New("no motion detected")
+		}
+		sleep(interval)
+		elapsed += interval
+		a, err := read()
+		if err != nil {
+			return nil, err
+		}
+		angle = a - zero
+	}
+
+	t := &Trajectory{Samples: []TrajectorySample{{Time: 0, Angle: 0}, {Time: interval, Angle: angle}}}
+	lastMove := interval
+	for now := interval; now < maxDuration && now-lastMove < idle; {
+		sleep(interval)
+		now += interval
+		a, err := read()
+		if err != nil {
+			return nil, err
+		}
+		a -= zero
+		last := t.Samples[len(t.Samples)-1].Angle
+		if abs(a-last) >= threshold/4 {
+			lastMove = now
+		}
+		t.Samples = append(t.Samples, TrajectorySample{Time: now, Angle: a})
+	}
+	// 去掉结尾的静止部分
+	for len(t.Samples) > 2 && t.Samples[len(t.Samples)-1].Time > lastMove {
+		t.Samples = t.Samples[:len(t.Samples)-1]
+	}
+	return t, nil
+}
+
+// anglePulsesSigned 返回转动 angle 度所需脉冲数， angle 可以为负数
+func anglePulsesSigned(angle float32, pulsesPerCircle uint32) int32 {
+	return int32(math.Round(float64(angle) * float64(pulsesPerCircle) / 360))
+}
@@ -0,0 +1,127 @@
+package golfclubs
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTrajectory_Plan 测试 Trajectory.Plan
+func TestTrajectory_Plan(t *testing.T) {
+	const ppc = 360
+	ms := time.Millisecond
+	traj := &Trajectory{Name: "trick", Samples: []TrajectorySample{
+		{Time: 0, Angle: 0},
+		{Time: 100 * ms, Angle: 10},
+		{Time: 200 * ms, Angle: 30},
+		{Time: 300 * ms, Angle: 30},
+		{Time: 400 * ms, Angle: 30},
+		{Time: 500 * ms, Angle: -60},
+	}}
+	segments, err := traj.Plan(ppc, TrajectoryLimits{MaxSpeed: 100, MaxAcceleration: 1000})
+	if err != nil {
+		t.Fatalf("plan error: %v", err)
+	}
+	if len(segments) != 4 {
+		t.Fatalf("expected 4 segments, got %+v", segments)
+	}
+
+	// 10°/100ms = 16.7rpm ，受下一段（同向）及从 0 加速的限制： 0+1000*0.1=100
+	if s := segments[0]; !s.Forward || s.Pulses != 10 || s.RPM < 16.6 || s.RPM > 16.7 {
+		t.Errorf("unexpected segment 0: %+v", s)
+	}
+	// 20°/100ms = 33.3rpm ，之后停顿，需在 0.1s 内减到 0 ：上限 100
+	if s := segments[1]; !s.Forward || s.Pulses != 20 || s.RPM < 33.3 || s.RPM > 33.4 {
+		t.Errorf("unexpected segment 1: %+v", s)
+	}
+	if s := segments[2]; s.Pulses != 0 || s.Pause != 200*ms {
+		t.Errorf("expected merged pause of 200ms, got %+v", s)
+	}
+	// 90°/100ms = 150rpm ，限速 100
+	if s := segments[3]; s.Forward || s.Pulses != 90 || s.RPM != 100 {
+		t.Errorf("unexpected segment 3: %+v", s)
+	}
+
+	// 加速度限制
+	segments, err = traj.Plan(ppc, TrajectoryLimits{MaxSpeed: 100, MaxAcceleration: 100})
+	if err != nil {
+		t.Fatalf("plan error: %v", err)
+	}
+	if s := segments[3]; s.RPM != 10 {
+		t.Errorf("expected acceleration limited rpm 10, got %+v", s)
+	}
+
+	if _, err := (&Trajectory{Samples: []TrajectorySample{{Time: 0}, {Time: 0, Angle: 1}}}).Plan(ppc, DefaultTrajectoryLimits); err == nil {
+		t.Errorf("expected error for non increasing time")
+	}
+}
+
+// TestTrajectory_MarshalBinary 测试 Trajectory 二进制编解码
+func TestTrajectory_MarshalBinary(t *testing.T) {
+	traj := &Trajectory{Name: "loop", Samples: []TrajectorySample{
+		{Time: 0, Angle: 0},
+		{Time: 20 * time.Millisecond, Angle: -12.5},
+		{Time: 40 * time.Millisecond, Angle: 370.25},
+	}}
+	data, err := traj.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	got := &Trajectory{}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if got.Name != traj.Name || len(got.Samples) != len(traj.Samples) {
+		t.Fatalf("unexpected trajectory: %+v", got)
+	}
+	for i := range got.Samples {
+		if got.Samples[i] != traj.Samples[i] {
+			t.Errorf("sample %d: expected %+v, got %+v", i, traj.Samples[i], got.Samples[i])
+		}
+	}
+	if err := got.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("expected error for truncated data")
+	}
+}
+
+// scriptedSensor 按顺序返回预设位置的 PositionSensor
+type scriptedSensor struct {
+	positions []int32
+}
+
+func (s *scriptedSensor) Position() (int32, error) {
+	p := s.positions[0]
+	if len(s.positions) > 1 {
+		s.positions = s.positions[1:]
+	}
+	return p, nil
+}
+
+// TestRecordTrajectory 测试 RecordTrajectory
+func TestRecordTrajectory(t *testing.T) {
+	// 4096 单位一圈，每单位约 0.088°
+	sensor := &scriptedSensor{positions: []int32{
+		100, 100, 101, 200, 400, 700, 800, 800, 800, 800, 800,
+	}}
+	traj, err := RecordTrajectory(sensor, true, 10*time.Millisecond, 30*time.Millisecond, time.Second, 2, func(time.Duration) {})
+	if err != nil {
+		t.Fatalf("record error: %v", err)
+	}
+	// 从 200 开始录制，在 800 后静止
+	var angles []int
+	for _, s := range traj.Samples {
+		angles = append(angles, int(s.Angle))
+	}
+	expected := []int{0, -8, -26, -52, -61}
+	if len(angles) != len(expected) {
+		t.Fatalf("expected angles %v, got %v", expected, angles)
+	}
+	for i := range expected {
+		if angles[i] != expected[i] {
+			t.Errorf("expected angles %v, got %v", expected, angles)
+			break
+		}
+	}
+	if d := traj.Duration(); d != 40*time.Millisecond {
+		t.Errorf("expected duration 40ms, got %s", d)
+	}
+}
@@ -0,0 +1,380 @@
+package history
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+)
+
+const (
+	// RecordSize 每条记录在 Flash 中所占字节数
+	RecordSize = 32
+	// MaxClubLength 记录的球杆名最大长度，超出部分被截断
+	MaxClubLength = 11
+	// recordVersion 记录格式版本
+	recordVersion = 1
+	// maxUint24 24 位字段的最大值
+	maxUint24 = 1<<24 - 1
+	// emptySeq 未写入的记录序号
+	emptySeq = 0xffffffff
+)
+
+// Fault 挥杆故障类型
+type Fault uint8
+
+const (
+	// FaultNone 没有故障
+	FaultNone Fault = iota
+	// FaultStall 堵转
+	FaultStall
+	// FaultPosition 位置偏差
+	FaultPosition
+	// FaultOther 其它错误
+	FaultOther
+	// FaultSlip IMU 检测到皮带打滑
+	FaultSlip
+	// FaultFlex IMU 检测到机架或杆身弯曲回弹
+	FaultFlex
+	// FaultLowVoltage 电源电压过低，拒绝挥杆
+	FaultLowVoltage
+	// FaultCooldown 超过挥杆次数限制，电机冷却中，拒绝挥杆
+	FaultCooldown
+
+	// clearMarker 清除记录后写入的标记，只用于保留序号，不是挥杆记录
+	clearMarker Fault = 0xff
+)
+
+// String 返回故障类型的字符串表示
+func (f Fault) String() string {
+	switch f {
+	case FaultNone:
+		return ""
+	case FaultStall:
+		return "stall"
+	case FaultPosition:
+		return "position"
+	case FaultOther:
+		return "error"
+	case FaultSlip:
+		return "slip"
+	case FaultFlex:
+		return "flex"
+	case FaultLowVoltage:
+		return "low-voltage"
+	case FaultCooldown:
+		return "cooldown"
+	}
+	return fmt.Sprintf("Fault(%d)", f)
+}
+
+// Record 一次挥杆记录
+type Record struct {
+	// 序号，从 1 开始跨重启递增，清除记录后继续递增
+	Seq uint32
+	// 挥杆时距开机的时间，精确到秒，最长约 194 天
+	Uptime time.Duration
+	// 球杆或参数名
+	Club string
+	// 指令速度百分比，推杆和轨迹回放为 0
+	Speed uint8
+	// 实际运动时长，最长约 65 秒
+	Duration time.Duration
+	// 电机步数，最多 16777215
+	Steps uint32
+	// 故障
+	Fault Fault
+	// IMU 实测峰值角速度（单位：度/秒），没有 IMU 时为 0
+	PeakVelocity uint16
+	// 挥杆时的电机电源电压（单位：毫伏），没有电源检测时为 0
+	Voltage uint16
+}
+
+// MarshalBinary 将记录编码为 RecordSize 字节
+//
+// 布局（小端）：
+//
+//	0  序号 uint32
+//	4  开机时间（秒） uint24
+//	7  指令速度 uint8
+//	8  运动时长（毫秒） uint16
+//	10 实测峰值角速度 uint16
+//	12 电机步数 uint24
+//	15 故障 uint8
+//	16 电源电压（毫伏） uint16
+//	18 格式版本 uint8
+//	19 球杆名 11 字节，不足补 0
+//	30 前 30 字节的 CRC-32 的低 16 位
+func (r *Record) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, RecordSize)
+	binary.LittleEndian.PutUint32(buf[0:], r.Seq)
+	putUint24(buf[4:], uint32(min(int64(r.Uptime/time.Second), maxUint24)))
+	buf[7] = r.Speed
+	binary.LittleEndian.PutUint16(buf[8:], uint16(min(r.Duration.Milliseconds(), 0xffff)))
+	binary.LittleEndian.PutUint16(buf[10:], r.PeakVelocity)
+	putUint24(buf[12:], min(r.Steps, maxUint24))
+	buf[15] = byte(r.Fault)
+	binary.LittleEndian.PutUint16(buf[16:], r.Voltage)
+	buf[18] = recordVersion
+	copy(buf[19:19+MaxClubLength], r.Club)
+	binary.LittleEndian.PutUint16(buf[30:], uint16(crc32.ChecksumIEEE(buf[:30])))
+	return buf, nil
+}
+
+// UnmarshalBinary 从 RecordSize 字节解码记录
+func (r *Record) UnmarshalBinary(data []byte) error {
+	if len(data) < RecordSize {
+		return errors.New("record too short")
+	}
+	if binary.LittleEndian.Uint16(data[30:]) != uint16(crc32.ChecksumIEEE(data[:30])) {
+		return storage.ErrCorrupted
+	}
+	if data[18] != recordVersion {
+		return fmt.Errorf("unsupported record version %d", data[18])
+	}
+	*r = Record{
+		Seq:          binary.LittleEndian.Uint32(data[0:]),
+		Uptime:       time.Duration(uint24(data[4:])) * time.Second,
+		Speed:        data[7],
+		Duration:     time.Duration(binary.LittleEndian.Uint16(data[8:])) * time.Millisecond,
+		PeakVelocity: binary.LittleEndian.Uint16(data[10:]),
+		Steps:        uint24(data[12:]),
+		Fault:        Fault(data[15]),
+		Voltage:      binary.LittleEndian.Uint16(data[16:]),
+		Club:         strings.TrimRight(string(data[19:19+MaxClubLength]), "\x00"),
+	}
+	return nil
+}
+
+// putUint24 以小端写入 v 的低 24 位
+func putUint24(b []byte, v uint32) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+}
+
+// uint24 以小端读取 24 位无符号整数
+func uint24(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
+
+// Log 保存在 Flash 中的挥杆记录环形缓冲区
+//
+// 记录依次追加写入区域，写到一个擦除块开头时先擦除该块，丢弃其中最旧的记录。
+// 区域至少需要两个擦除块，以便擦除时仍保留其它块中的记录。
+type Log struct {
+	region  *storage.Region
+	lock    sync.Mutex
+	records []Record
+	next    int64
+	seq     uint32
+}
+
+// Open 扫描区域中的记录并打开 *Log
+func Open(region *storage.Region) (*Log, error) {
+	if region == nil {
+		return nil, storage.ErrUnavailable
+	}
+	ebs := region.Device.EraseBlockSize()
+	if region.Size < 2*ebs || ebs%RecordSize != 0 {
+		return nil, fmt.Errorf("history region requires at least 2 erase blocks of multiple of %d bytes", RecordSize)
+	}
+	l := &Log{region: region}
+
+	buf := make([]byte, region.Size)
+	if _, err := region.Device.ReadAt(buf, region.Offset); err != nil {
+		return nil, fmt.Errorf("read history error: %w", err)
+	}
+	var lastSlot int64 = -1
+	for slot := int64(0); slot < region.Size/RecordSize; slot++ {
+		data := buf[slot*RecordSize : (slot+1)*RecordSize]
+		if binary.LittleEndian.Uint32(data) == emptySeq {
+			continue
+		}
+		var r Record
+		if err := r.UnmarshalBinary(data); err != nil {
+			// 跳过写入中断的记录
+			continue
+		}
+		if r.Fault != clearMarker {
+			l.records = append(l.records, r)
+		}
+		if lastSlot < 0 || r.Seq > l.seq {
+			l.seq = r.Seq
+			lastSlot = slot
+		}
+	}
+	sort.Slice(l.records, func(i, j int) bool {
+		return l.records[i].Seq < l.records[j].Seq
+	})
+	l.next = (lastSlot + 1) * RecordSize % region.Size
+	return l, nil
+}
+
+// Append 追加一条记录，自动分配序号
+func (l *Log) Append(r Record) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	dev := l.region.Device
+	ebs := dev.EraseBlockSize()
+	if l.next%ebs == 0 {
+		// 擦除即将写入的块，丢弃其中的记录
+		if err := dev.EraseBlocks((l.region.Offset+l.next)/ebs, 1); err != nil {
+			return fmt.Errorf("erase history block error: %w", err)
+		}
+		l.dropBlock(l.next / ebs)
+	}
+
+	l.seq++
+	r.Seq = l.seq
+	if err := l.write(r); err != nil {
+		return err
+	}
+	l.records = append(l.records, r)
+	return nil
+}
+
+// write 将 r 写入下一个槽位，槽位所在块需已擦除
+func (l *Log) write(r Record) error {
+	dev := l.region.Device
+	data, _ := r.MarshalBinary()
+
+	// 按写入块写入，其它位置填 0xff ，不会改变已写入的记录
+	wbs := max(dev.WriteBlockSize(), RecordSize)
+	offset := l.region.Offset + l.next
+	pageStart := offset / wbs * wbs
+	page := make([]byte, wbs)
+	for i := range page {
+		page[i] = 0xff
+	}
+	copy(page[offset-pageStart:], data)
+	if _, err := dev.WriteAt(page, pageStart); err != nil {
+		return fmt.Errorf("write history error: %w", err)
+	}
+
+	l.next = (l.next + RecordSize) % l.region.Size
+	return nil
+}
+
+// dropBlock 从内存中删除第 block 个擦除块中的记录
+func (l *Log) dropBlock(block int64) {
+	perBlock := l.region.Device.EraseBlockSize() / RecordSize
+	total := l.region.Size / RecordSize
+	// 记录按序号连续写入槽位，序号为 seq 的记录所在槽位由最新记录倒推
+	newestSlot := (l.next/RecordSize - 1 + total) % total
+	kept := l.records[:0]
+	for _, r := range l.records {
+		slot := (newestSlot - int64(l.seq-r.Seq)%total + total) % total
+		if slot/perBlock != block {
+			kept = append(kept, r)
+		}
+	}
+	l.records = kept
+}
+
+// Clear 擦除所有记录，序号继续递增
+//
+// 擦除后写入带有最新序号的清除标记，重启后也从该序号继续。
+func (l *Log) Clear() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if err := l.region.Erase(); err != nil {
+		return err
+	}
+	l.records = nil
+	l.next = 0
+	if l.seq == 0 {
+		return nil
+	}
+	return l.write(Record{Seq: l.seq, Fault: clearMarker})
+}
+
+// Records 返回所有记录，按序号递增
+func (l *Log) Records() []Record {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]Record(nil), l.records...)
+}
+
+// Last 返回最近的 n 条记录，按序号递增
+func (l *Log) Last(n int) []Record {
+	records := l.Records()
+	if len(records) > n {
+		records = records[len(records)-n:]
+	}
+	return records
+}
+
+// Total 返回最新的序号，即累计记录的挥杆次数，包括已被覆盖和已清除的记录
+func (l *Log) Total() uint32 {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.seq
+}
+
+// ClubStats 一个球杆的统计
+type ClubStats struct {
+	// 球杆名
+	Club string
+	// 挥杆次数
+	Count int
+	// 故障次数
+	Faults int
+	// 平均指令速度百分比，不计速度为 0 的记录
+	AverageSpeed float32
+	// 总步数
+	Steps uint64
+	// 最大 IMU 实测峰值角速度（单位：度/秒）
+	PeakVelocity uint16
+}
+
+// Summarize 按球杆统计记录，按首次出现的顺序排列
+func Summarize(records []Record) []ClubStats {
+	var stats []ClubStats
+	index := map[string]int{}
+	speedCounts := map[string]int{}
+	for _, r := range records {
+		i, ok := index[r.Club]
+		if !ok {
+			i = len(stats)
+			index[r.Club] = i
+			stats = append(stats, ClubStats{Club: r.Club})
+		}
+		s := &stats[i]
+		s.Count++
+		if r.Fault != FaultNone {
+			s.Faults++
+		}
+		if r.Speed > 0 {
+			// 增量计算平均值
+			speedCounts[r.Club]++
+			s.AverageSpeed += (float32(r.Speed) - s.AverageSpeed) / float32(speedCounts[r.Club])
+		}
+		s.Steps += uint64(r.Steps)
+		s.PeakVelocity = max(s.PeakVelocity, r.PeakVelocity)
+	}
+	return stats
+}
+
+// WriteCSV 将记录以 CSV 格式写入 w
+func WriteCSV(w io.Writer, records []Record) error {
+	if _, err := fmt.Fprint(w, "seq,uptime_ms,club,speed,duration_ms,steps,fault,peak_dps,supply_mv\r\n"); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if _, err := fmt.Fprintf(w, "%d,%d,%s,%d,%d,%d,%s,%d,%d\r\n",
+			r.Seq, r.Uptime.Milliseconds(), r.Club, r.Speed, r.Duration.Milliseconds(), r.Steps, r.Fault, r.PeakVelocity, r.Voltage,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage/storagetest"
+)
+
+// TestRecord_MarshalBinary 测试 Record 编解码
+func TestRecord_MarshalBinary(t *testing.T) {
+	r := Record{
+		Seq:          7,
+		Uptime:       90 * time.Second,
+		Club:         "Sand Wedge (long)",
+		Speed:        80,
+		Duration:     1500 * time.Millisecond,
+		Steps:        1200,
+		Fault:        FaultStall,
+		PeakVelocity: 1450,
+		Voltage:      11850,
+	}
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	var got Record
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	r.Club = r.Club[:MaxClubLength]
+	if got != r {
+		t.Errorf("expected %+v, got %+v", r, got)
+	}
+	data[20]++
+	if err := got.UnmarshalBinary(data); err == nil {
+		t.Errorf("expected crc error")
+	}
+
+	// 超出字段范围的值被截断
+	r.Uptime = 200 * 24 * time.Hour
+	r.Steps = 1 << 25
+	data, _ = r.MarshalBinary()
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if got.Uptime != maxUint24*time.Second || got.Steps != maxUint24 {
+		t.Errorf("expected saturated uptime and steps, got %s %d", got.Uptime, got.Steps)
+	}
+
+	// 不支持的版本
+	data[18] = recordVersion + 1
+	binary.LittleEndian.PutUint16(data[30:], uint16(crc32.ChecksumIEEE(data[:30])))
+	if err := got.UnmarshalBinary(data); err == nil {
+		t.Errorf("expected version error")
+	}
+}
+
+// TestLog 测试 Log 追加、环绕和重新打开
+func TestLog(t *testing.T) {
+	dev := storagetest.NewMemDevice(5120, 1024)
+	// 3 个擦除块，每块 32 条
+	region := &storage.Region{Device: dev, Offset: 1024, Size: 3072}
+	l, err := Open(region)
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+
+	for i := 1; i <= 100; i++ {
+		if err := l.Append(Record{Club: "Driver", Speed: uint8(i % 100), Steps: 10}); err != nil {
+			t.Fatalf("append %d error: %v", i, err)
+		}
+	}
+	// 写第 97 条时擦除了第一块，剩余 33..100
+	records := l.Records()
+	if len(records) != 68 || records[0].Seq != 33 || records[len(records)-1].Seq != 100 {
+		t.Fatalf("unexpected records: %d from %d to %d", len(records), records[0].Seq, records[len(records)-1].Seq)
+	}
+	if total := l.Total(); total != 100 {
+		t.Errorf("expected total 100, got %d", total)
+	}
+	if dev.Data[0] != 0xff || dev.Data[4096] != 0xff {
+		t.Errorf("wrote outside region")
+	}
+
+	// 重新打开
+	l, err = Open(region)
+	if err != nil {
+		t.Fatalf("reopen error: %v", err)
+	}
+	reopened := l.Records()
+	if len(reopened) != len(records) || reopened[0] != records[0] || reopened[len(reopened)-1] != records[len(records)-1] {
+		t.Fatalf("unexpected records after reopen: %d", len(reopened))
+	}
+	if err := l.Append(Record{Club: "Wedge"}); err != nil {
+		t.Fatalf("append error: %v", err)
+	}
+	last := l.Last(2)
+	if len(last) != 2 || last[1].Seq != 101 || last[1].Club != "Wedge" || last[0].Seq != 100 {
+		t.Errorf("unexpected last records: %+v", last)
+	}
+}
+
+// TestLog_Clear 测试清除记录后序号和累计次数跨重启保留
+func TestLog_Clear(t *testing.T) {
+	dev := storagetest.NewMemDevice(5120, 1024)
+	region := &storage.Region{Device: dev, Offset: 1024, Size: 3072}
+	l, err := Open(region)
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+
+	// 没有记录时清除
+	if err := l.Clear(); err != nil {
+		t.Fatalf("clear error: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := l.Append(Record{Club: "Driver"}); err != nil {
+			t.Fatalf("append %d error: %v", i, err)
+		}
+	}
+	if err := l.Clear(); err != nil {
+		t.Fatalf("clear error: %v", err)
+	}
+	if records := l.Records(); len(records) != 0 {
+		t.Errorf("expected no records after clear, got %+v", records)
+	}
+	if total := l.Total(); total != 5 {
+		t.Errorf("expected total 5 after clear, got %d", total)
+	}
+
+	// 清除后直接重启
+	l, err = Open(region)
+	if err != nil {
+		t.Fatalf("reopen error: %v", err)
+	}
+	if records := l.Records(); len(records) != 0 {
+		t.Errorf("expected clear marker hidden, got %+v", records)
+	}
+	if total := l.Total(); total != 5 {
+		t.Errorf("expected total 5 after reopen, got %d", total)
+	}
+	if err := l.Append(Record{Club: "Spoon"}); err != nil {
+		t.Fatalf("append error: %v", err)
+	}
+	l, err = Open(region)
+	if err != nil {
+		t.Fatalf("reopen error: %v", err)
+	}
+	if records := l.Records(); len(records) != 1 || records[0].Seq != 6 || records[0].Club != "Spoon" {
+		t.Errorf("unexpected records after clear: %+v", records)
+	}
+
+	// 写第 101 条时擦除了清除标记所在的第一块，剩余 37..102
+	for i := 0; i < 96; i++ {
+		if err := l.Append(Record{Club: "Iron"}); err != nil {
+			t.Fatalf("append %d error: %v", i, err)
+		}
+	}
+	l, err = Open(region)
+	if err != nil {
+		t.Fatalf("reopen error: %v", err)
+	}
+	records := l.Records()
+	if l.Total() != 102 || len(records) != 66 || records[0].Seq != 37 {
+		t.Errorf("unexpected records after wraparound: total %d, %d from %d", l.Total(), len(records), records[0].Seq)
+	}
+}
+
+// TestSummarize 测试 Summarize 和 WriteCSV
+func TestSummarize(t *testing.T) {
+	records := []Record{
+		{Seq: 1, Club: "Driver", Speed: 100, Steps: 1000},
+		{Seq: 2, Club: "Putter", Steps: 50},
+		{Seq: 3, Club: "Driver", Speed: 80, Steps: 900, Fault: FaultPosition, PeakVelocity: 1200},
+	}
+	stats := Summarize(records)
+	expected := []ClubStats{
+		{Club: "Driver", Count: 2, Faults: 1, AverageSpeed: 90, Steps: 1900, PeakVelocity: 1200},
+		{Club: "Putter", Count: 1, Steps: 50},
+	}
+	if len(stats) != len(expected) {
+		t.Fatalf("expected %+v, got %+v", expected, stats)
+	}
+	for i := range expected {
+		if stats[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], stats[i])
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteCSV(buf, records[2:]); err != nil {
+		t.Fatalf("write csv error: %v", err)
+	}
+	if got := buf.String(); got != "seq,uptime_ms,club,speed,duration_ms,steps,fault,peak_dps,supply_mv\r\n3,0,Driver,80,0,900,position,1200,0\r\n" {
+		t.Errorf("unexpected csv %q", got)
+	}
+}
@@ -0,0 +1,137 @@
+package imu
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+)
+
+// ErrNoSamples 没有实测角速度
+var ErrNoSamples = errors.New("no imu samples")
+
+// Limits 判断挥杆异常的阈值
+type Limits struct {
+	// 对齐指令和实测角速度时允许的最大延迟
+	MaxLag time.Duration
+	// 向前转过的角度比指令少超过该比例时认为皮带打滑
+	Slip float32
+	// 峰值角速度比指令大超过该比例时认为机架或杆身弯曲回弹
+	Flex float32
+}
+
+// DefaultLimits 默认阈值
+var DefaultLimits = Limits{
+	MaxLag: 100 * time.Millisecond,
+	Slip:   0.1,
+	Flex:   0.15,
+}
+
+// Analysis 一次运动的指令与实测角速度比较结果，角速度单位均为 度/秒 ，向前为正
+type Analysis struct {
+	// 实测样本数
+	Samples int
+	// 实测相对指令的延迟
+	Lag time.Duration
+	// 指令峰值角速度
+	PeakCommanded float32
+	// 实测峰值角速度
+	PeakMeasured float32
+	// 对齐后实测与指令角速度的均方根误差
+	RMSError float32
+	// 实测向前转过的角度相对指令的偏差比例，负数表示转得少
+	TravelError float32
+	// 实测峰值角速度相对指令的偏差比例
+	Overshoot float32
+	// 疑似皮带打滑
+	Slip bool
+	// 疑似机架或杆身弯曲回弹
+	Flex bool
+}
+
+// ClubHeadSpeed 返回转轴到杆头距离为 length （单位：米）时的峰值杆头速度（单位：米/秒）
+func (a *Analysis) ClubHeadSpeed(length float32) float32 {
+	return a.PeakMeasured * math.Pi / 180 * length
+}
+
+// Commanded 返回运动段 segments 以 interval 间隔采样的指令角速度（单位：度/秒），向前为正
+func Commanded(segments []golfclubs.Segment, pulsesPerCircle uint32, interval time.Duration) []float32 {
+	var ret []float32
+	var end time.Duration
+	for _, seg := range segments {
+		var rate float32
+		d := seg.Pause
+		if seg.Pulses > 0 && seg.RPM > 0 {
+			rate = seg.RPM * 6
+			if !seg.Forward {
+				rate = -rate
+			}
+			d = time.Duration(float64(seg.Pulses) * 60 / float64(seg.RPM) / float64(pulsesPerCircle) * float64(time.Second))
+		}
+		end += d
+		for time.Duration(len(ret))*interval < end {
+			ret = append(ret, rate)
+		}
+	}
+	return ret
+}
+
+// Analyze 比较以 interval 间隔采样的指令角速度 commanded 和实测角速度 measured
+//
+// 实测角速度在 limits.MaxLag 内平移以与指令对齐，再计算误差。
+func Analyze(commanded, measured []float32, interval time.Duration, limits Limits) (Analysis, error) {
+	if len(measured) == 0 {
+		return Analysis{}, ErrNoSamples
+	}
+	a := Analysis{Samples: len(measured)}
+
+	// 寻找误差最小的延迟
+	bestLag, bestErr := 0, float32(math.MaxFloat32)
+	for lag := 0; time.Duration(lag)*interval <= limits.MaxLag && lag < len(measured); lag++ {
+		if e := rmsError(commanded, measured[lag:]); e < bestErr {
+			bestLag, bestErr = lag, e
+		}
+	}
+	a.Lag = time.Duration(bestLag) * interval
+	a.RMSError = bestErr
+
+	cmdTravel, measTravel := float32(0), float32(0)
+	for _, v := range commanded {
+		a.PeakCommanded = max(a.PeakCommanded, v)
+		cmdTravel += max(v, 0)
+	}
+	for _, v := range measured {
+		a.PeakMeasured = max(a.PeakMeasured, v)
+		measTravel += max(v, 0)
+	}
+	if cmdTravel > 0 {
+		a.TravelError = measTravel/cmdTravel - 1
+		a.Slip = a.TravelError < -limits.Slip
+	}
+	if a.PeakCommanded > 0 {
+		a.Overshoot = a.PeakMeasured/a.PeakCommanded - 1
+		a.Flex = a.Overshoot > limits.Flex
+	}
+	return a, nil
+}
+
+// rmsError 返回 a 与 b 对应元素的均方根误差，较短的一方视为后续为 0
+func rmsError(a, b []float32) float32 {
+	n := max(len(a), len(b))
+	if n == 0 {
+		return 0
+	}
+	var sum float64
+	for i := 0; i < n; i++ {
+		var x, y float32
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		sum += float64((x - y) * (x - y))
+	}
+	return float32(math.Sqrt(sum / float64(n)))
+}
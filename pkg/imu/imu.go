@@ -0,0 +1,226 @@
+package imu
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+const (
+	// DefaultAddress MPU6050 和 ICM-42688 的默认 I2C 地址（ AD0 接地）
+	DefaultAddress uint16 = 0x68
+	// AlternateAddress AD0 接高电平时的 I2C 地址
+	AlternateAddress uint16 = 0x69
+
+	// gyroSensitivity ±2000 度/秒 量程下每 度/秒 对应的读数
+	gyroSensitivity float32 = 16.4
+
+	regWhoAmI uint8 = 0x75
+)
+
+// ErrUnknownDevice I2C 地址上不是支持的 IMU
+var ErrUnknownDevice = errors.New("unknown imu device")
+
+// Axis 陀螺仪轴
+type Axis uint8
+
+const (
+	// AxisX X 轴
+	AxisX Axis = iota
+	// AxisY Y 轴
+	AxisY
+	// AxisZ Z 轴
+	AxisZ
+)
+
+// String 返回轴名
+func (a Axis) String() string {
+	switch a {
+	case AxisX:
+		return "x"
+	case AxisY:
+		return "y"
+	case AxisZ:
+		return "z"
+	}
+	return "unknown"
+}
+
+// Sensor IMU 陀螺仪
+type Sensor interface {
+	// AngularRate 返回三轴角速度（单位：度/秒），按 AxisX 、 AxisY 、 AxisZ 排列
+	AngularRate() ([3]float32, error)
+}
+
+// MPU6050 通过 I2C 连接的 MPU6050
+type MPU6050 struct {
+	// I2C 总线
+	Bus drivers.I2C
+	// I2C 地址
+	// 默认为 DefaultAddress
+	Address uint16
+}
+
+var _ Sensor = (*MPU6050)(nil)
+
+const (
+	mpu6050WhoAmI uint8 = 0x68
+
+	mpu6050RegSampleRateDiv uint8 = 0x19
+	mpu6050RegConfig        uint8 = 0x1A
+	mpu6050RegGyroConfig    uint8 = 0x1B
+	mpu6050RegGyroXOutH     uint8 = 0x43
+	mpu6050RegPowerMgmt1    uint8 = 0x6B
+)
+
+// Configure 唤醒并配置为 ±2000 度/秒 量程、 1kHz 采样
+func (s *MPU6050) Configure() error {
+	if err := checkWhoAmI(s.Bus, address(s.Address), mpu6050WhoAmI); err != nil {
+		return err
+	}
+	for _, w := range [][2]uint8{
+		// 唤醒，以 X 轴陀螺仪为时钟源
+		{mpu6050RegPowerMgmt1, 0x01},
+		// 数字低通滤波 188Hz ，陀螺仪输出 1kHz
+		{mpu6050RegConfig, 0x01},
+		{mpu6050RegSampleRateDiv, 0x00},
+		// ±2000 度/秒
+		{mpu6050RegGyroConfig, 0x18},
+	} {
+		if err := writeReg(s.Bus, address(s.Address), w[0], w[1]); err != nil {
+			return fmt.Errorf("configure mpu6050 error: %w", err)
+		}
+	}
+	return nil
+}
+
+// AngularRate 返回三轴角速度
+func (s *MPU6050) AngularRate() ([3]float32, error) {
+	return readGyro(s.Bus, address(s.Address), mpu6050RegGyroXOutH)
+}
+
+// ICM42688 通过 I2C 连接的 ICM-42688-P
+type ICM42688 struct {
+	// I2C 总线
+	Bus drivers.I2C
+	// I2C 地址
+	// 默认为 DefaultAddress
+	Address uint16
+}
+
+var _ Sensor = (*ICM42688)(nil)
+
+const (
+	icm42688WhoAmI uint8 = 0x47
+
+	icm42688RegDeviceConfig uint8 = 0x11
+	icm42688RegGyroDataX1   uint8 = 0x25
+	icm42688RegPowerMgmt0   uint8 = 0x4E
+	icm42688RegGyroConfig0  uint8 = 0x4F
+
+	// icm42688ResetDelay 软复位后等待时长
+	icm42688ResetDelay = time.Millisecond
+	// icm42688StartupDelay 陀螺仪启动时长
+	icm42688StartupDelay = 50 * time.Millisecond
+)
+
+// Configure 复位并配置为 ±2000 度/秒 量程、 1kHz 采样，仅开启陀螺仪
+func (s *ICM42688) Configure() error {
+	addr := address(s.Address)
+	if err := checkWhoAmI(s.Bus, addr, icm42688WhoAmI); err != nil {
+		return err
+	}
+	if err := writeReg(s.Bus, addr, icm42688RegDeviceConfig, 0x01); err != nil {
+		return fmt.Errorf("reset icm42688 error: %w", err)
+	}
+	time.Sleep(icm42688ResetDelay)
+	// ±2000 度/秒， 1kHz
+	if err := writeReg(s.Bus, addr, icm42688RegGyroConfig0, 0x06); err != nil {
+		return fmt.Errorf("configure icm42688 error: %w", err)
+	}
+	// 陀螺仪低噪声模式，加速度计关闭
+	if err := writeReg(s.Bus, addr, icm42688RegPowerMgmt0, 0x0C); err != nil {
+		return fmt.Errorf("configure icm42688 error: %w", err)
+	}
+	time.Sleep(icm42688StartupDelay)
+	return nil
+}
+
+// AngularRate 返回三轴角速度
+func (s *ICM42688) AngularRate() ([3]float32, error) {
+	return readGyro(s.Bus, address(s.Address), icm42688RegGyroDataX1)
+}
+
+// checkWhoAmI 检查 WHO_AM_I 寄存器
+func checkWhoAmI(bus drivers.I2C, addr uint16, expected uint8) error {
+	buf := []byte{0}
+	if err := bus.Tx(addr, []byte{regWhoAmI}, buf); err != nil {
+		return fmt.Errorf("read imu who am i error: %w", err)
+	}
+	if buf[0] != expected {
+		return fmt.Errorf("%w: who am i %#x, expected %#x", ErrUnknownDevice, buf[0], expected)
+	}
+	return nil
+}
+
+// writeReg 写寄存器
+func writeReg(bus drivers.I2C, addr uint16, reg, value uint8) error {
+	return bus.Tx(addr, []byte{reg, value}, nil)
+}
+
+// readGyro 从 reg 开始读取三轴大端 16 位角速度
+func readGyro(bus drivers.I2C, addr uint16, reg uint8) ([3]float32, error) {
+	buf := make([]byte, 6)
+	if err := bus.Tx(addr, []byte{reg}, buf); err != nil {
+		return [3]float32{}, fmt.Errorf("read imu gyro error: %w", err)
+	}
+	var ret [3]float32
+	for i := range ret {
+		ret[i] = float32(int16(uint16(buf[2*i])<<8|uint16(buf[2*i+1]))) / gyroSensitivity
+	}
+	return ret, nil
+}
+
+// address 返回 I2C 地址
+func address(addr uint16) uint16 {
+	if addr == 0 {
+		return DefaultAddress
+	}
+	return addr
+}
+
+// Replay 按顺序返回预先录制的角速度的 Sensor ，用于测试
+type Replay struct {
+	// 录制的三轴角速度
+	Samples [][3]float32
+
+	lock sync.Mutex
+	next int
+}
+
+var _ Sensor = (*Replay)(nil)
+
+// ErrReplayEnd 录制的角速度已全部返回
+var ErrReplayEnd = errors.New("replay end")
+
+// AngularRate 返回下一个录制的角速度，全部返回后返回 ErrReplayEnd
+func (r *Replay) AngularRate() ([3]float32, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.next >= len(r.Samples) {
+		return [3]float32{}, ErrReplayEnd
+	}
+	s := r.Samples[r.next]
+	r.next++
+	return s, nil
+}
+
+// Remaining 返回尚未返回的样本数
+func (r *Replay) Remaining() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.Samples) - r.next
+}
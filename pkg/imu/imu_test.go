@@ -0,0 +1,170 @@
+package imu
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+)
+
+// fakeBus 模拟 I2C 总线上一个设备的寄存器
+type fakeBus struct {
+	addr uint16
+	regs [256]byte
+}
+
+// Tx 写入时第一个字节为寄存器地址，读取时从该寄存器开始连续读
+func (b *fakeBus) Tx(addr uint16, w, r []byte) error {
+	if addr != b.addr {
+		return errors.New("nack")
+	}
+	reg := w[0]
+	for i, v := range w[1:] {
+		b.regs[int(reg)+i] = v
+	}
+	for i := range r {
+		r[i] = b.regs[int(reg)+i]
+	}
+	return nil
+}
+
+// TestMPU6050 测试 MPU6050
+func TestMPU6050(t *testing.T) {
+	bus := &fakeBus{addr: DefaultAddress}
+	bus.regs[regWhoAmI] = mpu6050WhoAmI
+	bus.regs[mpu6050RegPowerMgmt1] = 0x40 // 上电时处于睡眠
+	s := &MPU6050{Bus: bus}
+	if err := s.Configure(); err != nil {
+		t.Fatalf("configure error: %v", err)
+	}
+	if bus.regs[mpu6050RegPowerMgmt1] != 0x01 || bus.regs[mpu6050RegGyroConfig] != 0x18 {
+		t.Errorf("unexpected config registers: %#x %#x", bus.regs[mpu6050RegPowerMgmt1], bus.regs[mpu6050RegGyroConfig])
+	}
+
+	// X 1640 ， Y -164 ， Z 0
+	copy(bus.regs[mpu6050RegGyroXOutH:], []byte{0x06, 0x68, 0xff, 0x5c, 0, 0})
+	rate, err := s.AngularRate()
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if rate != [3]float32{100, -10, 0} {
+		t.Errorf("unexpected rate: %v", rate)
+	}
+
+	// 地址上是其它设备
+	bus.regs[regWhoAmI] = icm42688WhoAmI
+	if err := s.Configure(); !errors.Is(err, ErrUnknownDevice) {
+		t.Errorf("expected unknown device, got %v", err)
+	}
+}
+
+// TestICM42688 测试 ICM42688
+func TestICM42688(t *testing.T) {
+	bus := &fakeBus{addr: AlternateAddress}
+	bus.regs[regWhoAmI] = icm42688WhoAmI
+	s := &ICM42688{Bus: bus, Address: AlternateAddress}
+	if err := s.Configure(); err != nil {
+		t.Fatalf("configure error: %v", err)
+	}
+	if bus.regs[icm42688RegPowerMgmt0] != 0x0C || bus.regs[icm42688RegGyroConfig0] != 0x06 {
+		t.Errorf("unexpected config registers: %#x %#x", bus.regs[icm42688RegPowerMgmt0], bus.regs[icm42688RegGyroConfig0])
+	}
+	copy(bus.regs[icm42688RegGyroDataX1:], []byte{0, 0, 0, 0, 0x80, 0x00})
+	rate, err := s.AngularRate()
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if math.Abs(float64(rate[AxisZ])+1998.05) > 0.01 {
+		t.Errorf("unexpected z rate: %v", rate[AxisZ])
+	}
+}
+
+// TestCommanded 测试 Commanded
+func TestCommanded(t *testing.T) {
+	// 200 脉冲每圈， 60 rpm 即 360 度/秒、每秒 200 脉冲
+	segments := []golfclubs.Segment{
+		{Forward: false, RPM: 60, Pulses: 20},
+		{Pause: 20 * time.Millisecond},
+		{Forward: true, RPM: 120, Pulses: 40},
+	}
+	got := Commanded(segments, 200, 10*time.Millisecond)
+	expected := []float32{
+		-360, -360, -360, -360, -360, -360, -360, -360, -360, -360,
+		0, 0,
+		720, 720, 720, 720, 720, 720, 720, 720, 720, 720,
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d samples, got %d: %v", len(expected), len(got), got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("sample %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+}
+
+// TestAnalyze 测试 Analyze
+func TestAnalyze(t *testing.T) {
+	commanded := []float32{0, 100, 200, 300, 200, 100, 0}
+	cases := []struct {
+		name     string
+		measured []float32
+		lag      time.Duration
+		slip     bool
+		flex     bool
+	}{
+		{name: "delayed", measured: []float32{0, 0, 0, 100, 200, 300, 200, 100, 0}, lag: 20 * time.Millisecond},
+		{name: "slip", measured: []float32{0, 80, 150, 220, 150, 80, 0}, slip: true},
+		{name: "flex", measured: []float32{0, 100, 200, 380, 150, 120, 0}, flex: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a, err := Analyze(commanded, c.measured, 10*time.Millisecond, DefaultLimits)
+			if err != nil {
+				t.Fatalf("analyze error: %v", err)
+			}
+			if a.Lag != c.lag || a.Slip != c.slip || a.Flex != c.flex {
+				t.Errorf("unexpected analysis: %+v", a)
+			}
+			if a.PeakCommanded != 300 {
+				t.Errorf("expected peak commanded 300, got %v", a.PeakCommanded)
+			}
+		})
+	}
+
+	if _, err := Analyze(commanded, nil, 10*time.Millisecond, DefaultLimits); !errors.Is(err, ErrNoSamples) {
+		t.Errorf("expected no samples, got %v", err)
+	}
+
+	a := Analysis{PeakMeasured: 1800}
+	if v := a.ClubHeadSpeed(0.5); math.Abs(float64(v)-15.708) > 0.001 {
+		t.Errorf("expected club head speed 15.708, got %v", v)
+	}
+}
+
+// TestMonitor 测试以回放数据记录并比较一次运动
+func TestMonitor(t *testing.T) {
+	// 传感器反向安装在 Y 轴
+	replay := &Replay{}
+	for _, v := range []float32{0, 90, 180, 180, 90, 0} {
+		replay.Samples = append(replay.Samples, [3]float32{0, -v, 0})
+	}
+	m := &Monitor{Recorder: Recorder{Sensor: replay, Axis: AxisY, Invert: true, Interval: time.Microsecond}}
+	segments := []golfclubs.Segment{{Forward: true, RPM: 30, Pulses: 1}}
+
+	m.MotionStarted(segments, 200)
+	for deadline := time.Now().Add(time.Second); replay.Remaining() > 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	m.MotionFinished(nil)
+
+	a := m.Last()
+	if a == nil {
+		t.Fatalf("expected analysis")
+	}
+	if a.Samples != 6 || a.PeakMeasured != 180 || a.PeakCommanded != 180 {
+		t.Errorf("unexpected analysis: %+v", a)
+	}
+}
@@ -0,0 +1,149 @@
+package imu
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logging"
+)
+
+// logger IMU 日志
+var logger = logging.New("imu")
+
+const (
+	// DefaultInterval 默认采样间隔
+	DefaultInterval = 5 * time.Millisecond
+	// DefaultMaxSamples 默认最多记录的样本数，按默认间隔约 5 秒
+	DefaultMaxSamples = 1024
+)
+
+// Recorder 在后台以固定间隔记录挥杆转轴的角速度
+type Recorder struct {
+	// 陀螺仪
+	Sensor Sensor
+	// 挥杆转轴对应的陀螺仪轴
+	Axis Axis
+	// 陀螺仪正方向与向前挥杆方向相反
+	Invert bool
+	// 采样间隔
+	// 默认为 DefaultInterval
+	Interval time.Duration
+	// 最多记录的样本数，记满后停止采样
+	// 默认为 DefaultMaxSamples
+	MaxSamples int
+
+	samples []float32
+	err     error
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// Start 开始在后台记录，之前的记录被丢弃
+func (r *Recorder) Start() {
+	r.samples = r.samples[:0]
+	r.err = nil
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run()
+}
+
+// Stop 停止记录，返回记录的角速度（单位：度/秒），向前为正
+//
+// 读取陀螺仪出错时停止采样，返回出错前的记录和该错误。
+func (r *Recorder) Stop() ([]float32, error) {
+	if r.stop == nil {
+		return nil, nil
+	}
+	close(r.stop)
+	<-r.done
+	r.stop = nil
+	return r.samples, r.err
+}
+
+// run 采样直到停止、记满或出错
+func (r *Recorder) run() {
+	defer close(r.done)
+	interval := r.interval()
+	maxSamples := r.MaxSamples
+	if maxSamples <= 0 {
+		maxSamples = DefaultMaxSamples
+	}
+	for len(r.samples) < maxSamples {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+		rate, err := r.Sensor.AngularRate()
+		if err != nil {
+			r.err = err
+			return
+		}
+		v := rate[r.Axis%3]
+		if r.Invert {
+			v = -v
+		}
+		r.samples = append(r.samples, v)
+		time.Sleep(interval)
+	}
+}
+
+// interval 返回采样间隔
+func (r *Recorder) interval() time.Duration {
+	if r.Interval <= 0 {
+		return DefaultInterval
+	}
+	return r.Interval
+}
+
+// Monitor 在每次运动时记录实测角速度并与指令比较， golfclubs.MotionObserver 的实现
+type Monitor struct {
+	// 角速度记录
+	Recorder Recorder
+	// 判断挥杆异常的阈值
+	// 默认为 DefaultLimits
+	Limits *Limits
+
+	lock      sync.Mutex
+	commanded []float32
+	last      *Analysis
+}
+
+var _ golfclubs.MotionObserver = (*Monitor)(nil)
+
+// MotionStarted 计算指令角速度并开始记录
+func (m *Monitor) MotionStarted(segments []golfclubs.Segment, pulsesPerCircle uint32) {
+	m.lock.Lock()
+	m.last = nil
+	m.lock.Unlock()
+	m.commanded = Commanded(segments, pulsesPerCircle, m.Recorder.interval())
+	m.Recorder.Start()
+}
+
+// MotionFinished 停止记录并比较指令与实测角速度
+func (m *Monitor) MotionFinished(_ error) {
+	samples, err := m.Recorder.Stop()
+	if err != nil && !errors.Is(err, ErrReplayEnd) {
+		logger.Errorf("read imu error: %v", err)
+	}
+	limits := DefaultLimits
+	if m.Limits != nil {
+		limits = *m.Limits
+	}
+	a, err := Analyze(m.commanded, samples, m.Recorder.interval(), limits)
+	if err != nil {
+		return
+	}
+	m.lock.Lock()
+	m.last = &a
+	m.lock.Unlock()
+}
+
+// Last 返回最近一次运动的比较结果，没有实测数据时返回 nil
+func (m *Monitor) Last() *Analysis {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.last
+}
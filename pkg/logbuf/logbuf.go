@@ -0,0 +1,194 @@
+package logbuf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSize 默认最多保存的日志条数
+const DefaultSize = 64
+
+// Level 日志级别
+type Level uint8
+
+const (
+	// LevelDebug 调试
+	LevelDebug Level = iota
+	// LevelInfo 信息
+	LevelInfo
+	// LevelWarn 警告
+	LevelWarn
+	// LevelError 错误
+	LevelError
+)
+
+// String 返回级别名
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return "UNKNOWN"
+}
+
+// Short 返回级别名的首字母
+func (l Level) Short() string {
+	return l.String()[:1]
+}
+
+// levelPrefixes 日志消息中表示级别的前缀
+var levelPrefixes = []struct {
+	prefix string
+	level  Level
+}{
+	{prefix: "DEBUG ", level: LevelDebug},
+	{prefix: "INFO ", level: LevelInfo},
+	{prefix: "WARNING ", level: LevelWarn},
+	{prefix: "WARN ", level: LevelWarn},
+	{prefix: "ERROR ", level: LevelError},
+}
+
+// ParseLevel 解析消息开头的级别前缀，返回级别和去掉前缀后的消息
+// 没有前缀的消息视为 LevelInfo
+func ParseLevel(msg string) (Level, string) {
+	for _, p := range levelPrefixes {
+		if strings.HasPrefix(msg, p.prefix) {
+			return p.level, msg[len(p.prefix):]
+		}
+	}
+	return LevelInfo, msg
+}
+
+// Entry 日志条目
+type Entry struct {
+	// 自启动起的时间
+	Time time.Duration
+	// 级别
+	Level Level
+	// 消息
+	Message string
+}
+
+// String 返回适合单行显示的字符串
+func (e Entry) String() string {
+	ms := e.Time.Milliseconds()
+	return fmt.Sprintf("%d.%03d %s %s", ms/1000, ms%1000, e.Level.Short(), e.Message)
+}
+
+// New 创建一个最多保存 size 条日志的 *Buffer
+// 若 output 不为 nil ，写入的日志同时会被格式化后输出到 output
+func New(size int, output io.Writer) *Buffer {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Buffer{
+		start:   time.Now(),
+		entries: make([]Entry, size),
+		output:  output,
+	}
+}
+
+// Buffer 基于内存环形缓冲区的日志输出
+//
+// 可以通过 log.SetOutput 安装为标准库 log 的输出，
+// 每行日志会被记录为一条 Entry ，超出容量时最早的日志被覆盖
+type Buffer struct {
+	lock    sync.Mutex
+	start   time.Time
+	entries []Entry
+	next    int
+	total   uint64
+	partial []byte
+
+	output io.Writer
+}
+
+var _ io.Writer = (*Buffer)(nil)
+
+// Write 写入日志，按行拆分为日志条目
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	var lines []string
+	data := append(b.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		lines = append(lines, strings.TrimRight(string(data[:i]), "\r"))
+		data = data[i+1:]
+	}
+	b.partial = append(b.partial[:0], data...)
+	b.lock.Unlock()
+
+	for _, line := range lines {
+		level, msg := ParseLevel(line)
+		b.Add(level, msg)
+	}
+	return len(p), nil
+}
+
+// Add 添加一条日志，时间为自创建 Buffer 起的时间
+func (b *Buffer) Add(level Level, msg string) {
+	b.Append(Entry{
+		Time:    time.Since(b.start),
+		Level:   level,
+		Message: msg,
+	})
+}
+
+// Append 添加一条已记录时间的日志
+func (b *Buffer) Append(e Entry) {
+	b.lock.Lock()
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	b.total++
+	b.lock.Unlock()
+
+	if b.output != nil {
+		_, _ = fmt.Fprint(b.output, e.String()+"\r\n")
+	}
+}
+
+// Entries 返回当前保存的日志，按时间从早到晚排列
+func (b *Buffer) Entries() []Entry {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	n := len(b.entries)
+	if b.total < uint64(n) {
+		n = int(b.total)
+	}
+	ret := make([]Entry, 0, n)
+	for i := len(b.entries) - n; i < len(b.entries); i++ {
+		ret = append(ret, b.entries[(b.next+i)%len(b.entries)])
+	}
+	return ret
+}
+
+// Lines 返回当前保存的日志的字符串形式，按时间从早到晚排列
+func (b *Buffer) Lines() []string {
+	entries := b.Entries()
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = e.String()
+	}
+	return lines
+}
+
+// Total 返回自创建以来写入的日志总数
+func (b *Buffer) Total() uint64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.total
+}
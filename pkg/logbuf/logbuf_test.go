@@ -0,0 +1,116 @@
+package logbuf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseLevel 测试 ParseLevel
+func TestParseLevel(t *testing.T) {
+	cases := []struct {
+		msg   string
+		level Level
+		rest  string
+	}{
+		{msg: "DEBUG tick", level: LevelDebug, rest: "tick"},
+		{msg: "INFO ready", level: LevelInfo, rest: "ready"},
+		{msg: "WARNING low", level: LevelWarn, rest: "low"},
+		{msg: "WARN low", level: LevelWarn, rest: "low"},
+		{msg: "ERROR open failed", level: LevelError, rest: "open failed"},
+		{msg: "plain message", level: LevelInfo, rest: "plain message"},
+		// 前缀必须后跟空格
+		{msg: "ERRORS", level: LevelInfo, rest: "ERRORS"},
+		{msg: "", level: LevelInfo, rest: ""},
+	}
+	for _, c := range cases {
+		level, rest := ParseLevel(c.msg)
+		if level != c.level || rest != c.rest {
+			t.Errorf("ParseLevel(%q): expected %s %q, got %s %q", c.msg, c.level, c.rest, level, rest)
+		}
+	}
+}
+
+// TestEntry_String 测试 Entry.String
+func TestEntry_String(t *testing.T) {
+	e := Entry{Time: 12345 * time.Millisecond, Level: LevelWarn, Message: "low"}
+	if s := e.String(); s != "12.345 W low" {
+		t.Errorf("unexpected string: %q", s)
+	}
+}
+
+// messages 返回 b 中保存的日志的级别首字母和消息
+func messages(b *Buffer) []string {
+	var ret []string
+	for _, e := range b.Entries() {
+		ret = append(ret, e.Level.Short()+" "+e.Message)
+	}
+	return ret
+}
+
+// TestBuffer_Entries 测试 Buffer 超出容量时覆盖最早的日志，并按时间从早到晚返回
+func TestBuffer_Entries(t *testing.T) {
+	b := New(3, nil)
+	if entries := b.Entries(); len(entries) != 0 {
+		t.Errorf("expected no entries, got %v", entries)
+	}
+
+	b.Add(LevelInfo, "a")
+	b.Add(LevelInfo, "b")
+	if got := strings.Join(messages(b), ","); got != "I a,I b" {
+		t.Errorf("unexpected entries: %s", got)
+	}
+
+	// 环绕多于一圈
+	for _, msg := range []string{"c", "d", "e", "f"} {
+		b.Add(LevelWarn, msg)
+	}
+	if got := strings.Join(messages(b), ","); got != "W d,W e,W f" {
+		t.Errorf("unexpected entries after wraparound: %s", got)
+	}
+	if total := b.Total(); total != 6 {
+		t.Errorf("expected total 6, got %d", total)
+	}
+
+	// Lines 与 Entries 顺序一致，时间不减
+	entries := b.Entries()
+	lines := b.Lines()
+	if len(lines) != len(entries) {
+		t.Fatalf("expected %d lines, got %q", len(entries), lines)
+	}
+	for i, e := range entries {
+		if lines[i] != e.String() {
+			t.Errorf("line %d: expected %q, got %q", i, e.String(), lines[i])
+		}
+		if i > 0 && e.Time < entries[i-1].Time {
+			t.Errorf("entry %d: time %s before previous %s", i, e.Time, entries[i-1].Time)
+		}
+	}
+
+	if b := New(0, nil); len(b.entries) != DefaultSize {
+		t.Errorf("expected default size %d, got %d", DefaultSize, len(b.entries))
+	}
+}
+
+// TestBuffer_Write 测试 Buffer 按行拆分写入并解析级别
+func TestBuffer_Write(t *testing.T) {
+	out := &bytes.Buffer{}
+	b := New(4, out)
+
+	// 不完整的行等到换行后才记录
+	_, _ = b.Write([]byte("ERROR open "))
+	if b.Total() != 0 {
+		t.Fatalf("expected partial line to be held")
+	}
+	_, _ = b.Write([]byte("failed\r\nWARNING low\nready\n"))
+
+	if got := strings.Join(messages(b), ","); got != "E open failed,W low,I ready" {
+		t.Errorf("unexpected entries: %s", got)
+	}
+
+	// 同时输出格式化后的日志
+	if lines := bytes.Count(out.Bytes(), []byte("\r\n")); lines != 3 {
+		t.Errorf("expected 3 output lines, got %q", out.String())
+	}
+}
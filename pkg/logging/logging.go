@@ -0,0 +1,202 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logbuf"
+)
+
+// Level 日志级别，与 logbuf 一致
+type Level = logbuf.Level
+
+const (
+	// LevelDebug 调试
+	LevelDebug = logbuf.LevelDebug
+	// LevelInfo 信息
+	LevelInfo = logbuf.LevelInfo
+	// LevelWarn 警告
+	LevelWarn = logbuf.LevelWarn
+	// LevelError 错误
+	LevelError = logbuf.LevelError
+)
+
+// exit 输出 Fatalf 日志后退出程序，测试时替换
+var exit = os.Exit
+
+// Entry 日志条目
+type Entry struct {
+	// 自启动起的时间
+	Time time.Duration
+	// 级别
+	Level Level
+	// 子系统标签
+	Tag string
+	// 消息
+	Message string
+}
+
+// Buffered 返回用于保存到 logbuf.Buffer 的条目，标签作为消息前缀
+func (e Entry) Buffered() logbuf.Entry {
+	msg := e.Message
+	if e.Tag != "" {
+		msg = e.Tag + ": " + msg
+	}
+	return logbuf.Entry{Time: e.Time, Level: e.Level, Message: msg}
+}
+
+// String 返回 "<秒>.<毫秒> <级别首字母> <标签>: <消息>"
+func (e Entry) String() string {
+	return e.Buffered().String()
+}
+
+// Sink 日志输出
+type Sink interface {
+	// Log 输出一条日志，可能在多个协程中调用
+	Log(e Entry)
+}
+
+// NewDispatcher 创建 *Dispatcher ，以当前时间为启动时间
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{start: time.Now()}
+}
+
+// Dispatcher 将日志条目分发到各个输出
+type Dispatcher struct {
+	lock  sync.Mutex
+	start time.Time
+	sinks []Sink
+}
+
+// AddSink 添加输出
+func (d *Dispatcher) AddSink(sinks ...Sink) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.sinks = append(d.sinks, sinks...)
+}
+
+// Log 输出一条日志，低于 MinLevel 的日志被丢弃
+func (d *Dispatcher) Log(level Level, tag, msg string) {
+	if level < MinLevel {
+		return
+	}
+	d.lock.Lock()
+	e := Entry{
+		Time:    d.Uptime(),
+		Level:   level,
+		Tag:     tag,
+		Message: msg,
+	}
+	sinks := d.sinks
+	d.lock.Unlock()
+
+	for _, s := range sinks {
+		s.Log(e)
+	}
+}
+
+// Uptime 返回自启动起的时间
+func (d *Dispatcher) Uptime() time.Duration {
+	return time.Since(d.start)
+}
+
+// New 创建标签为 tag 的 *Logger
+func (d *Dispatcher) New(tag string) *Logger {
+	return &Logger{tag: tag, dispatcher: d}
+}
+
+// Writer 返回可以通过 log.SetOutput 安装为标准库 log 输出的 io.Writer
+//
+// 每次写入的每行作为一条标签为 tag 的日志，级别由消息开头的前缀决定，见 logbuf.ParseLevel
+func (d *Dispatcher) Writer(tag string) io.Writer {
+	return &writer{tag: tag, dispatcher: d}
+}
+
+// writer 见 Dispatcher.Writer
+type writer struct {
+	tag        string
+	dispatcher *Dispatcher
+}
+
+// Write 按行写入日志
+func (w *writer) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\r\n"), "\n") {
+		level, msg := logbuf.ParseLevel(strings.TrimRight(line, "\r"))
+		w.dispatcher.Log(level, w.tag, msg)
+	}
+	return len(p), nil
+}
+
+// Logger 带子系统标签的日志记录器
+//
+// 低于编译期 MinLevel 的方法调用会被编译器优化掉，不产生格式化开销
+type Logger struct {
+	tag        string
+	dispatcher *Dispatcher
+}
+
+// Tag 返回子系统标签
+func (l *Logger) Tag() string {
+	return l.tag
+}
+
+// Debugf 输出调试日志
+func (l *Logger) Debugf(format string, args ...any) {
+	if LevelDebug >= MinLevel {
+		l.dispatcher.Log(LevelDebug, l.tag, fmt.Sprintf(format, args...))
+	}
+}
+
+// Infof 输出信息日志
+func (l *Logger) Infof(format string, args ...any) {
+	if LevelInfo >= MinLevel {
+		l.dispatcher.Log(LevelInfo, l.tag, fmt.Sprintf(format, args...))
+	}
+}
+
+// Warnf 输出警告日志
+func (l *Logger) Warnf(format string, args ...any) {
+	if LevelWarn >= MinLevel {
+		l.dispatcher.Log(LevelWarn, l.tag, fmt.Sprintf(format, args...))
+	}
+}
+
+// Errorf 输出错误日志
+func (l *Logger) Errorf(format string, args ...any) {
+	if LevelError >= MinLevel {
+		l.dispatcher.Log(LevelError, l.tag, fmt.Sprintf(format, args...))
+	}
+}
+
+// Fatalf 输出错误日志后退出程序
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.dispatcher.Log(LevelError, l.tag, fmt.Sprintf(format, args...))
+	exit(1)
+}
+
+// std 默认的日志分发器
+var std = NewDispatcher()
+
+// AddSink 添加默认的日志分发器的输出
+func AddSink(sinks ...Sink) {
+	std.AddSink(sinks...)
+}
+
+// Uptime 返回默认的日志分发器记录的自启动起的时间
+func Uptime() time.Duration {
+	return std.Uptime()
+}
+
+// New 创建使用默认的日志分发器、标签为 tag 的 *Logger
+func New(tag string) *Logger {
+	return std.New(tag)
+}
+
+// Writer 返回写入默认的日志分发器的 io.Writer ，见 Dispatcher.Writer
+func Writer(tag string) io.Writer {
+	return std.Writer(tag)
+}
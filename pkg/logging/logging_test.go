@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logbuf"
+)
+
+// recordSink 记录日志条目的输出
+type recordSink struct {
+	entries []Entry
+}
+
+// Log 记录一条日志
+func (s *recordSink) Log(e Entry) {
+	s.entries = append(s.entries, e)
+}
+
+// TestLogger 测试 Logger 按级别和标签分发日志
+func TestLogger(t *testing.T) {
+	d := NewDispatcher()
+	sink := &recordSink{}
+	d.AddSink(sink)
+	l := d.New("motor")
+
+	l.Debugf("debug %d", 1)
+	l.Infof("info %d", 2)
+	l.Warnf("warn %d", 3)
+	l.Errorf("error %d", 4)
+
+	// 低于编译期 MinLevel 的日志被丢弃
+	var expected []Entry
+	for _, e := range []Entry{
+		{Level: LevelDebug, Tag: "motor", Message: "debug 1"},
+		{Level: LevelInfo, Tag: "motor", Message: "info 2"},
+		{Level: LevelWarn, Tag: "motor", Message: "warn 3"},
+		{Level: LevelError, Tag: "motor", Message: "error 4"},
+	} {
+		if e.Level >= MinLevel {
+			expected = append(expected, e)
+		}
+	}
+	if len(sink.entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %v", len(expected), len(sink.entries), sink.entries)
+	}
+	for i, e := range sink.entries {
+		e.Time = 0
+		if e != expected[i] {
+			t.Errorf("entry %d: expected %+v, got %+v", i, expected[i], e)
+		}
+	}
+}
+
+// TestLoggerFatalf 测试 Logger.Fatalf 输出后退出
+func TestLoggerFatalf(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	d := NewDispatcher()
+	sink := &recordSink{}
+	d.AddSink(sink)
+	d.New("main").Fatalf("boom")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if len(sink.entries) != 1 || sink.entries[0].Level != LevelError || sink.entries[0].Message != "boom" {
+		t.Errorf("unexpected entries: %v", sink.entries)
+	}
+}
+
+// TestEntryString 测试 Entry.String
+func TestEntryString(t *testing.T) {
+	e := Entry{Time: 12345 * time.Millisecond, Level: LevelWarn, Tag: "supply", Message: "low"}
+	if s := e.String(); s != "12.345 W supply: low" {
+		t.Errorf("unexpected string: %q", s)
+	}
+	e.Tag = ""
+	if s := e.String(); s != "12.345 W low" {
+		t.Errorf("unexpected string without tag: %q", s)
+	}
+}
+
+// TestWriter 测试通过 Dispatcher.Writer 转发标准库 log 的输出
+func TestWriter(t *testing.T) {
+	d := NewDispatcher()
+	sink := &recordSink{}
+	d.AddSink(sink)
+	l := log.New(d.Writer("std"), "", 0)
+	l.Printf("ERROR open failed")
+	l.Printf("WARNING low\nsecond line")
+
+	var expected []Entry
+	for _, e := range []Entry{
+		{Level: LevelError, Tag: "std", Message: "open failed"},
+		{Level: LevelWarn, Tag: "std", Message: "low"},
+		{Level: LevelInfo, Tag: "std", Message: "second line"},
+	} {
+		if e.Level >= MinLevel {
+			expected = append(expected, e)
+		}
+	}
+	if len(sink.entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %v", len(expected), len(sink.entries), sink.entries)
+	}
+	for i, e := range sink.entries {
+		e.Time = 0
+		if e != expected[i] {
+			t.Errorf("entry %d: expected %+v, got %+v", i, expected[i], e)
+		}
+	}
+}
+
+// TestWriterSink 测试 WriterSink 按级别过滤和启用开关
+func TestWriterSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	s := NewWriterSink(buf, LevelWarn)
+	s.Log(Entry{Time: time.Second, Level: LevelInfo, Tag: "a", Message: "dropped"})
+	s.Log(Entry{Time: time.Second, Level: LevelError, Tag: "a", Message: "shown"})
+	s.SetEnabled(false)
+	if s.Enabled() {
+		t.Errorf("expected disabled")
+	}
+	s.Log(Entry{Time: time.Second, Level: LevelError, Tag: "a", Message: "disabled"})
+	s.SetEnabled(true)
+	s.Newline = "\n"
+	s.Log(Entry{Time: 2 * time.Second, Level: LevelWarn, Tag: "b", Message: "again"})
+
+	expected := "1.000 E a: shown\r\n2.000 W b: again\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+// TestBufferSink 测试 BufferSink 保存日志时保留时间和标签
+func TestBufferSink(t *testing.T) {
+	buf := logbuf.New(4, nil)
+	s := BufferSink{Buffer: buf}
+	s.Log(Entry{Time: 1500 * time.Millisecond, Level: LevelInfo, Tag: "menu", Message: "ready"})
+
+	lines := buf.Lines()
+	if len(lines) != 1 || !strings.HasSuffix(lines[0], "I menu: ready") || !strings.HasPrefix(lines[0], "1.500 ") {
+		t.Errorf("unexpected lines: %q", lines)
+	}
+}
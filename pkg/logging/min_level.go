@@ -0,0 +1,8 @@
+//go:build !log_debug && !log_warn && !log_error
+
+package logging
+
+// MinLevel 编译期的最低日志级别，低于该级别的日志不输出
+//
+// 可以通过构建标签 log_debug 、 log_warn 或 log_error 修改，如 tinygo build -tags log_warn
+const MinLevel = LevelInfo
@@ -0,0 +1,6 @@
+//go:build log_debug
+
+package logging
+
+// MinLevel 编译期的最低日志级别，低于该级别的日志不输出
+const MinLevel = LevelDebug
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"io"
+	"sync"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/logbuf"
+)
+
+// DefaultNewline 默认的 WriterSink 行尾
+const DefaultNewline = "\r\n"
+
+// NewWriterSink 创建输出到 w 的 *WriterSink ，初始启用
+func NewWriterSink(w io.Writer, level Level) *WriterSink {
+	return &WriterSink{Writer: w, Level: level}
+}
+
+// WriterSink 将日志格式化后逐行写入 io.Writer ，如串口或 textscreen 文本屏幕
+//
+// 可以随时禁用，如串口菜单显示期间不输出日志到串口，避免与菜单输出混在一起
+type WriterSink struct {
+	// 日志输出
+	Writer io.Writer
+	// 最低输出级别
+	Level Level
+	// 行尾，默认为 DefaultNewline
+	Newline string
+
+	lock     sync.Mutex
+	disabled bool
+}
+
+var _ Sink = (*WriterSink)(nil)
+
+// Log 输出一条日志
+func (s *WriterSink) Log(e Entry) {
+	if e.Level < s.Level {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.disabled {
+		return
+	}
+	newline := s.Newline
+	if newline == "" {
+		newline = DefaultNewline
+	}
+	_, _ = io.WriteString(s.Writer, e.String()+newline)
+}
+
+// SetEnabled 设置是否启用
+func (s *WriterSink) SetEnabled(enabled bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.disabled = !enabled
+}
+
+// Enabled 返回是否启用
+func (s *WriterSink) Enabled() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return !s.disabled
+}
+
+// BufferSink 将日志保存到内存环形缓冲区，以便在菜单中查看
+type BufferSink struct {
+	Buffer *logbuf.Buffer
+}
+
+var _ Sink = BufferSink{}
+
+// Log 输出一条日志
+func (s BufferSink) Log(e Entry) {
+	s.Buffer.Append(e.Buffered())
+}
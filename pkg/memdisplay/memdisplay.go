@@ -0,0 +1,100 @@
+package memdisplay
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+
+	"tinygo.org/x/drivers"
+)
+
+// New 创建一个宽 width 高 height 的 *Display
+func New(width, height int16) *Display {
+	return &Display{
+		width:  width,
+		height: height,
+		pixels: make([]color.RGBA, int(width)*int(height)),
+	}
+}
+
+// Display 记录像素的内存显示器， drivers.Displayer 的实现
+//
+// 主要用于在主机上测试渲染结果
+type Display struct {
+	width  int16
+	height int16
+	pixels []color.RGBA
+
+	flushes int
+}
+
+var _ drivers.Displayer = (*Display)(nil)
+
+// Size 返回显示器尺寸
+func (d *Display) Size() (x, y int16) {
+	return d.width, d.height
+}
+
+// SetPixel 设置像素颜色，超出显示范围的像素被忽略
+func (d *Display) SetPixel(x, y int16, c color.RGBA) {
+	if x < 0 || y < 0 || x >= d.width || y >= d.height {
+		return
+	}
+	d.pixels[int(y)*int(d.width)+int(x)] = c
+}
+
+// Display 记录一次刷新
+func (d *Display) Display() error {
+	d.flushes++
+	return nil
+}
+
+// Pixel 返回像素颜色，超出显示范围时返回零值
+func (d *Display) Pixel(x, y int16) color.RGBA {
+	if x < 0 || y < 0 || x >= d.width || y >= d.height {
+		return color.RGBA{}
+	}
+	return d.pixels[int(y)*int(d.width)+int(x)]
+}
+
+// Flushes 返回 Display 被调用的次数
+func (d *Display) Flushes() int {
+	return d.flushes
+}
+
+// Image 返回当前显示内容的图像
+func (d *Display) Image() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, int(d.width), int(d.height)))
+	for y := int16(0); y < d.height; y++ {
+		for x := int16(0); x < d.width; x++ {
+			img.SetRGBA(int(x), int(y), d.Pixel(x, y))
+		}
+	}
+	return img
+}
+
+// WritePNG 将当前显示内容以 PNG 格式写入 w
+func (d *Display) WritePNG(w io.Writer) error {
+	return png.Encode(w, d.Image())
+}
+
+// ASCII 返回当前显示内容的字符画
+//
+// 亮度过半的像素显示为 '#' ，其余显示为 '.'
+func (d *Display) ASCII() string {
+	var b strings.Builder
+	for y := int16(0); y < d.height; y++ {
+		for x := int16(0); x < d.width; x++ {
+			c := d.Pixel(x, y)
+			if uint32(c.R)+uint32(c.G)+uint32(c.B) > 3*0xff/2 {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+// Package memdisplaytest 提供用内存显示器做黄金文件测试的工具
+package memdisplaytest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/memdisplay"
+)
+
+// UpdateGoldenEnv 设置该环境变量为非空时， CheckGolden 用实际结果更新黄金文件
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// CheckGolden 比较 d 当前显示内容的字符画与黄金文件 path 的内容
+//
+// 不一致时测试失败，并将实际显示内容保存为 path 加 .actual.png 后缀的 PNG 文件以便查看
+func CheckGolden(t testing.TB, d *memdisplay.Display, path string) {
+	t.Helper()
+
+	got := d.ASCII()
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create golden dir error: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("write golden file error: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file error: %v (set %s=1 to create it)", err, UpdateGoldenEnv)
+	}
+	if got == string(want) {
+		return
+	}
+
+	pngPath := path + ".actual.png"
+	f, err := os.Create(pngPath)
+	if err == nil {
+		err = d.WritePNG(f)
+		_ = f.Close()
+	}
+	if err != nil {
+		t.Errorf("save actual image error: %v", err)
+	}
+	t.Errorf("display does not match %s (actual image: %s)\nwant:\n%s\ngot:\n%s", path, pngPath, want, got)
+}
@@ -0,0 +1,91 @@
+package menu
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CommandPrefix 串口输入中以该字符开头的行作为命令执行
+const CommandPrefix = ':'
+
+// ErrUsage 命令参数错误
+var ErrUsage = errors.New("invalid arguments")
+
+// Command 串口命令
+type Command struct {
+	// 命令名
+	Name string
+	// 参数说明
+	Usage string
+	// 为 true 时不按空白拆分参数， args 只含命令名之后去除首尾空白的原始内容，用于接收 JSON 等含空白的参数
+	Raw bool
+	// 执行命令，输出写入 w
+	Run func(args []string, w io.Writer) error
+}
+
+// Commands 串口命令集
+type Commands struct {
+	commands map[string]*Command
+}
+
+// Register 注册命令，同名命令会被覆盖
+func (c *Commands) Register(commands ...*Command) {
+	if c.commands == nil {
+		c.commands = map[string]*Command{}
+	}
+	for _, cmd := range commands {
+		c.commands[cmd.Name] = cmd
+	}
+}
+
+// Exec 执行一行命令
+//
+// 成功时输出 "OK" ，失败时输出 "ERROR <错误>" ，以便主机端脚本判断结果。
+func (c *Commands) Exec(line string, w io.Writer) {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return
+	}
+	if args[0] == "help" {
+		c.help(w)
+		_, _ = fmt.Fprint(w, "OK\r\n")
+		return
+	}
+
+	cmd, ok := c.commands[args[0]]
+	if !ok {
+		_, _ = fmt.Fprintf(w, "ERROR unknown command %q\r\n", args[0])
+		return
+	}
+	args = args[1:]
+	if cmd.Raw {
+		args = nil
+		if rest := strings.TrimSpace(strings.TrimSpace(line)[len(cmd.Name):]); rest != "" {
+			args = []string{rest}
+		}
+	}
+	if err := cmd.Run(args, w); err != nil {
+		if errors.Is(err, ErrUsage) {
+			_, _ = fmt.Fprintf(w, "ERROR %v, usage: %s %s\r\n", err, cmd.Name, cmd.Usage)
+		} else {
+			_, _ = fmt.Fprintf(w, "ERROR %v\r\n", err)
+		}
+		return
+	}
+	_, _ = fmt.Fprint(w, "OK\r\n")
+}
+
+// help 输出所有命令的用法
+func (c *Commands) help(w io.Writer) {
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "%s %s\r\n", name, c.commands[name].Usage)
+	}
+}
@@ -0,0 +1,66 @@
+package menu
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// TestCommands_Exec 测试 Commands.Exec
+func TestCommands_Exec(t *testing.T) {
+	cmds := &Commands{}
+	cmds.Register(
+		&Command{
+			Name:  "echo",
+			Usage: "<words>...",
+			Run: func(args []string, w io.Writer) error {
+				if len(args) == 0 {
+					return ErrUsage
+				}
+				_, _ = fmt.Fprintf(w, "%v\r\n", args)
+				return nil
+			},
+		},
+		&Command{
+			Name:  "raw",
+			Usage: "<text>",
+			Raw:   true,
+			Run: func(args []string, w io.Writer) error {
+				if len(args) == 0 {
+					return ErrUsage
+				}
+				_, _ = fmt.Fprintf(w, "%q\r\n", args)
+				return nil
+			},
+		},
+		&Command{
+			Name: "fail",
+			Run: func(_ []string, _ io.Writer) error {
+				return errors.New("boom")
+			},
+		},
+	)
+
+	cases := []struct {
+		line     string
+		expected string
+	}{
+		{line: "", expected: ""},
+		{line: "echo a  b", expected: "[a b]\r\nOK\r\n"},
+		{line: "echo", expected: "ERROR invalid arguments, usage: echo <words>...\r\n"},
+		{line: "  raw  {\"a\":  \"b  c\"} ", expected: "[\"{\\\"a\\\":  \\\"b  c\\\"}\"]\r\nOK\r\n"},
+		{line: "raw  ", expected: "ERROR invalid arguments, usage: raw <text>\r\n"},
+		{line: "fail", expected: "ERROR boom\r\n"},
+		{line: "nope", expected: "ERROR unknown command \"nope\"\r\n"},
+		{line: "help", expected: "echo <words>...\r\nfail \r\nraw <text>\r\nOK\r\n"},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		cmds.Exec(c.line, buf)
+		if buf.String() != c.expected {
+			t.Errorf("line %q: expected %q, got %q", c.line, c.expected, buf.String())
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package menu
+
+import "time"
+
+// Edge 触发边沿
+type Edge uint8
+
+const (
+	// EdgeFalling 下降沿触发，适用于接地的常开开关（配合上拉）
+	EdgeFalling Edge = iota
+	// EdgeRising 上升沿触发
+	EdgeRising
+	// EdgeBoth 上升沿和下降沿均触发
+	EdgeBoth
+)
+
+// String 返回边沿名
+func (e Edge) String() string {
+	switch e {
+	case EdgeFalling:
+		return "falling"
+	case EdgeRising:
+		return "rising"
+	case EdgeBoth:
+		return "both"
+	}
+	return "unknown"
+}
+
+// Debouncer 数字输入去抖和边沿检测
+//
+// 电平变化后保持 Debounce 才认为有效，有效电平变化符合 Edge 时触发，触发后 Lockout 内不再触发。
+type Debouncer struct {
+	// 触发边沿
+	Edge Edge
+	// 去抖时长，电平保持该时长后才认为有效
+	Debounce time.Duration
+	// 锁定时长，触发后该时长内忽略输入
+	Lockout time.Duration
+
+	initialized bool
+	stable      bool
+	pending     bool
+	changed     time.Time
+	fired       time.Time
+	hasFired    bool
+}
+
+// Update 输入 now 时刻的电平 level ，返回是否触发
+func (d *Debouncer) Update(level bool, now time.Time) bool {
+	if !d.initialized {
+		// 以首次读到的电平为初始状态，不触发
+		d.initialized = true
+		d.stable = level
+		d.pending = level
+		return false
+	}
+	if level != d.pending {
+		d.pending = level
+		d.changed = now
+	}
+	if d.pending == d.stable || now.Sub(d.changed) < d.Debounce {
+		return false
+	}
+
+	d.stable = d.pending
+	switch {
+	case d.Edge == EdgeRising && !d.stable:
+		return false
+	case d.Edge == EdgeFalling && d.stable:
+		return false
+	case d.hasFired && now.Sub(d.fired) < d.Lockout:
+		return false
+	}
+	d.fired = now
+	d.hasFired = true
+	return true
+}
+
+// Pending 外部触发输入待输入菜单的操作
+//
+// 菜单协程正在显示、刷新或执行其它操作时无法立即接收，触发被保留到下次采样时重试；
+// 只有 Busy 返回 true （如球杆正在运动）时丢弃，以免动作结束后意外地再执行一次。
+// 只能在采样协程中使用。
+type Pending struct {
+	// 返回是否正在执行不应排队的动作，为 nil 时不丢弃
+	Busy func() bool
+
+	pending bool
+}
+
+// Trigger 记录一次触发
+func (p *Pending) Trigger() {
+	p.pending = true
+}
+
+// Deliver 尝试将 op 输入到 ch ，每次采样时调用，返回是否已输入
+func (p *Pending) Deliver(ch chan<- Operation, op Operation) bool {
+	if !p.pending {
+		return false
+	}
+	if p.Busy != nil && p.Busy() {
+		p.pending = false
+		return false
+	}
+	select {
+	case ch <- op:
+		p.pending = false
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,118 @@
+package menu
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDebouncer 测试 Debouncer
+func TestDebouncer(t *testing.T) {
+	type input struct {
+		ms    int
+		level bool
+		fire  bool
+	}
+	cases := []struct {
+		name   string
+		d      Debouncer
+		inputs []input
+	}{
+		{
+			name: "falling-with-bounce",
+			d:    Debouncer{Edge: EdgeFalling, Debounce: 10 * time.Millisecond},
+			inputs: []input{
+				{ms: 0, level: true},
+				{ms: 1, level: false},
+				{ms: 2, level: true}, // 抖动
+				{ms: 3, level: false},
+				{ms: 12, level: false},
+				{ms: 13, level: false, fire: true},
+				{ms: 14, level: false},
+				{ms: 20, level: true},
+				{ms: 40, level: true}, // 上升沿不触发
+			},
+		},
+		{
+			name: "rising",
+			d:    Debouncer{Edge: EdgeRising},
+			inputs: []input{
+				{ms: 0, level: false},
+				{ms: 1, level: true, fire: true},
+				{ms: 2, level: false},
+				{ms: 3, level: true, fire: true},
+			},
+		},
+		{
+			name: "both-with-lockout",
+			d:    Debouncer{Edge: EdgeBoth, Lockout: 100 * time.Millisecond},
+			inputs: []input{
+				{ms: 0, level: true},
+				{ms: 10, level: false, fire: true},
+				{ms: 50, level: true}, // 锁定中
+				{ms: 120, level: false, fire: true},
+			},
+		},
+		{
+			name: "initial-level",
+			d:    Debouncer{Edge: EdgeFalling},
+			inputs: []input{
+				{ms: 0, level: false}, // 上电时已按下不触发
+				{ms: 10, level: false},
+				{ms: 20, level: true},
+				{ms: 30, level: false, fire: true},
+			},
+		},
+	}
+	start := time.Unix(0, 0)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := c.d
+			for _, in := range c.inputs {
+				now := start.Add(time.Duration(in.ms) * time.Millisecond)
+				if fire := d.Update(in.level, now); fire != in.fire {
+					t.Errorf("at %dms level %t: expected fire %t, got %t", in.ms, in.level, in.fire, fire)
+				}
+			}
+		})
+	}
+}
+
+// TestPending 测试 Pending 在菜单忙时保留触发，运动时丢弃
+func TestPending(t *testing.T) {
+	ch := make(chan Operation, 1)
+	busy := false
+	p := &Pending{Busy: func() bool { return busy }}
+	op := Operation{Enter: &Enter{}}
+
+	if p.Deliver(ch, op) {
+		t.Fatalf("unexpected delivery without trigger")
+	}
+
+	// 菜单协程未接收时保留
+	ch <- Operation{}
+	p.Trigger()
+	if p.Deliver(ch, op) {
+		t.Fatalf("unexpected delivery while menu busy")
+	}
+	<-ch
+	if !p.Deliver(ch, op) {
+		t.Fatalf("expected delivery after menu is free")
+	}
+	if got := <-ch; got.Enter == nil {
+		t.Errorf("unexpected operation: %+v", got)
+	}
+	if p.Deliver(ch, op) {
+		t.Errorf("expected trigger to be delivered once")
+	}
+
+	// 运动时丢弃
+	p.Trigger()
+	busy = true
+	if p.Deliver(ch, op) {
+		t.Fatalf("unexpected delivery while moving")
+	}
+	busy = false
+	if p.Deliver(ch, op) {
+		t.Errorf("expected trigger dropped while moving")
+	}
+}
@@ -17,6 +17,10 @@ type GraphicsDisplay struct {
 	ForegroundColor color.RGBA
 	// 背景色
 	BackgroundColor color.RGBA
+	// 选中行前景色，为零值时使用 BackgroundColor
+	SelectedForegroundColor color.RGBA
+	// 选中行背景色，为零值时使用 ForegroundColor
+	SelectedBackgroundColor color.RGBA
 	// 显示位置左上角 X 坐标
 	X int16
 	// 显示位置左上角 Y 坐标
@@ -35,6 +39,55 @@ type GraphicsDisplay struct {
 
 var _ UIOutput = (*GraphicsDisplay)(nil)
 
+// Theme 配色方案
+type Theme struct {
+	// 前景色
+	Foreground color.RGBA
+	// 背景色
+	Background color.RGBA
+	// 选中行前景色
+	SelectedForeground color.RGBA
+	// 选中行背景色
+	SelectedBackground color.RGBA
+}
+
+var (
+	// ThemeMonochrome 单色屏配色
+	ThemeMonochrome = Theme{
+		Foreground:         color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		Background:         color.RGBA{A: 255},
+		SelectedForeground: color.RGBA{A: 255},
+		SelectedBackground: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+	}
+	// ThemeGreen 彩色屏配色，深色背景绿色高亮
+	ThemeGreen = Theme{
+		Foreground:         color.RGBA{R: 220, G: 220, B: 220, A: 255},
+		Background:         color.RGBA{R: 16, G: 24, B: 16, A: 255},
+		SelectedForeground: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		SelectedBackground: color.RGBA{R: 0, G: 128, B: 64, A: 255},
+	}
+	// ThemeBlue 彩色屏配色，深色背景蓝色高亮
+	ThemeBlue = Theme{
+		Foreground:         color.RGBA{R: 220, G: 220, B: 220, A: 255},
+		Background:         color.RGBA{R: 8, G: 8, B: 24, A: 255},
+		SelectedForeground: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		SelectedBackground: color.RGBA{R: 0, G: 80, B: 200, A: 255},
+	}
+)
+
+// SetTheme 设置配色
+func (g *GraphicsDisplay) SetTheme(theme Theme) {
+	g.ForegroundColor = theme.Foreground
+	g.BackgroundColor = theme.Background
+	g.SelectedForegroundColor = theme.SelectedForeground
+	g.SelectedBackgroundColor = theme.SelectedBackground
+}
+
+// rectFiller 支持直接填充矩形的显示器，如 TFT
+type rectFiller interface {
+	FillRectangle(x, y, width, height int16, c color.RGBA) error
+}
+
 // Show 显示菜单当前状态
 func (g *GraphicsDisplay) Show(m *Menu) {
 	// 计算显示区域
@@ -53,36 +106,31 @@ func (g *GraphicsDisplay) Show(m *Menu) {
 	names, selected := m.ItemNames()
 	if len(names) == 0 {
 		// 清空屏幕
-		for y := int16(0); y < height; y++ {
-			for x := int16(0); x < width; x++ {
-				g.Display.SetPixel(x+g.X, y+g.Y, g.BackgroundColor)
-			}
-		}
+		g.fill(0, 0, width, height, g.BackgroundColor)
 		_ = g.Display.Display()
 		return
 	}
 
 	// 中间显示选中行
-	for y := midLineY; y < midLineY+lineHeight; y++ {
-		for x := int16(0); x < width; x++ {
-			g.Display.SetPixel(x+g.X, y+g.Y, g.ForegroundColor)
-		}
+	selectedFG, selectedBG := g.BackgroundColor, g.ForegroundColor
+	if g.SelectedForegroundColor != (color.RGBA{}) {
+		selectedFG = g.SelectedForegroundColor
 	}
+	if g.SelectedBackgroundColor != (color.RGBA{}) {
+		selectedBG = g.SelectedBackgroundColor
+	}
+	g.fill(0, midLineY, width, lineHeight, selectedBG)
 	tinyfont.WriteLine(
 		g.Display, g.Font,
 		g.X+g.PaddingLeft, midLineY+lineHeight+g.Y-g.PaddingBottom-1,
-		names[selected], g.BackgroundColor,
+		names[selected], selectedFG,
 	)
 
 	// 显示上方行
 	minY := midLineY
 	for i := int16(1); midLineY-i*lineHeight >= 0 && int16(selected)-i >= 0; i++ {
-		for y := midLineY - i*lineHeight; y < midLineY-(i-1)*lineHeight; y++ {
-			for x := int16(0); x < width; x++ {
-				g.Display.SetPixel(x+g.X, y+g.Y, g.BackgroundColor)
-			}
-		}
 		y := midLineY - i*lineHeight
+		g.fill(0, y, width, lineHeight, g.BackgroundColor)
 		minY = y
 		tinyfont.WriteLine(
 			g.Display, g.Font,
@@ -90,21 +138,13 @@ func (g *GraphicsDisplay) Show(m *Menu) {
 			names[int16(selected)-i], g.ForegroundColor,
 		)
 	}
-	for y := int16(0); y < minY; y++ {
-		for x := int16(0); x < width; x++ {
-			g.Display.SetPixel(x+g.X, y+g.Y, g.BackgroundColor)
-		}
-	}
+	g.fill(0, 0, width, minY, g.BackgroundColor)
 
 	// 显示下方行
 	maxY := midLineY + lineHeight
 	for i := int16(1); midLineY+(i+1)*lineHeight < height && int16(selected)+i < int16(len(names)); i++ {
-		for y := midLineY + i*lineHeight; y < midLineY+(i+1)*lineHeight; y++ {
-			for x := int16(0); x < width; x++ {
-				g.Display.SetPixel(x+g.X, y+g.Y, g.BackgroundColor)
-			}
-		}
 		y := midLineY + i*lineHeight
+		g.fill(0, y, width, lineHeight, g.BackgroundColor)
 		maxY = y + lineHeight
 		tinyfont.WriteLine(
 			g.Display, g.Font,
@@ -112,11 +152,24 @@ func (g *GraphicsDisplay) Show(m *Menu) {
 			names[int16(selected)+i], g.ForegroundColor,
 		)
 	}
-	for y := maxY; y < height; y++ {
-		for x := int16(0); x < width; x++ {
-			g.Display.SetPixel(x+g.X, y+g.Y, g.BackgroundColor)
-		}
-	}
+	g.fill(0, maxY, width, height-maxY, g.BackgroundColor)
 
 	_ = g.Display.Display()
 }
+
+// fill 以颜色 c 填充显示区域内的矩形，坐标相对于显示区域左上角
+func (g *GraphicsDisplay) fill(x, y, width, height int16, c color.RGBA) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if f, ok := g.Display.(rectFiller); ok {
+		if err := f.FillRectangle(x+g.X, y+g.Y, width, height, c); err == nil {
+			return
+		}
+	}
+	for dy := int16(0); dy < height; dy++ {
+		for dx := int16(0); dx < width; dx++ {
+			g.Display.SetPixel(x+dx+g.X, y+dy+g.Y, c)
+		}
+	}
+}
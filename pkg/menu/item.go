@@ -23,6 +23,12 @@ type Node interface {
 	SetParent(parent Node)
 }
 
+// Refresher 显示内容会随时间变化的节点
+type Refresher interface {
+	// Refresh 返回自上次显示后显示内容是否有变化
+	Refresh() bool
+}
+
 // BaseNode Node 的一个基础实现
 type BaseNode struct {
 	NodeName string
@@ -132,10 +138,38 @@ type ValueNode struct {
 	FormatValue func(value int32) string
 	// 进入当前节点所选项时执行
 	OnEnter func(node *ValueNode)
+
+	// 值的范围和步长，步长为 0 时不限制范围
+	Min, Max, Step int32
 }
 
 var _ Node = (*ValueNode)(nil)
 
+// NewRangeValueNode 创建值在 [min, max] 范围内、每次调整 step 的 *ValueNode ，节点名包含值
+func NewRangeValueNode(
+	name string,
+	val, min, max, step int32,
+	format func(value int32) string,
+	onEnter func(node *ValueNode),
+) *ValueNode {
+	if format == nil {
+		format = func(value int32) string {
+			return strconv.FormatInt(int64(value), 10)
+		}
+	}
+	node := &ValueNode{
+		BaseNode:    BaseNode{NodeName: name},
+		NodeName:    nodeNameWithValue,
+		FormatValue: format,
+		OnEnter:     onEnter,
+		Min:         min,
+		Max:         max,
+		Step:        step,
+	}
+	node.SetValue(val)
+	return node
+}
+
 // Name 返回当前节点名
 func (node *ValueNode) Name() string {
 	if node.NodeName != nil {
@@ -166,11 +200,23 @@ func (node *ValueNode) Items() (names []string, selected int32) {
 	return []string{strconv.FormatInt(int64(node.cursor), 10)}, 0
 }
 
+// NextN 选择下 n 项，若 n 是负数表示上 -n 项
+func (node *ValueNode) NextN(n int32) {
+	if node.Step == 0 {
+		node.cursor += n
+		return
+	}
+	node.SetValue(node.cursor + n*node.Step)
+}
+
 // AddChildren 添加子节点
 func (node *ValueNode) AddChildren(_ ...Node) {}
 
-// SetValue 设置值
+// SetValue 设置值，有范围时限制在范围内
 func (node *ValueNode) SetValue(v int32) {
+	if node.Step != 0 {
+		v = min(max(v, node.Min), node.Max)
+	}
 	node.cursor = v
 }
 
@@ -187,12 +233,22 @@ func nodeNameWithValue(node *ValueNode) string {
 // ActionNode 动作节点， Node 的实现
 type ActionNode struct {
 	BaseNode
+	// 节点名，为 nil 时使用 BaseNode.NodeName
+	NodeName func(node *ActionNode) string
 	// 进入当前节点所选项时执行
 	OnEnter func(node *ActionNode)
 }
 
 var _ Node = (*ActionNode)(nil)
 
+// Name 返回当前节点名
+func (node *ActionNode) Name() string {
+	if node.NodeName != nil {
+		return node.NodeName(node)
+	}
+	return node.BaseNode.Name()
+}
+
 // Enter 进入当前节点，返回进入后的节点
 func (node *ActionNode) Enter() Node {
 	if node.OnEnter != nil {
@@ -230,3 +286,163 @@ func (node *BackNode) Entered() Node {
 
 // AddChildren 添加子节点
 func (node *BackNode) AddChildren(_ ...Node) {}
+
+// NewLinesNode 创建 *LinesNode
+func NewLinesNode(name string, lines func() []string) *LinesNode {
+	return &LinesNode{
+		BaseNode: BaseNode{NodeName: name},
+		Lines:    lines,
+	}
+}
+
+// LinesNode 显示多行文本的节点，进入后可滚动浏览， Node 的实现
+//
+// 选中最后一行时，有新行加入后会自动跟随到最后一行
+type LinesNode struct {
+	BaseNode
+	// 获取要显示的行
+	Lines func() []string
+
+	follow bool
+	shown  []string
+}
+
+var _ Node = (*LinesNode)(nil)
+var _ Refresher = (*LinesNode)(nil)
+
+// Enter 进入当前节点，返回进入后的节点
+func (node *LinesNode) Enter() Node {
+	// 浏览时进入则返回父节点
+	return node.Back()
+}
+
+// Entered 返回当前节点被进入后进入的节点
+func (node *LinesNode) Entered() Node {
+	// 刚进入时选中最后一行
+	node.follow = true
+	return node
+}
+
+// NextN 选择下 n 项，若 n 是负数表示上 -n 项
+func (node *LinesNode) NextN(n int32) {
+	lines := node.lines()
+	node.cursor = node.clamp(node.cursor+n, len(lines))
+	node.follow = len(lines) == 0 || node.cursor == int32(len(lines)-1)
+}
+
+// Items 返回当前节点的子项和所选项序号
+func (node *LinesNode) Items() (names []string, selected int32) {
+	lines := node.lines()
+	if node.follow {
+		node.cursor = int32(len(lines) - 1)
+	}
+	node.cursor = node.clamp(node.cursor, len(lines))
+	node.shown = lines
+	return lines, node.cursor
+}
+
+// Refresh 返回显示内容是否有变化
+func (node *LinesNode) Refresh() bool {
+	lines := node.lines()
+	if len(lines) != len(node.shown) {
+		return true
+	}
+	for i := range lines {
+		if lines[i] != node.shown[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// AddChildren 添加子节点
+func (node *LinesNode) AddChildren(_ ...Node) {}
+
+// lines 返回要显示的行
+func (node *LinesNode) lines() []string {
+	if node.Lines == nil {
+		return nil
+	}
+	return node.Lines()
+}
+
+// clamp 将 cursor 限制在 [0, n) 范围内
+func (node *LinesNode) clamp(cursor int32, n int) int32 {
+	if cursor >= int32(n) {
+		cursor = int32(n) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	return cursor
+}
+
+// NewNoticeNode 创建 *NoticeNode
+func NewNoticeNode(name string, lines func() []string, confirm string, onConfirm func() Node) *NoticeNode {
+	return &NoticeNode{
+		BaseNode:  BaseNode{NodeName: name},
+		Lines:     lines,
+		Confirm:   confirm,
+		OnConfirm: onConfirm,
+	}
+}
+
+// NoticeNode 需要确认的提示节点，显示多行文本和最后的确认项， Node 的实现
+//
+// 不能返回，只有进入确认项时执行 OnConfirm ，并切换到其返回的节点
+type NoticeNode struct {
+	BaseNode
+	// 获取要显示的行
+	Lines func() []string
+	// 确认项名
+	Confirm string
+	// 确认时执行，返回确认后切换到的节点，为 nil 时留在当前节点
+	OnConfirm func() Node
+}
+
+var _ Node = (*NoticeNode)(nil)
+
+// Back 不能返回，返回当前节点
+func (node *NoticeNode) Back() Node {
+	return node
+}
+
+// Enter 进入当前节点所选项，选中确认项时执行 OnConfirm
+func (node *NoticeNode) Enter() Node {
+	if node.cursor != int32(len(node.lines())) || node.OnConfirm == nil {
+		return node
+	}
+	if next := node.OnConfirm(); next != nil {
+		return next
+	}
+	return node
+}
+
+// Entered 返回当前节点被进入后进入的节点
+func (node *NoticeNode) Entered() Node {
+	return node
+}
+
+// NextN 选择下 n 项，若 n 是负数表示上 -n 项
+func (node *NoticeNode) NextN(n int32) {
+	node.cursor = min(max(node.cursor+n, 0), int32(len(node.lines())))
+}
+
+// Items 返回提示行和确认项，以及所选项序号
+func (node *NoticeNode) Items() (names []string, selected int32) {
+	lines := node.lines()
+	names = append(append(names, lines...), node.Confirm)
+	node.cursor = min(node.cursor, int32(len(lines)))
+	return names, node.cursor
+}
+
+// AddChildren 添加子节点
+func (node *NoticeNode) AddChildren(_ ...Node) {}
+
+// lines 返回要显示的行
+func (node *NoticeNode) lines() []string {
+	if node.Lines == nil {
+		return nil
+	}
+	return node.Lines()
+}
@@ -0,0 +1,67 @@
+package menu
+
+import "testing"
+
+// TestRangeValueNode 测试有范围的 ValueNode
+func TestRangeValueNode(t *testing.T) {
+	var entered int32
+	node := NewRangeValueNode("Speed", 50, 10, 100, 5, nil, func(node *ValueNode) {
+		entered = node.Value()
+	})
+	if name := node.Name(); name != "Speed: 50" {
+		t.Errorf("unexpected name %q", name)
+	}
+
+	node.NextN(3)
+	if v := node.Value(); v != 65 {
+		t.Errorf("expected 65, got %d", v)
+	}
+	node.NextN(100)
+	if v := node.Value(); v != 100 {
+		t.Errorf("expected clamped to 100, got %d", v)
+	}
+	node.NextN(-100)
+	if v := node.Value(); v != 10 {
+		t.Errorf("expected clamped to 10, got %d", v)
+	}
+	node.SetValue(1000)
+	if v := node.Value(); v != 100 {
+		t.Errorf("expected clamped to 100, got %d", v)
+	}
+
+	node.Enter()
+	if entered != 100 {
+		t.Errorf("expected entered with 100, got %d", entered)
+	}
+}
+
+// TestNoticeNode 测试 NoticeNode 只能通过确认项离开
+func TestNoticeNode(t *testing.T) {
+	next := &BaseNode{NodeName: "Root"}
+	confirmed := 0
+	node := NewNoticeNode("Fault", func() []string {
+		return []string{"WATCHDOG", "RESET"}
+	}, "Ack", func() Node {
+		confirmed++
+		return next
+	})
+
+	if n := node.Back(); n != node {
+		t.Errorf("expected back to stay on notice")
+	}
+	if n := node.Enter(); n != node || confirmed != 0 {
+		t.Errorf("expected enter on text line to stay on notice")
+	}
+	node.NextN(10)
+	names, selected := node.Items()
+	if len(names) != 3 || names[2] != "Ack" || selected != 2 {
+		t.Errorf("unexpected items %v selected %d", names, selected)
+	}
+	if n := node.Enter(); n != next || confirmed != 1 {
+		t.Errorf("expected confirm to switch to next node")
+	}
+	node.NextN(-10)
+	if _, selected := node.Items(); selected != 0 {
+		t.Errorf("expected selected 0, got %d", selected)
+	}
+}
@@ -3,10 +3,20 @@ package menu
 import (
 	"context"
 	"sync"
+	"time"
 )
 
+// DefaultRefreshInterval 默认刷新间隔
+const DefaultRefreshInterval = 500 * time.Millisecond
+
 // Menu 菜单
 type Menu struct {
+	// 检查当前节点是否需要刷新显示的间隔
+	// 默认为 DefaultRefreshInterval
+	RefreshInterval time.Duration
+	// 看门狗，处理输入时每个刷新间隔喂狗一次，为 nil 时不喂狗
+	Watchdog Watchdog
+
 	lock sync.RWMutex
 	root Node
 
@@ -15,6 +25,12 @@ type Menu struct {
 	operationChan chan Operation
 }
 
+// Watchdog 看门狗，如 machine.Watchdog
+type Watchdog interface {
+	// Update 喂狗
+	Update()
+}
+
 // SetRoot 设置菜单根节点
 func (m *Menu) SetRoot(root Node) {
 	m.lock.Lock()
@@ -42,10 +58,21 @@ func (m *Menu) HandleInputs(ctx context.Context) {
 	for _, input := range m.inputs {
 		input.StartReceiving(m.operationChan)
 	}
+	interval := m.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-ticker.C:
+			if m.Watchdog != nil {
+				m.Watchdog.Update()
+			}
+			m.Refresh()
 		case op := <-m.operationChan:
 			switch {
 			case op.NextN != nil:
@@ -54,6 +81,10 @@ func (m *Menu) HandleInputs(ctx context.Context) {
 				m.Enter()
 			case op.Back != nil:
 				m.Back()
+			case op.Run != nil:
+				op.Run.Func()
+			case op.Jump != nil:
+				m.Jump(op.Jump.Func)
 			}
 		}
 	}
@@ -68,6 +99,25 @@ func (m *Menu) Show() {
 	}
 }
 
+// Refresh 驱动会随时间切换的节点，若当前节点显示内容有变化则重新显示
+func (m *Menu) Refresh() {
+	m.lock.Lock()
+	changed := false
+	if t, ok := m.root.(Ticker); ok {
+		if next := t.Tick(); next != m.root {
+			m.root = next.Entered()
+			changed = true
+		}
+	}
+	if r, ok := m.root.(Refresher); ok && r.Refresh() {
+		changed = true
+	}
+	m.lock.Unlock()
+	if changed {
+		m.Show()
+	}
+}
+
 // NextN 选择下 n 项，若 n 是负数表示上 -n 项
 func (m *Menu) NextN(n int32) {
 	m.lock.Lock()
@@ -104,6 +154,18 @@ func (m *Menu) Back() {
 	m.Show()
 }
 
+// Jump 以当前节点调用 f ，并切换到 f 返回的节点
+func (m *Menu) Jump(f func(current Node) Node) {
+	m.lock.Lock()
+	if m.root == nil {
+		m.lock.Unlock()
+		return
+	}
+	m.root = f(m.root).Entered()
+	m.lock.Unlock()
+	m.Show()
+}
+
 // ItemNames 返回选项名和当前所选项序号
 func (m *Menu) ItemNames() (names []string, selected int32) {
 	m.lock.RLock()
@@ -0,0 +1,48 @@
+//go:build tinygo
+
+package menu
+
+import (
+	"machine"
+	"time"
+)
+
+// pinPollInterval 外部触发输入的采样间隔
+const pinPollInterval = time.Millisecond
+
+// PinTrigger 基于 GPIO 的外部触发输入，如脚踏开关、有线遥控或其它板子的逻辑信号，菜单用户交互界面输入源的实现
+type PinTrigger struct {
+	// 输入针脚，需已配置为输入
+	Pin machine.Pin
+	// 去抖、边沿和锁定配置
+	Debouncer Debouncer
+	// 触发时输入的菜单操作
+	Operation Operation
+	// 触发时直接在采样协程中调用，不经过菜单操作队列，用于急停等需立即响应的动作
+	// 不为 nil 时忽略 Operation
+	Immediate func()
+	// 返回是否正在执行不应排队的动作（如球杆运动），此时的触发被丢弃，为 nil 时不丢弃
+	Busy func() bool
+}
+
+var _ UIInput = (*PinTrigger)(nil)
+
+// StartReceiving 开始接收操作，并将操作输入到 ch
+//
+// 菜单暂时无法接收操作时保留触发并在下次采样时重试， Busy 返回 true 时丢弃，见 Pending 。
+func (p *PinTrigger) StartReceiving(ch chan<- Operation) {
+	go func() {
+		pending := &Pending{Busy: p.Busy}
+		for {
+			time.Sleep(pinPollInterval)
+			if p.Debouncer.Update(p.Pin.Get(), time.Now()) {
+				if p.Immediate != nil {
+					p.Immediate()
+					continue
+				}
+				pending.Trigger()
+			}
+			pending.Deliver(ch, p.Operation)
+		}
+	}()
+}
@@ -1,3 +1,5 @@
+//go:build tinygo
+
 package menu
 
 import (
@@ -10,6 +12,8 @@ import (
 type Serial struct {
 	// 接收输入和发送输出的串口
 	Serial machine.Serialer
+	// 以 CommandPrefix 开头的行作为命令执行，为 nil 时不支持命令
+	Commands *Commands
 }
 
 var _ UIOutput = (*Serial)(nil)
@@ -45,6 +49,19 @@ func (s *Serial) StartReceiving(ch chan<- Operation) {
 				input += string(c)
 				break
 			}
+			if s.Commands != nil && input[0] == CommandPrefix {
+				// 命令模式，读取到行尾
+				c := input[len(input)-1]
+				if c != '\r' && c != '\n' {
+					continue
+				}
+				line := input[1 : len(input)-1]
+				input = ""
+				ch <- Operation{Run: &Run{Func: func() {
+					s.Commands.Exec(line, s.Serial)
+				}}}
+				continue
+			}
 			switch input {
 			case "\x1b[A": // 上
 				ch <- Operation{NextN: &NextN{N: -1}}
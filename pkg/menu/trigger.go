@@ -0,0 +1,271 @@
+package menu
+
+import (
+	"strconv"
+	"time"
+)
+
+// Ticker 会随时间自动切换到其它节点的节点
+type Ticker interface {
+	// Tick 定时调用，返回之后的当前节点，不切换时返回自身
+	Tick() Node
+}
+
+// Trigger 动作触发方式，为任意进入时执行动作的节点（如 *ActionNode 、 *ValueNode ）添加倒计时和两步确认，并支持重复上次的动作
+//
+// 倒计时由 Menu 刷新时驱动，精度取决于 Menu.RefreshInterval 。倒计时中返回或再次进入会取消动作。
+type Trigger struct {
+	// 倒计时时长，为 0 时立即执行
+	Countdown time.Duration
+	// 两步确认，为 true 时需先进入一次准备，再进入一次才开始倒计时
+	Arm bool
+	// 倒计时剩余秒数变化时调用，剩余 0 时表示即将执行动作
+	OnTick func(remaining int)
+	// 节点准备或取消准备时调用
+	OnArm func(armed bool)
+
+	last *TriggerNode
+	now  func() time.Time
+}
+
+// Wrap 包装 node ，返回以该触发方式执行动作的节点
+func (t *Trigger) Wrap(node Node) *TriggerNode {
+	return &TriggerNode{Node: node, trigger: t}
+}
+
+// NewRepeatNode 创建重复上次动作的节点，没有上次动作时不执行
+func (t *Trigger) NewRepeatNode(name string) *RepeatNode {
+	return &RepeatNode{BaseNode: BaseNode{NodeName: name}, trigger: t}
+}
+
+// Last 返回最近执行过动作的节点，没有时返回 nil
+func (t *Trigger) Last() *TriggerNode {
+	return t.last
+}
+
+// Start 不经两步确认，按倒计时执行 node 的动作，返回之后的当前节点
+//
+// 动作执行或倒计时取消后进入 ret ，为 nil 时进入动作返回的节点，用于从菜单以外（如外部触发输入）执行动作。
+// ret 是正在进行的倒计时时取消该倒计时。
+func (t *Trigger) Start(node *TriggerNode, ret Node) Node {
+	if c, ok := ret.(*countdownNode); ok {
+		return c.Back()
+	}
+	if t.Countdown <= 0 {
+		return t.fire(node, ret)
+	}
+	c := &countdownNode{
+		trigger:  t,
+		node:     node,
+		ret:      ret,
+		deadline: t.clock().Add(t.Countdown),
+	}
+	c.SetParent(node)
+	c.tick()
+	return c
+}
+
+// Repeat 按倒计时重复上次动作，之后进入 ret ，没有上次动作时直接返回 ret
+func (t *Trigger) Repeat(ret Node) Node {
+	if t.last == nil {
+		return ret
+	}
+	return t.Start(t.last, ret)
+}
+
+// fire 执行 node 的动作
+func (t *Trigger) fire(node *TriggerNode, ret Node) Node {
+	t.last = node
+	next := node.Node.Enter()
+	if ret != nil {
+		return ret
+	}
+	return next
+}
+
+// clock 返回当前时间
+func (t *Trigger) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// TriggerNode 以 Trigger 方式执行动作的节点， Node 的实现
+type TriggerNode struct {
+	Node
+
+	trigger *Trigger
+	armed   bool
+}
+
+var _ Node = (*TriggerNode)(nil)
+
+// Name 返回当前节点名，已准备时加上标记
+func (node *TriggerNode) Name() string {
+	if node.armed {
+		return "*" + node.Node.Name()
+	}
+	return node.Node.Name()
+}
+
+// Enter 进入当前节点所选项，按触发方式执行动作
+func (node *TriggerNode) Enter() Node {
+	if node.trigger.Arm && !node.armed {
+		node.setArmed(true)
+		return node
+	}
+	node.setArmed(false)
+	return node.trigger.Start(node, nil)
+}
+
+// Entered 返回当前节点被进入后进入的节点
+func (node *TriggerNode) Entered() Node {
+	if entered := node.Node.Entered(); entered != node.Node {
+		return entered
+	}
+	return node
+}
+
+// Back 退出当前节点，取消准备
+func (node *TriggerNode) Back() Node {
+	node.setArmed(false)
+	return node.Node.Back()
+}
+
+// setArmed 设置是否已准备，有变化时调用 Trigger.OnArm
+func (node *TriggerNode) setArmed(armed bool) {
+	if armed == node.armed {
+		return
+	}
+	node.armed = armed
+	if node.trigger.OnArm != nil {
+		node.trigger.OnArm(armed)
+	}
+}
+
+// Items 返回当前节点的子项和所选项序号，已准备时在最前面显示提示
+func (node *TriggerNode) Items() (names []string, selected int32) {
+	names, selected = node.Node.Items()
+	switch {
+	case !node.armed:
+		return names, selected
+	case len(names) == 0:
+		return []string{"ARMED"}, 0
+	}
+	return append([]string{"ARMED"}, names...), selected + 1
+}
+
+// RepeatNode 重复上次动作的节点， Node 的实现
+type RepeatNode struct {
+	BaseNode
+
+	trigger *Trigger
+}
+
+var _ Node = (*RepeatNode)(nil)
+
+// Name 返回当前节点名，包含上次动作名
+func (node *RepeatNode) Name() string {
+	if last := node.trigger.last; last != nil {
+		return node.NodeName + ": " + last.Node.Name()
+	}
+	return node.NodeName
+}
+
+// Enter 进入当前节点，按触发方式重复上次动作，不需要两步确认
+func (node *RepeatNode) Enter() Node {
+	return node.trigger.Repeat(node.Back())
+}
+
+// Entered 返回当前节点被进入后进入的节点
+func (node *RepeatNode) Entered() Node {
+	return node
+}
+
+// AddChildren 添加子节点
+func (node *RepeatNode) AddChildren(_ ...Node) {}
+
+// countdownNode 倒计时节点， Node 和 Ticker 的实现
+type countdownNode struct {
+	BaseNode
+
+	trigger   *Trigger
+	node      *TriggerNode
+	ret       Node
+	deadline  time.Time
+	remaining int
+	shown     int
+}
+
+var _ Node = (*countdownNode)(nil)
+var _ Ticker = (*countdownNode)(nil)
+var _ Refresher = (*countdownNode)(nil)
+
+// Name 返回当前节点名
+func (node *countdownNode) Name() string {
+	return node.node.Name()
+}
+
+// Enter 取消倒计时
+func (node *countdownNode) Enter() Node {
+	return node.Back()
+}
+
+// Back 取消倒计时，返回 ret ，为 nil 时返回动作节点
+func (node *countdownNode) Back() Node {
+	if node.ret != nil {
+		return node.ret
+	}
+	return node.BaseNode.Back()
+}
+
+// Entered 返回当前节点被进入后进入的节点
+func (node *countdownNode) Entered() Node {
+	return node
+}
+
+// Items 返回动作名和剩余秒数
+func (node *countdownNode) Items() (names []string, selected int32) {
+	node.shown = node.remaining
+	return []string{node.node.Node.Name(), "in " + strconv.Itoa(node.remaining) + "s"}, 1
+}
+
+// NextN 倒计时中不可选择
+func (node *countdownNode) NextN(_ int32) {}
+
+// Refresh 返回剩余秒数是否有变化
+func (node *countdownNode) Refresh() bool {
+	return node.shown != node.remaining
+}
+
+// Tick 更新倒计时，到时执行动作
+func (node *countdownNode) Tick() Node {
+	node.tick()
+	if node.trigger.clock().Before(node.deadline) {
+		return node
+	}
+	return node.trigger.fire(node.node, node.ret)
+}
+
+// tick 更新剩余秒数
+func (node *countdownNode) tick() {
+	if s := node.seconds(); s != node.remaining {
+		node.remaining = s
+		if node.trigger.OnTick != nil {
+			node.trigger.OnTick(s)
+		}
+	}
+}
+
+// seconds 返回向上取整的剩余秒数
+func (node *countdownNode) seconds() int {
+	d := node.deadline.Sub(node.trigger.clock())
+	if d <= 0 {
+		return 0
+	}
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// AddChildren 添加子节点
+func (node *countdownNode) AddChildren(_ ...Node) {}
@@ -0,0 +1,152 @@
+package menu
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock 可手动推进的时钟
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+// newTriggerMenu 创建包含一个被 trigger 包装的动作的菜单，返回菜单和动作执行次数
+func newTriggerMenu(trigger *Trigger) (*Menu, *int) {
+	count := 0
+	root := &BaseNode{NodeName: "Root"}
+	root.AddChildren(
+		trigger.Wrap(&ActionNode{
+			BaseNode: BaseNode{NodeName: "Swing"},
+			OnEnter:  func(_ *ActionNode) { count++ },
+		}),
+		trigger.NewRepeatNode("Repeat"),
+	)
+	m := &Menu{}
+	m.SetRoot(root)
+	return m, &count
+}
+
+// TestTrigger_Countdown 测试倒计时
+func TestTrigger_Countdown(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	var ticks []int
+	trigger := &Trigger{
+		Countdown: 3 * time.Second,
+		OnTick:    func(remaining int) { ticks = append(ticks, remaining) },
+		now:       clock.now,
+	}
+	m, count := newTriggerMenu(trigger)
+
+	m.Enter() // 进入 Swing
+	m.Enter() // 开始倒计时
+	if names, _ := m.ItemNames(); !reflect.DeepEqual(names, []string{"Swing", "in 3s"}) {
+		t.Errorf("unexpected countdown items: %v", names)
+	}
+	for i := 0; i < 6; i++ {
+		clock.t = clock.t.Add(500 * time.Millisecond)
+		m.Refresh()
+	}
+	if *count != 1 {
+		t.Errorf("expected action fired once, got %d", *count)
+	}
+	if !reflect.DeepEqual(ticks, []int{3, 2, 1, 0}) {
+		t.Errorf("unexpected ticks: %v", ticks)
+	}
+	if names, _ := m.ItemNames(); names[0] != "Swing" || names[1] != "Repeat: Swing" {
+		t.Errorf("expected back to root, got %v", names)
+	}
+
+	// 重复上次动作，倒计时中取消
+	m.NextN(1)
+	m.Enter() // 进入 Repeat
+	m.Enter() // 开始倒计时
+	clock.t = clock.t.Add(time.Second)
+	m.Refresh()
+	m.Back()
+	clock.t = clock.t.Add(5 * time.Second)
+	m.Refresh()
+	if *count != 1 {
+		t.Errorf("expected cancelled countdown not to fire, got %d", *count)
+	}
+}
+
+// TestTrigger_Arm 测试两步确认
+func TestTrigger_Arm(t *testing.T) {
+	var arms []bool
+	trigger := &Trigger{Arm: true, OnArm: func(armed bool) { arms = append(arms, armed) }}
+	m, count := newTriggerMenu(trigger)
+
+	m.Enter() // 进入 Swing
+	m.Enter() // 准备
+	if *count != 0 {
+		t.Fatalf("expected not fired after arming")
+	}
+	if names, selected := m.ItemNames(); len(names) != 1 || names[0] != "ARMED" || selected != 0 {
+		t.Errorf("unexpected armed items: %v %d", names, selected)
+	}
+	m.Enter() // 触发
+	if *count != 1 {
+		t.Errorf("expected fired once, got %d", *count)
+	}
+
+	// 准备后返回则取消
+	m.Enter()
+	m.Enter()
+	m.Back()
+	m.Enter()
+	if *count != 1 {
+		t.Errorf("expected disarmed after back, got %d", *count)
+	}
+	if !reflect.DeepEqual(arms, []bool{true, false, true, false}) {
+		t.Errorf("unexpected arm events: %v", arms)
+	}
+}
+
+// TestTrigger_Start 测试从菜单以外执行动作
+func TestTrigger_Start(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	trigger := &Trigger{Countdown: time.Second, Arm: true, now: clock.now}
+	count := 0
+	swing := trigger.Wrap(&ActionNode{
+		BaseNode: BaseNode{NodeName: "Swing"},
+		OnEnter:  func(_ *ActionNode) { count++ },
+	})
+	root := &BaseNode{NodeName: "Root"}
+	root.AddChildren(&BaseNode{NodeName: "Other"})
+	m := &Menu{}
+	m.SetRoot(root)
+
+	// 外部触发不需要两步确认，倒计时后回到原来的节点
+	m.Jump(func(current Node) Node { return trigger.Start(swing, current) })
+	if names, _ := m.ItemNames(); !reflect.DeepEqual(names, []string{"Swing", "in 1s"}) {
+		t.Errorf("unexpected countdown items: %v", names)
+	}
+	clock.t = clock.t.Add(time.Second)
+	m.Refresh()
+	if count != 1 {
+		t.Errorf("expected fired once, got %d", count)
+	}
+	if names, _ := m.ItemNames(); !reflect.DeepEqual(names, []string{"Other"}) {
+		t.Errorf("expected back to root, got %v", names)
+	}
+
+	// 倒计时中取消或再次触发都回到原来的节点
+	m.Jump(func(current Node) Node { return trigger.Repeat(current) })
+	m.Back()
+	if names, _ := m.ItemNames(); !reflect.DeepEqual(names, []string{"Other"}) {
+		t.Errorf("expected back to root, got %v", names)
+	}
+	m.Jump(func(current Node) Node { return trigger.Repeat(current) })
+	m.Jump(func(current Node) Node { return trigger.Repeat(current) })
+	if names, _ := m.ItemNames(); !reflect.DeepEqual(names, []string{"Other"}) {
+		t.Errorf("expected back to root, got %v", names)
+	}
+	clock.t = clock.t.Add(time.Second)
+	m.Refresh()
+	if count != 1 {
+		t.Errorf("expected cancelled countdown not to fire, got %d", count)
+	}
+}
@@ -21,6 +21,10 @@ type Operation struct {
 	Enter *Enter
 	// 返回操作
 	Back *Back
+	// 执行函数操作
+	Run *Run
+	// 跳转操作
+	Jump *Jump
 }
 
 // NextN 选择下或上 n 项操作
@@ -33,3 +37,13 @@ type Enter struct{}
 
 // Back 返回操作
 type Back struct{}
+
+// Run 执行函数操作，在处理菜单操作的协程中执行，不改变菜单状态
+type Run struct {
+	Func func()
+}
+
+// Jump 跳转操作，在处理菜单操作的协程中以当前节点调用 Func ，并切换到其返回的节点
+type Jump struct {
+	Func func(current Node) Node
+}
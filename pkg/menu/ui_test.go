@@ -0,0 +1,98 @@
+package menu
+
+import (
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"tinygo.org/x/tinyfont/proggy"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/memdisplay"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/memdisplay/memdisplaytest"
+)
+
+// newTestMenu 创建包含 n 个子项并选中第 selected 项的菜单
+func newTestMenu(n int, selected int32) *Menu {
+	names := []string{"Driver", "Spoon", "3-Iron", "5-Iron", "7-Iron", "9-Iron", "Wedge", "Putter"}
+	root := &BaseNode{NodeName: "Root"}
+	for i := 0; i < n; i++ {
+		root.AddChildren(&BaseNode{NodeName: names[i%len(names)]})
+	}
+	root.NextN(selected)
+	m := &Menu{}
+	m.SetRoot(root)
+	return m
+}
+
+// newTestGraphicsDisplay 创建与固件中配置一致的 *GraphicsDisplay
+func newTestGraphicsDisplay(d *memdisplay.Display) *GraphicsDisplay {
+	return &GraphicsDisplay{
+		Display:         d,
+		Font:            &proggy.TinySZ8pt7b,
+		ForegroundColor: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		BackgroundColor: color.RGBA{A: 255},
+		PaddingLeft:     1,
+		PaddingTop:      -1,
+		PaddingBottom:   1,
+		Width:           40,
+		Height:          32,
+	}
+}
+
+// TestGraphicsDisplay_Show 测试 GraphicsDisplay.Show
+func TestGraphicsDisplay_Show(t *testing.T) {
+	cases := []struct {
+		name     string
+		items    int
+		selected int32
+		height   int16
+		modify   func(g *GraphicsDisplay)
+	}{
+		{name: "top", items: 8, selected: 0},
+		{name: "middle", items: 8, selected: 3},
+		{name: "bottom", items: 8, selected: 7},
+		{name: "empty", items: 0},
+		{name: "single", items: 1},
+		{name: "no-padding", items: 8, selected: 3, modify: func(g *GraphicsDisplay) {
+			g.PaddingLeft = 0
+			g.PaddingTop = 0
+			g.PaddingBottom = 0
+		}},
+		{name: "large-padding", items: 8, selected: 3, modify: func(g *GraphicsDisplay) {
+			g.PaddingLeft = 3
+			g.PaddingTop = 2
+			g.PaddingBottom = 2
+		}},
+		{name: "offset", items: 8, selected: 3, modify: func(g *GraphicsDisplay) {
+			g.X = 64
+			g.Y = 4
+			g.Height = 24
+		}},
+		{name: "theme", items: 8, selected: 3, modify: func(g *GraphicsDisplay) {
+			g.SetTheme(ThemeBlue)
+		}},
+		{name: "tall", items: 8, selected: 3, height: 64, modify: func(g *GraphicsDisplay) {
+			g.Width = 0
+			g.Height = 0
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			height := c.height
+			if height == 0 {
+				height = 32
+			}
+			d := memdisplay.New(128, height)
+			g := newTestGraphicsDisplay(d)
+			if c.modify != nil {
+				c.modify(g)
+			}
+			g.Show(newTestMenu(c.items, c.selected))
+			if d.Flushes() != 1 {
+				t.Errorf("expected 1 flush, got %d", d.Flushes())
+			}
+			memdisplaytest.CheckGolden(t, d, filepath.Join("testdata", "graphics-display-"+c.name+".txt"))
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package power
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+)
+
+const (
+	// DefaultReference 默认 ADC 参考电压（单位：伏）
+	DefaultReference float32 = 3.3
+	// adcFullScale ADC 读数满量程
+	adcFullScale float32 = 65535
+	// smoothing 电压指数平滑系数，越小越平滑
+	smoothing float32 = 0.25
+	// hysteresis 电压回升超过阈值该值（单位：伏）后才恢复等级，避免在阈值附近反复切换
+	hysteresis float32 = 0.1
+)
+
+// ErrLowVoltage 电源电压过低
+var ErrLowVoltage = errors.New("supply voltage too low")
+
+// Sampler ADC 采样，如 machine.ADC
+type Sampler interface {
+	// Get 返回 0 到 65535 的读数
+	Get() uint16
+}
+
+// Level 电源电压等级
+type Level uint8
+
+const (
+	// LevelOK 电压正常
+	LevelOK Level = iota
+	// LevelLow 电压偏低，警告并降速挥杆
+	LevelLow
+	// LevelCritical 电压过低，拒绝挥杆
+	LevelCritical
+)
+
+// String 返回等级名
+func (l Level) String() string {
+	switch l {
+	case LevelOK:
+		return "ok"
+	case LevelLow:
+		return "low"
+	case LevelCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// Config 电源电压检测配置，电压单位均为伏
+type Config struct {
+	// ADC 参考电压
+	// 默认为 DefaultReference
+	Reference float32
+	// 分压比，电源电压与 ADC 针脚电压之比
+	Divider float32
+	// 满电电压，用于计算百分比
+	Full float32
+	// 空电电压，用于计算百分比
+	Empty float32
+	// 低于该电压时警告，并按电压比例降低挥杆速度，为 0 时不警告
+	Low float32
+	// 低于该电压时拒绝挥杆，为 0 时不拒绝
+	Critical float32
+}
+
+// Validate 检查配置是否有效
+func (c *Config) Validate() error {
+	switch {
+	case c.Divider <= 0:
+		return fmt.Errorf("invalid divider ratio %g", c.Divider)
+	case c.Full <= c.Empty:
+		return fmt.Errorf("full voltage %g must be greater than empty voltage %g", c.Full, c.Empty)
+	case c.Low > 0 && c.Critical > c.Low:
+		return fmt.Errorf("critical voltage %g must not be greater than low voltage %g", c.Critical, c.Low)
+	}
+	return nil
+}
+
+// Monitor 电源电压监测， golfclubs.Supply 的实现
+type Monitor struct {
+	// ADC 采样
+	Sampler Sampler
+	// 配置
+	Config Config
+	// 电压等级变化时调用
+	OnLevel func(level Level, voltage float32)
+
+	lock    sync.Mutex
+	started bool
+	voltage float32
+	level   Level
+}
+
+var _ golfclubs.Supply = (*Monitor)(nil)
+
+// Update 采样一次并更新平滑后的电压和等级，返回平滑后的电压
+func (m *Monitor) Update() float32 {
+	m.lock.Lock()
+	ref := m.Config.Reference
+	if ref <= 0 {
+		ref = DefaultReference
+	}
+	v := float32(m.Sampler.Get()) / adcFullScale * ref * m.Config.Divider
+	if m.started {
+		v = m.voltage + (v-m.voltage)*smoothing
+	}
+	m.started = true
+	m.voltage = v
+
+	prev := m.level
+	m.level = m.nextLevel(v)
+	level := m.level
+	m.lock.Unlock()
+
+	if level != prev && m.OnLevel != nil {
+		m.OnLevel(level, v)
+	}
+	return v
+}
+
+// nextLevel 返回电压为 v 时的等级，回升时需超过阈值 hysteresis
+func (m *Monitor) nextLevel(v float32) Level {
+	c := &m.Config
+	below := func(threshold float32, level Level) bool {
+		if threshold <= 0 {
+			return false
+		}
+		if m.level >= level {
+			return v < threshold+hysteresis
+		}
+		return v < threshold
+	}
+	switch {
+	case below(c.Critical, LevelCritical):
+		return LevelCritical
+	case below(c.Low, LevelLow):
+		return LevelLow
+	}
+	return LevelOK
+}
+
+// Voltage 返回平滑后的电压
+func (m *Monitor) Voltage() float32 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.voltage
+}
+
+// Level 返回电压等级
+func (m *Monitor) Level() Level {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.level
+}
+
+// Percent 返回电压在空电和满电之间的百分比
+func (m *Monitor) Percent() uint8 {
+	v := m.Voltage()
+	p := (v - m.Config.Empty) / (m.Config.Full - m.Config.Empty) * 100
+	return uint8(min(max(p, 0), 100))
+}
+
+// Check 采样一次，电压过低时返回 ErrLowVoltage
+func (m *Monitor) Check() error {
+	v := m.Update()
+	if m.Level() == LevelCritical {
+		return fmt.Errorf("%w: %.1fV", ErrLowVoltage, v)
+	}
+	return nil
+}
+
+// SpeedScale 返回挥杆速度的缩放比例，电压低于 Config.Low 时按电压比例降低，否则为 1
+//
+// 步进电机高速时的可用转矩大致与电源电压成正比，按比例降速可避免丢步。
+func (m *Monitor) SpeedScale() float32 {
+	v := m.Voltage()
+	if m.Config.Low <= 0 || v >= m.Config.Low {
+		return 1
+	}
+	return max(v/m.Config.Low, 0)
+}
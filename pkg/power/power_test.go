@@ -0,0 +1,137 @@
+package power
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeSampler 返回固定读数的 Sampler
+type fakeSampler struct {
+	value uint16
+}
+
+// Get 返回读数
+func (s *fakeSampler) Get() uint16 {
+	return s.value
+}
+
+// voltsToRaw 返回分压比为 divider 时电源电压 v 对应的 ADC 读数
+func voltsToRaw(v, divider float32) uint16 {
+	return uint16(v / divider / DefaultReference * adcFullScale)
+}
+
+// TestMonitor 测试电压换算、平滑、百分比和降速
+func TestMonitor(t *testing.T) {
+	s := &fakeSampler{value: voltsToRaw(12, 5)}
+	m := &Monitor{Sampler: s, Config: Config{Divider: 5, Full: 12.6, Empty: 10.5, Low: 11, Critical: 10}}
+	if err := m.Config.Validate(); err != nil {
+		t.Fatalf("validate error: %v", err)
+	}
+
+	// 第一次采样直接作为电压
+	if v := m.Update(); v < 11.99 || v > 12.01 {
+		t.Errorf("expected 12V, got %f", v)
+	}
+	if p := m.Percent(); p != 71 {
+		t.Errorf("expected 71%%, got %d", p)
+	}
+	if scale := m.SpeedScale(); scale != 1 {
+		t.Errorf("expected no derating, got %f", scale)
+	}
+
+	// 之后的采样平滑
+	s.value = voltsToRaw(8, 5)
+	if v := m.Update(); v < 10.99 || v > 11.01 {
+		t.Errorf("expected 11V after smoothing, got %f", v)
+	}
+	if p := m.Percent(); p != 23 {
+		t.Errorf("expected 23%%, got %d", p)
+	}
+	s.value = voltsToRaw(10.5, 5)
+	for i := 0; i < 30; i++ {
+		m.Update()
+	}
+	if scale := m.SpeedScale(); scale < 0.95 || scale > 0.96 {
+		t.Errorf("expected scale 10.5/11, got %f", scale)
+	}
+	s.value = 0
+	for i := 0; i < 30; i++ {
+		m.Update()
+	}
+	if p := m.Percent(); p != 0 {
+		t.Errorf("expected 0%%, got %d", p)
+	}
+}
+
+// TestMonitor_Level 测试电压等级、迟滞和 Check
+func TestMonitor_Level(t *testing.T) {
+	s := &fakeSampler{}
+	var levels []Level
+	m := &Monitor{
+		Sampler: s,
+		Config:  Config{Divider: 5, Full: 12.6, Empty: 10.5, Low: 11, Critical: 10},
+		OnLevel: func(level Level, _ float32) { levels = append(levels, level) },
+	}
+	// 设置电压并等待平滑稳定
+	set := func(v float32) {
+		s.value = voltsToRaw(v, 5)
+		for i := 0; i < 50; i++ {
+			m.Update()
+		}
+	}
+
+	set(12)
+	if err := m.Check(); err != nil {
+		t.Errorf("unexpected check error: %v", err)
+	}
+	set(10.9)
+	if m.Level() != LevelLow {
+		t.Errorf("expected low, got %s", m.Level())
+	}
+	set(9.5)
+	if err := m.Check(); !errors.Is(err, ErrLowVoltage) {
+		t.Errorf("expected ErrLowVoltage, got %v", err)
+	}
+	// 迟滞范围内保持等级
+	set(10.05)
+	if m.Level() != LevelCritical {
+		t.Errorf("expected critical within hysteresis, got %s", m.Level())
+	}
+	set(10.5)
+	if m.Level() != LevelLow {
+		t.Errorf("expected low, got %s", m.Level())
+	}
+	set(11.2)
+
+	expected := []Level{LevelLow, LevelCritical, LevelLow, LevelOK}
+	if len(levels) != len(expected) {
+		t.Fatalf("expected levels %v, got %v", expected, levels)
+	}
+	for i := range expected {
+		if levels[i] != expected[i] {
+			t.Errorf("expected levels %v, got %v", expected, levels)
+		}
+	}
+}
+
+// TestConfig_Validate 测试 Config.Validate
+func TestConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config Config
+		valid  bool
+	}{
+		{name: "ok", config: Config{Divider: 5, Full: 12.6, Empty: 10.5, Low: 11, Critical: 10}, valid: true},
+		{name: "no-thresholds", config: Config{Divider: 1, Full: 3.3}, valid: true},
+		{name: "no-divider", config: Config{Full: 12.6, Empty: 10.5}},
+		{name: "full-below-empty", config: Config{Divider: 5, Full: 10, Empty: 10.5}},
+		{name: "critical-above-low", config: Config{Divider: 5, Full: 12.6, Empty: 10.5, Low: 10, Critical: 11}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.config.Validate(); (err == nil) != c.valid {
+				t.Errorf("expected valid %t, got %v", c.valid, err)
+			}
+		})
+	}
+}
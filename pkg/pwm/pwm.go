@@ -0,0 +1,66 @@
+//go:build tinygo
+
+// Package pwm 提供按针脚查找 PWM 组的功能，供电机脉冲和蜂鸣器共用
+package pwm
+
+import "machine"
+
+// Group PWM 组，如 machine.PWM0
+type Group interface {
+	// Configure enables and configures this PWM.
+	Configure(config machine.PWMConfig) error
+	// Channel returns a PWM channel for the given pin. If pin does
+	// not belong to PWM peripheral ErrInvalidOutputPin error is returned.
+	// It also configures pin as PWM output.
+	Channel(pin machine.Pin) (channel uint8, err error)
+	// SetPeriod updates the period of this PWM peripheral in nanoseconds.
+	// To set a particular frequency, use the following formula:
+	//
+	//	period = 1e9 / frequency
+	//
+	// Where frequency is in hertz. If you use a period of 0, a period
+	// that works well for LEDs will be picked.
+	//
+	// SetPeriod will try not to modify TOP if possible to reach the target period.
+	// If the period is unattainable with current TOP SetPeriod will modify TOP
+	// by the bare minimum to reach the target period. It will also enable phase
+	// correct to reach periods above 130ms.
+	SetPeriod(period uint64) error
+	// Top returns the current counter top, for use in duty cycle calculation.
+	//
+	// The value returned here is hardware dependent. In general, it's best to treat
+	// it as an opaque value that can be divided by some number and passed to Set
+	// (see Set documentation for more information).
+	Top() uint32
+	// Counter returns the current counter value of the timer in this PWM
+	// peripheral. It may be useful for debugging.
+	Counter() uint32
+	// Period returns the used PWM period in nanoseconds.
+	Period() uint64
+	// SetInverting sets whether to invert the output of this channel.
+	// Without inverting, a 25% duty cycle would mean the output is high for 25% of
+	// the time and low for the rest. Inverting flips the output as if a NOT gate
+	// was placed at the output, meaning that the output would be 25% low and 75%
+	// high with a duty cycle of 25%.
+	SetInverting(channel uint8, inverting bool)
+	// Set updates the channel value. This is used to control the channel duty
+	// cycle, in other words the fraction of time the channel output is high (or low
+	// when inverted). For example, to set it to a 25% duty cycle, use:
+	//
+	//	pwm.Set(channel, pwm.Top() / 4)
+	//
+	// pwm.Set(channel, 0) will set the output to low and pwm.Set(channel,
+	// pwm.Top()) will set the output to high, assuming the output isn't inverted.
+	Set(channel uint8, value uint32)
+	// Get current level (last set by Set). Default value on initialization is 0.
+	Get(channel uint8) (value uint32)
+	// SetTop sets TOP control register. Max value is 16bit (0xffff).
+	SetTop(top uint32)
+	// SetCounter sets counter control register. Max value is 16bit (0xffff).
+	// Useful for synchronising two different PWM peripherals.
+	SetCounter(ctr uint32)
+	// Enable enables or disables PWM peripheral channels.
+	Enable(enable bool)
+	// IsEnabled returns true if peripheral is enabled.
+	IsEnabled() (enabled bool)
+}
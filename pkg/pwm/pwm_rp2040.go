@@ -0,0 +1,31 @@
+//go:build rp2040
+
+package pwm
+
+import (
+	"fmt"
+	"machine"
+)
+
+var groups = []Group{
+	machine.PWM0,
+	machine.PWM1,
+	machine.PWM2,
+	machine.PWM3,
+	machine.PWM4,
+	machine.PWM5,
+	machine.PWM6,
+	machine.PWM7,
+}
+
+// ForPin 返回针脚 pin 所属的 PWM 组
+func ForPin(pin machine.Pin) (Group, error) {
+	i, err := machine.PWMPeripheral(pin)
+	if err != nil {
+		return nil, err
+	}
+	if int(i) >= len(groups) {
+		return nil, fmt.Errorf("pin %d has no pwm group %d", pin, i)
+	}
+	return groups[i], nil
+}
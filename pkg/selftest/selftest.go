@@ -0,0 +1,147 @@
+package selftest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+)
+
+// Status 检查结果状态
+type Status uint8
+
+const (
+	// StatusPass 通过
+	StatusPass Status = iota
+	// StatusWarn 通过但有警告
+	StatusWarn
+	// StatusFail 失败
+	StatusFail
+	// StatusSkip 跳过
+	StatusSkip
+)
+
+// String 返回状态名
+func (s Status) String() string {
+	switch s {
+	case StatusPass:
+		return "OK"
+	case StatusWarn:
+		return "WARN"
+	case StatusFail:
+		return "FAIL"
+	case StatusSkip:
+		return "SKIP"
+	}
+	return "UNKNOWN"
+}
+
+// Result 一项检查的结果
+type Result struct {
+	// 检查项名
+	Name string
+	// 状态
+	Status Status
+	// 说明，如测量值或错误
+	Detail string
+}
+
+// String 返回 "<名字> <状态> <说明>"
+func (r Result) String() string {
+	if r.Detail == "" {
+		return r.Name + " " + r.Status.String()
+	}
+	return r.Name + " " + r.Status.String() + " " + r.Detail
+}
+
+// Pass 返回通过的结果
+func Pass(format string, args ...any) Result {
+	return Result{Status: StatusPass, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Warn 返回有警告的结果
+func Warn(format string, args ...any) Result {
+	return Result{Status: StatusWarn, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Fail 返回因 err 失败的结果
+func Fail(err error) Result {
+	return Result{Status: StatusFail, Detail: err.Error()}
+}
+
+// Skip 返回跳过的结果
+func Skip(reason string) Result {
+	return Result{Status: StatusSkip, Detail: reason}
+}
+
+// Check 一项检查
+type Check struct {
+	// 名字，尽量短以适应窄屏
+	Name string
+	// 需要操作者确认后才能执行，如会转动电机的检查
+	Confirm bool
+	// 执行检查
+	Run func() Result
+}
+
+// Run 依次执行 checks ， confirmed 为 false 时跳过需要确认的检查
+func Run(checks []Check, confirmed bool) []Result {
+	results := make([]Result, len(checks))
+	for i, c := range checks {
+		var r Result
+		if c.Confirm && !confirmed {
+			r = Skip("not confirmed")
+		} else {
+			r = c.Run()
+		}
+		r.Name = c.Name
+		results[i] = r
+	}
+	return results
+}
+
+// Count 返回状态为 status 的结果数
+func Count(results []Result, status Status) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Lines 返回适合在屏幕上显示的结果行，每项一行，不含说明
+func Lines(results []Result) []string {
+	lines := make([]string, len(results))
+	for i, r := range results {
+		lines[i] = r.Name + " " + r.Status.String()
+	}
+	return lines
+}
+
+// Stuck 每隔 interval 读取 samples 次 read ，始终为 active 时失败，用于检查按键卡住或针脚短路
+func Stuck(read func() bool, active bool, samples int, interval time.Duration) Result {
+	for i := 0; i < samples; i++ {
+		if read() != active {
+			return Pass("")
+		}
+		if i < samples-1 {
+			time.Sleep(interval)
+		}
+	}
+	return Fail(errors.New("stuck"))
+}
+
+// Region 检查存储区域中保存的数据能否通过校验，没有保存数据时通过
+func Region(region *storage.Region) Result {
+	data, err := region.Load()
+	switch {
+	case errors.Is(err, storage.ErrEmpty):
+		return Pass("empty")
+	case err != nil:
+		return Fail(err)
+	}
+	return Pass("%dB", len(data))
+}
@@ -0,0 +1,79 @@
+package selftest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage/storagetest"
+)
+
+// TestRun 测试 Run 执行检查并跳过需要确认的检查
+func TestRun(t *testing.T) {
+	jogged := false
+	checks := []Check{
+		{Name: "disp", Run: func() Result { return Pass("0x%02x", 0x3c) }},
+		{Name: "supply", Run: func() Result { return Warn("%.1fV", 10.8) }},
+		{Name: "flash", Run: func() Result { return Fail(errors.New("corrupted")) }},
+		{Name: "motor", Confirm: true, Run: func() Result {
+			jogged = true
+			return Pass("")
+		}},
+	}
+
+	results := Run(checks, false)
+	if jogged {
+		t.Errorf("unconfirmed check executed")
+	}
+	expected := []string{"disp OK 0x3c", "supply WARN 10.8V", "flash FAIL corrupted", "motor SKIP not confirmed"}
+	for i, r := range results {
+		if r.String() != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], r.String())
+		}
+	}
+	if !reflect.DeepEqual(Lines(results), []string{"disp OK", "supply WARN", "flash FAIL", "motor SKIP"}) {
+		t.Errorf("unexpected lines %v", Lines(results))
+	}
+	if Count(results, StatusFail) != 1 || Count(results, StatusPass) != 1 {
+		t.Errorf("unexpected counts")
+	}
+
+	results = Run(checks, true)
+	if !jogged || results[3].String() != "motor OK" {
+		t.Errorf("expected confirmed check executed, got %v", results[3])
+	}
+}
+
+// TestStuck 测试 Stuck
+func TestStuck(t *testing.T) {
+	if r := Stuck(func() bool { return false }, false, 5, 0); r.Status != StatusFail {
+		t.Errorf("expected fail, got %v", r)
+	}
+	reads := 0
+	if r := Stuck(func() bool {
+		reads++
+		return reads > 2
+	}, false, 5, 0); r.Status != StatusPass || reads != 3 {
+		t.Errorf("expected pass after 3 reads, got %v after %d", r, reads)
+	}
+}
+
+// TestRegion 测试 Region
+func TestRegion(t *testing.T) {
+	dev := storagetest.NewMemDevice(4096, 4096)
+	region := &storage.Region{Device: dev, Size: 4096}
+	if r := Region(region); r.Status != StatusPass || r.Detail != "empty" {
+		t.Errorf("unexpected empty result %v", r)
+	}
+	if err := region.Save([]byte("hello")); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+	if r := Region(region); r.Status != StatusPass || r.Detail != "5B" {
+		t.Errorf("unexpected result %v", r)
+	}
+	dev.Data[13] ^= 0xff
+	if r := Region(region); r.Status != StatusFail {
+		t.Errorf("expected corrupted region to fail, got %v", r)
+	}
+}
@@ -0,0 +1,125 @@
+package shotplan
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/calibration"
+)
+
+// ErrNoClub 没有能达到目标距离的球杆
+var ErrNoClub = errors.New("no calibrated club reaches the distance")
+
+// WindDirection 风向，相对击球方向
+type WindDirection uint8
+
+const (
+	// Headwind 逆风
+	Headwind WindDirection = iota
+	// Tailwind 顺风
+	Tailwind
+	// Crosswind 侧风
+	Crosswind
+)
+
+// WindDirections 所有风向
+var WindDirections = []WindDirection{Headwind, Tailwind, Crosswind}
+
+// String 返回风向的字符串表示
+func (d WindDirection) String() string {
+	switch d {
+	case Headwind:
+		return "Head"
+	case Tailwind:
+		return "Tail"
+	case Crosswind:
+		return "Cross"
+	}
+	return fmt.Sprintf("WindDirection(%d)", d)
+}
+
+// Conditions 击球条件
+type Conditions struct {
+	// 风速（单位： m/s ）
+	Wind float32
+	// 风向
+	WindDirection WindDirection
+	// 目标相对击球位置的高度差（单位：米），目标更高时为正
+	Elevation float32
+}
+
+// Coefficients 距离修正系数
+type Coefficients struct {
+	// 每 1m/s 逆风需增加的距离比例
+	Headwind float32 `json:"headwind"`
+	// 每 1m/s 顺风需减少的距离比例
+	Tailwind float32 `json:"tailwind"`
+	// 每 1m/s 侧风需增加的距离比例
+	Crosswind float32 `json:"crosswind"`
+	// 每 1m 高度差需增加的距离（单位：码）
+	Elevation float32 `json:"elevation"`
+}
+
+// DefaultCoefficients 默认修正系数
+var DefaultCoefficients = Coefficients{
+	Headwind:  0.03,
+	Tailwind:  0.02,
+	Crosswind: 0.005,
+	Elevation: 1.1,
+}
+
+// EffectiveDistance 返回在 cond 条件下要达到 target 距离（单位：码）时，无风平地上等效的击球距离
+func (k *Coefficients) EffectiveDistance(target float32, cond Conditions) float32 {
+	ratio := float32(1)
+	switch cond.WindDirection {
+	case Headwind:
+		ratio += k.Headwind * cond.Wind
+	case Tailwind:
+		ratio -= k.Tailwind * cond.Wind
+	case Crosswind:
+		ratio += k.Crosswind * cond.Wind
+	}
+	// 顺风再大也不会让球反向飞
+	ratio = max(ratio, 0.1)
+	return max(target*ratio+k.Elevation*cond.Elevation, 0)
+}
+
+// Choice 选择的球杆和速度
+type Choice struct {
+	// 球杆名
+	Club string
+	// 挥杆速度百分比
+	Speed uint8
+}
+
+// Choose 从 clubs 中选择能以不超过 maxSpeed 速度达到 distance 距离的球杆，并返回所需速度
+//
+// 优先选择所需速度最高的球杆，速度越高游戏中落点越稳定；所有球杆都超过 maxSpeed 时，选择所需速度最低的球杆。
+func Choose(table *calibration.Table, clubs []string, distance float32, maxSpeed uint8) (Choice, error) {
+	var best, fallback *Choice
+	for _, club := range clubs {
+		curve := table.Lookup(club)
+		if curve == nil {
+			continue
+		}
+		speed, err := curve.Setting(distance)
+		if err != nil {
+			continue
+		}
+		c := Choice{Club: club, Speed: speed}
+		if speed <= maxSpeed {
+			if best == nil || speed > best.Speed {
+				best = &c
+			}
+		} else if fallback == nil || speed < fallback.Speed {
+			fallback = &c
+		}
+	}
+	switch {
+	case best != nil:
+		return *best, nil
+	case fallback != nil:
+		return *fallback, nil
+	}
+	return Choice{}, fmt.Errorf("%w: %.0f", ErrNoClub, distance)
+}
@@ -0,0 +1,69 @@
+package shotplan
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/calibration"
+)
+
+// TestCoefficients_EffectiveDistance 测试 Coefficients.EffectiveDistance
+func TestCoefficients_EffectiveDistance(t *testing.T) {
+	k := Coefficients{Headwind: 0.03, Tailwind: 0.02, Crosswind: 0.01, Elevation: 1}
+	cases := []struct {
+		name     string
+		cond     Conditions
+		expected float32
+	}{
+		{name: "calm", cond: Conditions{}, expected: 200},
+		{name: "headwind", cond: Conditions{Wind: 5, WindDirection: Headwind}, expected: 230},
+		{name: "tailwind", cond: Conditions{Wind: 5, WindDirection: Tailwind}, expected: 180},
+		{name: "crosswind", cond: Conditions{Wind: 5, WindDirection: Crosswind}, expected: 210},
+		{name: "uphill", cond: Conditions{Elevation: 10}, expected: 210},
+		{name: "downhill-tailwind", cond: Conditions{Wind: 5, WindDirection: Tailwind, Elevation: -20}, expected: 160},
+		{name: "extreme-tailwind", cond: Conditions{Wind: 100, WindDirection: Tailwind}, expected: 20},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := k.EffectiveDistance(200, c.cond); math.Abs(float64(got-c.expected)) > 0.01 {
+				t.Errorf("expected %f, got %f", c.expected, got)
+			}
+		})
+	}
+}
+
+// TestChoose 测试 Choose
+func TestChoose(t *testing.T) {
+	table := &calibration.Table{Curves: []calibration.Curve{
+		{Club: "Driver", Points: []calibration.Point{{Setting: 20, Distance: 80}, {Setting: 100, Distance: 240}}},
+		{Club: "5-Iron", Points: []calibration.Point{{Setting: 20, Distance: 50}, {Setting: 100, Distance: 170}}},
+		{Club: "Wedge", Points: []calibration.Point{{Setting: 20, Distance: 20}, {Setting: 100, Distance: 80}}},
+	}}
+	clubs := []string{"Driver", "Spoon", "5-Iron", "Wedge"}
+
+	cases := []struct {
+		distance float32
+		expected Choice
+	}{
+		{distance: 230, expected: Choice{Club: "Driver", Speed: 95}},
+		{distance: 150, expected: Choice{Club: "5-Iron", Speed: 87}},
+		{distance: 70, expected: Choice{Club: "Wedge", Speed: 87}},
+		// 只有超过 90% 的选择
+		{distance: 235, expected: Choice{Club: "Driver", Speed: 98}},
+	}
+	for _, c := range cases {
+		got, err := Choose(table, clubs, c.distance, 90)
+		if err != nil {
+			t.Errorf("distance %f: unexpected error: %v", c.distance, err)
+			continue
+		}
+		if got != c.expected {
+			t.Errorf("distance %f: expected %+v, got %+v", c.distance, c.expected, got)
+		}
+	}
+
+	if _, err := Choose(table, clubs, 300, 90); !errors.Is(err, ErrNoClub) {
+		t.Errorf("expected no club error, got %v", err)
+	}
+}
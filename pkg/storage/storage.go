@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// headerSize 区域头部长度：魔数、数据长度、 CRC32 校验值
+const headerSize = 12
+
+// magic 区域头部魔数
+const magic uint32 = 0x474f4c46 // "GOLF"
+
+var (
+	// ErrEmpty 区域中没有保存数据
+	ErrEmpty = errors.New("storage region is empty")
+	// ErrCorrupted 区域中的数据校验失败
+	ErrCorrupted = errors.New("storage region is corrupted")
+	// ErrTooLarge 数据超过区域大小
+	ErrTooLarge = errors.New("data too large for storage region")
+	// ErrUnavailable 区域未能分配
+	ErrUnavailable = errors.New("storage region unavailable")
+)
+
+// BlockDevice 块存储设备，与 TinyGo machine.Flash 的方法一致
+type BlockDevice interface {
+	// ReadAt 从 off 处读取数据
+	ReadAt(p []byte, off int64) (n int, err error)
+	// WriteAt 向 off 处写入数据，写入前需先擦除
+	WriteAt(p []byte, off int64) (n int, err error)
+	// Size 返回设备大小
+	Size() int64
+	// WriteBlockSize 返回写入块大小
+	WriteBlockSize() int64
+	// EraseBlockSize 返回擦除块大小
+	EraseBlockSize() int64
+	// EraseBlocks 从第 start 个擦除块开始擦除 len 个擦除块
+	EraseBlocks(start, len int64) error
+}
+
+// Region 设备上的一段存储区域，保存一份带校验的数据
+//
+// 分配失败时可使用 nil 区域，读写都返回 ErrUnavailable ，调用方按未保存数据处理。
+type Region struct {
+	// 存储设备
+	Device BlockDevice
+	// 区域起始偏移，需对齐擦除块
+	Offset int64
+	// 区域大小，需为擦除块大小的整数倍
+	Size int64
+}
+
+// Load 读取区域中保存的数据
+func (r *Region) Load() ([]byte, error) {
+	if r == nil {
+		return nil, ErrUnavailable
+	}
+	header := make([]byte, headerSize)
+	if _, err := r.Device.ReadAt(header, r.Offset); err != nil {
+		return nil, fmt.Errorf("read header error: %w", err)
+	}
+	if binary.LittleEndian.Uint32(header[0:4]) != magic {
+		return nil, ErrEmpty
+	}
+	n := int64(binary.LittleEndian.Uint32(header[4:8]))
+	if n > r.Size-headerSize {
+		return nil, ErrCorrupted
+	}
+	data := make([]byte, n)
+	if _, err := r.Device.ReadAt(data, r.Offset+headerSize); err != nil {
+		return nil, fmt.Errorf("read data error: %w", err)
+	}
+	if crc32.ChecksumIEEE(data) != binary.LittleEndian.Uint32(header[8:12]) {
+		return nil, ErrCorrupted
+	}
+	return data, nil
+}
+
+// Save 擦除区域并保存数据
+func (r *Region) Save(data []byte) error {
+	if r == nil {
+		return ErrUnavailable
+	}
+	if int64(len(data)) > r.Size-headerSize {
+		return ErrTooLarge
+	}
+
+	// 按写入块对齐
+	buf := make([]byte, headerSize+len(data))
+	binary.LittleEndian.PutUint32(buf[0:4], magic)
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(len(data)))
+	binary.LittleEndian.PutUint32(buf[8:12], crc32.ChecksumIEEE(data))
+	copy(buf[headerSize:], data)
+	if wbs := r.Device.WriteBlockSize(); wbs > 0 {
+		if rem := int64(len(buf)) % wbs; rem != 0 {
+			padding := make([]byte, wbs-rem)
+			for i := range padding {
+				padding[i] = 0xff
+			}
+			buf = append(buf, padding...)
+		}
+	}
+
+	ebs := r.Device.EraseBlockSize()
+	if err := r.Device.EraseBlocks(r.Offset/ebs, (r.Size+ebs-1)/ebs); err != nil {
+		return fmt.Errorf("erase error: %w", err)
+	}
+	if _, err := r.Device.WriteAt(buf, r.Offset); err != nil {
+		return fmt.Errorf("write error: %w", err)
+	}
+	return nil
+}
+
+// Erase 擦除区域
+func (r *Region) Erase() error {
+	if r == nil {
+		return ErrUnavailable
+	}
+	ebs := r.Device.EraseBlockSize()
+	return r.Device.EraseBlocks(r.Offset/ebs, (r.Size+ebs-1)/ebs)
+}
+
+// Allocator 在设备上分配存储区域
+//
+// 区域从设备开头依次分配，分配顺序变化会导致已保存的数据错位，新区域只应追加在最后。
+type Allocator struct {
+	Device BlockDevice
+
+	next int64
+}
+
+// Allocate 分配至少 size 字节的区域，按擦除块对齐
+func (a *Allocator) Allocate(size int64) (*Region, error) {
+	ebs := a.Device.EraseBlockSize()
+	size = (size + ebs - 1) / ebs * ebs
+	if a.next+size > a.Device.Size() {
+		return nil, fmt.Errorf("no space for %d bytes, %d bytes left", size, a.Device.Size()-a.next)
+	}
+	r := &Region{Device: a.Device, Offset: a.next, Size: size}
+	a.next += size
+	return r, nil
+}
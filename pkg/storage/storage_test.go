@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/storage/storagetest"
+)
+
+var _ BlockDevice = (*storagetest.MemDevice)(nil)
+
+// TestRegion 测试 Region 保存和读取
+func TestRegion(t *testing.T) {
+	dev := storagetest.NewMemDevice(4*4096, 4096)
+	alloc := &Allocator{Device: dev}
+	a, err := alloc.Allocate(100)
+	if err != nil {
+		t.Fatalf("allocate error: %v", err)
+	}
+	b, err := alloc.Allocate(5000)
+	if err != nil {
+		t.Fatalf("allocate error: %v", err)
+	}
+	if a.Offset != 0 || a.Size != 4096 || b.Offset != 4096 || b.Size != 8192 {
+		t.Errorf("unexpected regions: %+v %+v", a, b)
+	}
+	c, err := alloc.Allocate(8192)
+	if err == nil {
+		t.Errorf("expected no space error")
+	}
+	// 分配失败的区域不可读写
+	if _, err := c.Load(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected unavailable error, got %v", err)
+	}
+	if err := c.Save([]byte("hello")); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected unavailable error, got %v", err)
+	}
+
+	if _, err := a.Load(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("expected empty error, got %v", err)
+	}
+	for _, data := range [][]byte{[]byte("hello"), []byte("world!!"), {}} {
+		if err := a.Save(data); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+		got, err := a.Load()
+		if err != nil {
+			t.Fatalf("load error: %v", err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("expected %q, got %q", data, got)
+		}
+	}
+	if _, err := b.Load(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("expected other region empty, got %v", err)
+	}
+
+	// 损坏数据
+	if err := a.Save([]byte("hello")); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+	dev.Data[headerSize] = 'j'
+	if _, err := a.Load(); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("expected corrupted error, got %v", err)
+	}
+
+	if err := a.Save(make([]byte, 4096)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected too large error, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Package storagetest 提供测试 storage 及其使用者的工具
+package storagetest
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// WriteBlockSize MemDevice 的写入块大小
+const WriteBlockSize = 256
+
+// NewMemDevice 创建 size 字节、擦除块大小为 eraseBlockSize 的已擦除 *MemDevice
+func NewMemDevice(size int, eraseBlockSize int64) *MemDevice {
+	return &MemDevice{
+		Data:       bytes.Repeat([]byte{0xff}, size),
+		EraseBlock: eraseBlockSize,
+	}
+}
+
+// MemDevice 内存中的 storage.BlockDevice ，模拟 NOR Flash 只能将位从 1 写为 0
+type MemDevice struct {
+	// 设备内容，测试中可直接修改以模拟损坏
+	Data []byte
+	// 擦除块大小
+	EraseBlock int64
+}
+
+// ReadAt 读取 off 开始的数据
+func (d *MemDevice) ReadAt(p []byte, off int64) (int, error) {
+	return copy(p, d.Data[off:]), nil
+}
+
+// WriteAt 按写入块对齐写入，只能将位从 1 写为 0
+func (d *MemDevice) WriteAt(p []byte, off int64) (int, error) {
+	if off%WriteBlockSize != 0 || int64(len(p))%WriteBlockSize != 0 {
+		return 0, fmt.Errorf("unaligned write at %d len %d", off, len(p))
+	}
+	for i, b := range p {
+		d.Data[off+int64(i)] &= b
+	}
+	return len(p), nil
+}
+
+// Size 返回设备大小
+func (d *MemDevice) Size() int64 { return int64(len(d.Data)) }
+
+// WriteBlockSize 返回写入块大小
+func (d *MemDevice) WriteBlockSize() int64 { return WriteBlockSize }
+
+// EraseBlockSize 返回擦除块大小
+func (d *MemDevice) EraseBlockSize() int64 { return d.EraseBlock }
+
+// EraseBlocks 将从第 start 个擦除块开始的 n 个块写为 0xff
+func (d *MemDevice) EraseBlocks(start, n int64) error {
+	for i := start * d.EraseBlock; i < (start+n)*d.EraseBlock; i++ {
+		d.Data[i] = 0xff
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package textscreen
+
+import (
+	"image/color"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"tinygo.org/x/tinyfont/proggy"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/memdisplay"
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/memdisplay/memdisplaytest"
+)
+
+var white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+// TestWrapText 测试 WrapText
+func TestWrapText(t *testing.T) {
+	font := &proggy.TinySZ8pt7b
+	cases := []struct {
+		name     string
+		str      string
+		maxWidth int16
+		expected []string
+	}{
+		{name: "empty", str: "", maxWidth: 40, expected: []string{""}},
+		{name: "fits", str: "Driver", maxWidth: 128, expected: []string{"Driver"}},
+		{name: "newlines", str: "a\n\nb", maxWidth: 128, expected: []string{"a", "", "b"}},
+		{name: "wrap", str: "abcdefghijkl", maxWidth: 30, expected: []string{"abcde", "fghij", "kl"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := WrapText(font, c.str, c.maxWidth)
+			if !reflect.DeepEqual(got, c.expected) {
+				t.Errorf("expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+// TestWriteLines 测试 WriteLines
+func TestWriteLines(t *testing.T) {
+	cases := []struct {
+		name      string
+		str       string
+		maxWidth  int16
+		lineSpace int16
+	}{
+		{name: "single", str: "Hello", maxWidth: 128},
+		{name: "multi", str: "Hello\nGolf", maxWidth: 128, lineSpace: 1},
+		{name: "wrap", str: "abcdefghijkl", maxWidth: 30},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := memdisplay.New(64, 32)
+			WriteLines(d, &proggy.TinySZ8pt7b, 0, 0, c.maxWidth, c.lineSpace, c.str, white)
+			memdisplaytest.CheckGolden(t, d, filepath.Join("testdata", "write-lines-"+c.name+".txt"))
+		})
+	}
+}
@@ -0,0 +1,95 @@
+//go:build tinygo
+
+package usbhid
+
+import (
+	"machine"
+	"machine/usb/hid"
+	"runtime/interrupt"
+
+	"github.com/yhlooo/ns-sports-golf-clubs/pkg/golfclubs"
+)
+
+// Port USB HID 键盘接口
+//
+// 导入本包时注册，USB 枚举为 CDC 串口和 HID 键盘的复合设备，串口照常可用。
+var Port = &Device{}
+
+func init() {
+	hid.SetHandler(Port)
+}
+
+// Device USB HID 键盘接口
+//
+// 运动开始和结束时敲击 SwingStartKey 、 SwingDoneKey 或 SwingFaultKey ，主机端工具据此获取挥杆状态；
+// 并记录主机通过输出报告置位的指示灯，用于触发挥杆。
+// TxHandler 和 RxHandler 在 USB 中断中调用，其它方法访问共享状态时关闭中断。
+type Device struct {
+	queue   Queue
+	edges   Edges
+	sending bool
+}
+
+var _ golfclubs.MotionObserver = (*Device)(nil)
+
+// TxHandler 上一个输入报告发送完成时在中断中调用，发送下一个报告
+func (d *Device) TxHandler() bool {
+	report, ok := d.queue.Next()
+	if !ok {
+		d.sending = false
+		return false
+	}
+	hid.SendUSBPacket(report)
+	return true
+}
+
+// RxHandler 收到输出报告时在中断中调用
+func (d *Device) RxHandler(b []byte) bool {
+	leds, ok := ParseLEDReport(b)
+	if !ok {
+		return false
+	}
+	d.edges.Update(leds)
+	return true
+}
+
+// Tap 敲击 key ，没有连接主机时丢弃
+func (d *Device) Tap(key Key) {
+	state := interrupt.Disable()
+	defer interrupt.Restore(state)
+	if !machine.USBDev.InitEndpointComplete {
+		// 未连接主机或未完成枚举（如脱离电脑单独使用），不会有发送完成中断
+		d.queue.Clear()
+		d.sending = false
+		return
+	}
+	if !d.queue.Tap(key) {
+		// 队列已满说明发送完成中断丢失（如主机断开后重新枚举），丢弃积压的报告重新开始
+		d.queue.Clear()
+		d.sending = false
+		d.queue.Tap(key)
+	}
+	if d.sending {
+		return
+	}
+	report, _ := d.queue.Next()
+	d.sending = true
+	hid.SendUSBPacket(report)
+}
+
+// Triggered 返回主机是否置位过 led ，并清除记录
+func (d *Device) Triggered(led LED) bool {
+	state := interrupt.Disable()
+	defer interrupt.Restore(state)
+	return d.edges.Take(led)
+}
+
+// MotionStarted 开始运动时敲击 SwingStartKey
+func (d *Device) MotionStarted(_ []golfclubs.Segment, _ uint32) {
+	d.Tap(SwingStartKey)
+}
+
+// MotionFinished 运动结束时根据 err 敲击 SwingDoneKey 或 SwingFaultKey
+func (d *Device) MotionFinished(err error) {
+	d.Tap(FinishKey(err))
+}
@@ -0,0 +1,176 @@
+package usbhid
+
+import "errors"
+
+const (
+	// ReportIDKeyboard 键盘报告的报告 ID ，与 TinyGo 的 CDC+HID 复合设备报告描述符一致
+	ReportIDKeyboard = 0x02
+	// KeyboardReportSize 键盘输入报告长度：报告 ID 、修饰键、保留字节和 6 个按键
+	KeyboardReportSize = 9
+	// maxKeys 一个键盘输入报告中最多同时按下的按键数
+	maxKeys = 6
+	// queueSize 待发送的输入报告队列容量，每次敲击占两个报告
+	queueSize = 16
+)
+
+// Key HID 键盘用法码
+type Key uint8
+
+const (
+	// KeyF16 F16 键
+	KeyF16 Key = 0x6b
+	// KeyF17 F17 键
+	KeyF17 Key = 0x6c
+	// KeyF18 F18 键
+	KeyF18 Key = 0x6d
+)
+
+// 挥杆事件对应的按键
+//
+// 普通键盘没有 F16 ~ F18 ，macOS 、 Windows 和常见 Linux 桌面默认都不为其绑定动作，不会干扰主机上的其它程序。
+// 不使用 F13 ~ F15 ： macOS 默认将 F14 和 F15 作为屏幕亮度键。
+const (
+	// SwingStartKey 开始运动时敲击的键
+	SwingStartKey = KeyF16
+	// SwingDoneKey 运动正常结束时敲击的键
+	SwingDoneKey = KeyF17
+	// SwingFaultKey 运动出错结束时敲击的键
+	SwingFaultKey = KeyF18
+)
+
+// FinishKey 返回运动结束时敲击的键， err 为运动返回的错误
+func FinishKey(err error) Key {
+	if err != nil {
+		return SwingFaultKey
+	}
+	return SwingDoneKey
+}
+
+// ErrTooManyKeys 同时按下的按键超过 6 个
+var ErrTooManyKeys = errors.New("too many keys")
+
+// KeyboardReport 返回按下 keys 的键盘输入报告， keys 为空时表示松开所有按键
+func KeyboardReport(keys ...Key) ([KeyboardReportSize]byte, error) {
+	var report [KeyboardReportSize]byte
+	if len(keys) > maxKeys {
+		return report, ErrTooManyKeys
+	}
+	report[0] = ReportIDKeyboard
+	for i, k := range keys {
+		report[3+i] = byte(k)
+	}
+	return report, nil
+}
+
+// LED 键盘指示灯输出报告中的位
+//
+// 主机端工具可以通过写输出报告置位 LEDCompose 或 LEDKana 触发动作，不需要串口驱动。
+// 这两个指示灯很少被操作系统同步到所有键盘，不会因用户按下大写锁定等键误触发。
+type LED uint8
+
+const (
+	// LEDNumLock 数字锁定
+	LEDNumLock LED = 1 << iota
+	// LEDCapsLock 大写锁定
+	LEDCapsLock
+	// LEDScrollLock 滚动锁定
+	LEDScrollLock
+	// LEDCompose Compose
+	LEDCompose
+	// LEDKana Kana
+	LEDKana
+)
+
+// String 返回指示灯名
+func (l LED) String() string {
+	switch l {
+	case LEDNumLock:
+		return "num-lock"
+	case LEDCapsLock:
+		return "caps-lock"
+	case LEDScrollLock:
+		return "scroll-lock"
+	case LEDCompose:
+		return "compose"
+	case LEDKana:
+		return "kana"
+	}
+	return "unknown"
+}
+
+// ParseLEDReport 解析键盘指示灯输出报告，不是键盘报告时返回 false
+func ParseLEDReport(b []byte) (LED, bool) {
+	if len(b) < 2 || b[0] != ReportIDKeyboard {
+		return 0, false
+	}
+	return LED(b[1]), true
+}
+
+// Edges 记录输出报告中指示灯位的上升沿，由主机置位指示灯触发动作
+type Edges struct {
+	last    LED
+	pending LED
+}
+
+// Update 收到输出报告时调用，记录新置位的指示灯
+func (e *Edges) Update(leds LED) {
+	e.pending |= leds &^ e.last
+	e.last = leds
+}
+
+// Take 返回 led 是否有未处理的上升沿，并清除记录
+func (e *Edges) Take(led LED) bool {
+	if e.pending&led == 0 {
+		return false
+	}
+	e.pending &^= led
+	return true
+}
+
+// Queue 待发送的键盘输入报告队列，容量固定，不分配内存，可在中断中使用
+type Queue struct {
+	reports [queueSize][KeyboardReportSize]byte
+	head    int
+	n       int
+}
+
+// Tap 添加按下并松开 key 的两个报告，队列已满时丢弃并返回 false
+func (q *Queue) Tap(key Key) bool {
+	if q.n+2 > len(q.reports) {
+		return false
+	}
+	press, _ := KeyboardReport(key)
+	release, _ := KeyboardReport()
+	q.push(press)
+	q.push(release)
+	return true
+}
+
+// push 添加一个报告，调用前需确认队列未满
+func (q *Queue) push(report [KeyboardReportSize]byte) {
+	q.reports[(q.head+q.n)%len(q.reports)] = report
+	q.n++
+}
+
+// Next 取出下一个待发送的报告，队列为空时返回 false
+// 返回的切片指向队列内部，下次调用 Tap 后可能被覆盖，需立即发送
+func (q *Queue) Next() ([]byte, bool) {
+	if q.n == 0 {
+		return nil, false
+	}
+	report := q.reports[q.head][:]
+	q.head = (q.head + 1) % len(q.reports)
+	q.n--
+	return report, true
+}
+
+// Clear 丢弃所有待发送的报告
+func (q *Queue) Clear() {
+	q.head = 0
+	q.n = 0
+}
+
+// Len 返回待发送的报告数
+func (q *Queue) Len() int {
+	return q.n
+}
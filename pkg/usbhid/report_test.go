@@ -0,0 +1,129 @@
+package usbhid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestKeyboardReport 测试 KeyboardReport
+func TestKeyboardReport(t *testing.T) {
+	report, err := KeyboardReport(KeyF16, KeyF18)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [KeyboardReportSize]byte{ReportIDKeyboard, 0, 0, 0x6b, 0x6d, 0, 0, 0, 0}
+	if report != expected {
+		t.Errorf("expected %x, got %x", expected, report)
+	}
+
+	report, _ = KeyboardReport()
+	if report != [KeyboardReportSize]byte{ReportIDKeyboard} {
+		t.Errorf("unexpected release report: %x", report)
+	}
+
+	if _, err := KeyboardReport(1, 2, 3, 4, 5, 6, 7); !errors.Is(err, ErrTooManyKeys) {
+		t.Errorf("expected ErrTooManyKeys, got %v", err)
+	}
+}
+
+// TestFinishKey 测试 FinishKey
+func TestFinishKey(t *testing.T) {
+	if k := FinishKey(nil); k != SwingDoneKey {
+		t.Errorf("expected done key, got %x", k)
+	}
+	if k := FinishKey(errors.New("stalled")); k != SwingFaultKey {
+		t.Errorf("expected fault key, got %x", k)
+	}
+}
+
+// TestEdges 测试 ParseLEDReport 和 Edges 只在指示灯置位时触发一次
+func TestEdges(t *testing.T) {
+	if _, ok := ParseLEDReport([]byte{0x01, 0xff}); ok {
+		t.Errorf("expected non-keyboard report to be ignored")
+	}
+	if _, ok := ParseLEDReport([]byte{ReportIDKeyboard}); ok {
+		t.Errorf("expected short report to be ignored")
+	}
+
+	e := &Edges{}
+	update := func(b ...byte) {
+		t.Helper()
+		leds, ok := ParseLEDReport(b)
+		if !ok {
+			t.Fatalf("parse %x failed", b)
+		}
+		e.Update(leds)
+	}
+
+	update(ReportIDKeyboard, byte(LEDNumLock))
+	if e.Take(LEDCompose) {
+		t.Errorf("unexpected compose trigger")
+	}
+	update(ReportIDKeyboard, byte(LEDNumLock|LEDCompose))
+	// 保持置位不会再次触发
+	update(ReportIDKeyboard, byte(LEDNumLock|LEDCompose))
+	if !e.Take(LEDCompose) {
+		t.Errorf("expected compose trigger")
+	}
+	if e.Take(LEDCompose) {
+		t.Errorf("expected compose trigger to be taken once")
+	}
+	// 清除后再次置位
+	update(ReportIDKeyboard, 0)
+	update(ReportIDKeyboard, byte(LEDCompose|LEDKana))
+	if !e.Take(LEDKana) || !e.Take(LEDCompose) {
+		t.Errorf("expected both triggers")
+	}
+}
+
+// TestQueue 测试 Queue 按顺序发送并在满时丢弃
+func TestQueue(t *testing.T) {
+	q := &Queue{}
+	if _, ok := q.Next(); ok {
+		t.Fatalf("expected empty queue")
+	}
+	for i := 0; i < queueSize/2; i++ {
+		if !q.Tap(SwingStartKey) {
+			t.Fatalf("tap %d dropped", i)
+		}
+	}
+	if q.Tap(SwingDoneKey) {
+		t.Errorf("expected tap to be dropped when full")
+	}
+
+	// 取出一次敲击后可以继续添加
+	press, _ := KeyboardReport(SwingStartKey)
+	release, _ := KeyboardReport()
+	for _, expected := range [][KeyboardReportSize]byte{press, release} {
+		report, ok := q.Next()
+		if !ok || !bytes.Equal(report, expected[:]) {
+			t.Fatalf("expected %x, got %x", expected, report)
+		}
+	}
+	if !q.Tap(SwingDoneKey) {
+		t.Fatalf("expected tap after next")
+	}
+	if q.Len() != queueSize {
+		t.Errorf("expected %d reports, got %d", queueSize, q.Len())
+	}
+
+	var reports [][]byte
+	for {
+		report, ok := q.Next()
+		if !ok {
+			break
+		}
+		reports = append(reports, append([]byte(nil), report...))
+	}
+	done, _ := KeyboardReport(SwingDoneKey)
+	if n := len(reports); n != queueSize || !bytes.Equal(reports[n-2], done[:]) || !bytes.Equal(reports[n-1], release[:]) {
+		t.Errorf("expected done key tapped last, got %x", reports)
+	}
+
+	q.Tap(SwingStartKey)
+	q.Clear()
+	if _, ok := q.Next(); ok || q.Len() != 0 {
+		t.Errorf("expected empty queue after clear")
+	}
+}